@@ -4,10 +4,14 @@ go 1.19
 
 require (
 	github.com/btcsuite/btcutil v1.0.2
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.2.0
+	github.com/fxamacker/cbor/v2 v2.5.0
 	github.com/google/uuid v1.3.0
 	github.com/piprate/json-gold v0.5.0
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
 	github.com/sirupsen/logrus v1.9.0
 	github.com/stretchr/testify v1.8.1
+	github.com/suutaku/bls12381 v0.0.0-20220909105542-17195eab9a7d
 	github.com/suutaku/go-bbs v0.0.0-20230128100940-bbf42a26767b
 )
 
@@ -18,7 +22,7 @@ require (
 	github.com/kr/text v0.2.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/pquerna/cachecontrol v0.0.0-20180517163645-1555304b9b35 // indirect
-	github.com/suutaku/bls12381 v0.0.0-20220909105542-17195eab9a7d // indirect
+	github.com/x448/float16 v0.8.4 // indirect
 	golang.org/x/crypto v0.0.0-20220829220503-c86fa9a7ed90 // indirect
 	golang.org/x/sys v0.0.0-20220908164124-27713097b956 // indirect
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+const (
+	// MultibaseBase58BTC is the multibase prefix of base58-btc encoding
+	MultibaseBase58BTC = 'z'
+	// MultibaseBase64URL is the multibase prefix of base64url (no padding) encoding
+	MultibaseBase64URL = 'u'
+)
+
+// EncodeMultibase encode data with the encoding identified by prefix
+func EncodeMultibase(prefix byte, data []byte) (string, error) {
+	switch prefix {
+	case MultibaseBase58BTC:
+		return string(prefix) + base58.Encode(data), nil
+	case MultibaseBase64URL:
+		return string(prefix) + base64.RawURLEncoding.EncodeToString(data), nil
+	}
+	return "", fmt.Errorf("unsupported multibase prefix %q", prefix)
+}
+
+// DecodeMultibase decode a multibase string
+func DecodeMultibase(value string) ([]byte, error) {
+	if len(value) < 2 {
+		return nil, fmt.Errorf("invalid multibase value")
+	}
+	switch value[0] {
+	case MultibaseBase58BTC:
+		ret := base58.Decode(value[1:])
+		if len(ret) == 0 {
+			return nil, fmt.Errorf("invalid base58-btc value")
+		}
+		return ret, nil
+	case MultibaseBase64URL:
+		return base64.RawURLEncoding.DecodeString(value[1:])
+	}
+	return nil, fmt.Errorf("unsupported multibase prefix %q", value[0])
+}
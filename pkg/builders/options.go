@@ -1,12 +1,23 @@
 package builders
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"time"
+
 	"github.com/suutaku/go-bbs/pkg/bbs"
+	"github.com/suutaku/go-vc/pkg/credential"
 	"github.com/suutaku/go-vc/pkg/processor"
 	"github.com/suutaku/go-vc/pkg/proof"
+	"github.com/suutaku/go-vc/pkg/status"
 	"github.com/suutaku/go-vc/pkg/suite"
+	"github.com/suutaku/go-vc/pkg/suite/bbs2023"
 	"github.com/suutaku/go-vc/pkg/suite/bbsblssignature2020"
 	"github.com/suutaku/go-vc/pkg/suite/bbsblssignatureproof2020"
+	"github.com/suutaku/go-vc/pkg/suite/ecdsardfc2019"
+	"github.com/suutaku/go-vc/pkg/suite/ed25519signature2020"
+	"github.com/suutaku/go-vc/pkg/suite/jsonwebsignature2020"
 )
 
 type builderOption struct {
@@ -14,6 +25,14 @@ type builderOption struct {
 	signatureSuites map[string]suite.SignatureSuite
 	processorOpts   []processor.ProcessorOpts
 	ldpCtx          *proof.LinkedDataProofContext
+	clock           func() time.Time
+	skew            time.Duration
+	// validate credentials before signing
+	validate  bool
+	dataModel credential.DataModel
+	// credential templates by name and status list managers by purpose
+	templates      map[string]*Template
+	statusManagers map[string]*status.StatusListManager
 }
 
 type BuilderOption func(opts *builderOption)
@@ -28,7 +47,7 @@ func WithSignatureSuites(suites ...suite.SignatureSuite) BuilderOption {
 	}
 }
 
-// WithPrivateKey option will create both BbsBlsSignature2020 and BbsBlsSignatureProof2020 suite with compacted proof disabled automatically
+// WithPrivateKey option will create BbsBlsSignature2020, BbsBlsSignatureProof2020 and bbs-2023 suites with compacted proof disabled automatically
 func WithPrivateKey(priv *bbs.PrivateKey) BuilderOption {
 	return func(opts *builderOption) {
 		opts.signatureSuites = make(map[string]suite.SignatureSuite)
@@ -36,6 +55,43 @@ func WithPrivateKey(priv *bbs.PrivateKey) BuilderOption {
 		opts.signatureSuites[bbss.Alg()] = bbss
 		bbsps := bbsblssignatureproof2020.NewSignatureSuite(priv, false)
 		opts.signatureSuites[bbsps.Alg()] = bbsps
+		bbs23 := bbs2023.NewSignatureSuite(priv, false)
+		opts.signatureSuites[bbs23.Alg()] = bbs23
+	}
+}
+
+// WithEd25519PrivateKey option will add Ed25519Signature2020 suite to the suites already configured
+func WithEd25519PrivateKey(priv ed25519.PrivateKey) BuilderOption {
+	return func(opts *builderOption) {
+		if opts.signatureSuites == nil {
+			opts.signatureSuites = make(map[string]suite.SignatureSuite)
+		}
+		eds := ed25519signature2020.NewSignatureSuite(priv, false)
+		opts.signatureSuites[eds.Alg()] = eds
+	}
+}
+
+// WithECDSAPrivateKey option will add ecdsa-rdfc-2019 suite (P-256 or P-384 key) to the suites already configured
+func WithECDSAPrivateKey(priv *ecdsa.PrivateKey) BuilderOption {
+	return func(opts *builderOption) {
+		if opts.signatureSuites == nil {
+			opts.signatureSuites = make(map[string]suite.SignatureSuite)
+		}
+		ecs := ecdsardfc2019.NewSignatureSuite(priv, false)
+		opts.signatureSuites[ecs.Alg()] = ecs
+	}
+}
+
+// WithJWSPrivateKey option will add JsonWebSignature2020 suite to the suites already configured, the JWS alg
+// is chosen from the key: ES256/ES384 (*ecdsa.PrivateKey), ES256K (*secp256k1.PrivateKey), EdDSA (ed25519.PrivateKey)
+// or PS256 (*rsa.PrivateKey)
+func WithJWSPrivateKey(priv crypto.PrivateKey) BuilderOption {
+	return func(opts *builderOption) {
+		if opts.signatureSuites == nil {
+			opts.signatureSuites = make(map[string]suite.SignatureSuite)
+		}
+		jws := jsonwebsignature2020.NewSignatureSuite(priv, false)
+		opts.signatureSuites[jws.Alg()] = jws
 	}
 }
 
@@ -53,6 +109,54 @@ func WithLinkedDataProofContext(ldpCtx *proof.LinkedDataProofContext) BuilderOpt
 	}
 }
 
+// WithClock option sets the current time of credential validity period checks, time.Now by default
+func WithClock(clock func() time.Time) BuilderOption {
+	return func(opts *builderOption) {
+		opts.clock = clock
+	}
+}
+
+// WithClockSkew option sets the tolerated clock difference with issuers for validity period checks
+func WithClockSkew(skew time.Duration) BuilderOption {
+	return func(opts *builderOption) {
+		opts.skew = skew
+	}
+}
+
+// WithDataModelValidation option validates credentials against data model version before AddLinkedDataProof,
+// the version of each credential is used when version is empty
+func WithDataModelValidation(version credential.DataModel) BuilderOption {
+	return func(opts *builderOption) {
+		opts.validate = true
+		opts.dataModel = version
+	}
+}
+
+// WithTemplates option registers credential templates for IssueFromTemplate
+func WithTemplates(templates ...*Template) BuilderOption {
+	return func(opts *builderOption) {
+		if opts.templates == nil {
+			opts.templates = make(map[string]*Template)
+		}
+		for _, t := range templates {
+			opts.templates[t.Name] = t
+		}
+	}
+}
+
+// WithStatusListManagers option sets the managers allocating the status entries of issued credentials,
+// one for each status purpose
+func WithStatusListManagers(managers ...*status.StatusListManager) BuilderOption {
+	return func(opts *builderOption) {
+		if opts.statusManagers == nil {
+			opts.statusManagers = make(map[string]*status.StatusListManager)
+		}
+		for _, m := range managers {
+			opts.statusManagers[m.Purpose()] = m
+		}
+	}
+}
+
 func WithDID(did string) BuilderOption {
 	return func(opts *builderOption) {
 		opts.did = did
@@ -65,6 +169,7 @@ func prepareOpts(opts []BuilderOption) *builderOption {
 		processorOpts: []processor.ProcessorOpts{
 			processor.WithValidateRDF(),
 		},
+		clock: time.Now,
 	}
 	for _, opt := range opts {
 		opt(procOpts)
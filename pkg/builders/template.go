@@ -0,0 +1,170 @@
+package builders
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/suutaku/go-vc/pkg/common"
+	"github.com/suutaku/go-vc/pkg/credential"
+	"github.com/suutaku/go-vc/pkg/status"
+)
+
+// Template describes a type of credential issued by IssueFromTemplate
+type Template struct {
+	Name string
+	// Contexts of issued credentials, the VC 2.0 context when empty. Credentials with the VC 1.1 context
+	// use issuanceDate and expirationDate instead of validFrom and validUntil.
+	Contexts credential.Contexts
+	// Types of issued credentials, VerifiableCredential is added when missing
+	Types []string
+	// Schema is the credentialSchema of issued credentials
+	Schema interface{}
+	// StatusPurposes are allocated a status entry each from the builder status list managers
+	StatusPurposes []string
+	// Validity is the validity period of issued credentials, unlimited when zero
+	Validity time.Duration
+	// RequiredClaims are the subject claims every issued credential must have
+	RequiredClaims []string
+}
+
+func (t *Template) validate() error {
+	if t.Name == "" {
+		return errors.New("template name is required")
+	}
+	if t.Validity < 0 {
+		return fmt.Errorf("template %s: negative validity period", t.Name)
+	}
+	return nil
+}
+
+// RegisterTemplate registers a credential template, replacing the template of the same name
+func (vcb *VCBuilder) RegisterTemplate(t *Template) error {
+	if err := t.validate(); err != nil {
+		return err
+	}
+	vcb.options.Merge([]BuilderOption{WithTemplates(t)})
+	return nil
+}
+
+// IssueFromTemplate issues a credential of the template name about subjectClaims. The credential gets a
+// UUID URN id, the builder DID as issuer, its validity period from the builder clock and newly allocated
+// status entries before being signed.
+func (vcb *VCBuilder) IssueFromTemplate(name string, subjectClaims map[string]interface{}, opts ...BuilderOption) (*credential.Credential, error) {
+	vcb.options.Merge(opts)
+	t, ok := vcb.options.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown credential template %s", name)
+	}
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	if vcb.options.did == "" {
+		return nil, errors.New("issuer DID is required")
+	}
+	var missing []string
+	for _, claim := range t.RequiredClaims {
+		if _, ok := subjectClaims[claim]; !ok {
+			missing = append(missing, claim)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template %s: missing required claims %s", name, strings.Join(missing, ", "))
+	}
+
+	cred := credential.NewCredential()
+	cred.Context = append(credential.Contexts{}, t.Contexts...)
+	if len(cred.Context) == 0 {
+		cred.Context = credential.Contexts{common.VC2JsonLDContext}
+	}
+	types := []string{common.DefaultVCJsonLDContextTypeVC}
+	for _, typ := range t.Types {
+		if typ != common.DefaultVCJsonLDContextTypeVC {
+			types = append(types, typ)
+		}
+	}
+	cred.Type = types
+	cred.Id = "urn:uuid:" + uuid.NewString()
+	cred.Issuer = credential.NewIssuer(vcb.options.did)
+	cred.Schema = t.Schema
+
+	subject := make(map[string]interface{}, len(subjectClaims))
+	for k, v := range subjectClaims {
+		subject[k] = v
+	}
+	cred.Subject = subject
+
+	now := vcb.options.clock().UTC().Truncate(time.Second)
+	from := &common.FormatedTime{Time: now}
+	var until *common.FormatedTime
+	if t.Validity > 0 {
+		until = &common.FormatedTime{Time: now.Add(t.Validity)}
+	}
+	if cred.DataModel() == credential.DataModelV2 {
+		cred.ValidFrom, cred.ValidUntil = from, until
+	} else {
+		cred.IssuanceDate, cred.ExpirationDate = from, until
+	}
+
+	// status entries are only allocated for valid credentials, and released when signing fails
+	if vcb.options.validate {
+		if errs := cred.Validate(vcb.options.dataModel); errs != nil {
+			return nil, fmt.Errorf("invalid credential: %w", errs)
+		}
+	}
+	allocated, err := vcb.allocateStatus(cred, t)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := vcb.AddLinkedDataProof(cred)
+	if err != nil {
+		return nil, releaseStatus(allocated, err)
+	}
+	return signed, nil
+}
+
+// statusAllocation is a status entry allocated by a status list manager
+type statusAllocation struct {
+	manager *status.StatusListManager
+	entry   *status.StatusEntry
+}
+
+// allocateStatus sets a status entry of every status purpose of t
+func (vcb *VCBuilder) allocateStatus(cred *credential.Credential, t *Template) ([]statusAllocation, error) {
+	allocated := make([]statusAllocation, 0, len(t.StatusPurposes))
+	for _, purpose := range t.StatusPurposes {
+		m, ok := vcb.options.statusManagers[purpose]
+		if !ok {
+			return nil, releaseStatus(allocated, fmt.Errorf("template %s: no status list manager for purpose %s", t.Name, purpose))
+		}
+		entry, err := m.Allocate()
+		if err != nil {
+			return nil, releaseStatus(allocated, fmt.Errorf("template %s: %w", t.Name, err))
+		}
+		allocated = append(allocated, statusAllocation{manager: m, entry: entry})
+	}
+	switch len(allocated) {
+	case 0:
+	case 1:
+		cred.Status = allocated[0].entry.ToMap()
+	default:
+		entries := make([]interface{}, len(allocated))
+		for i, a := range allocated {
+			entries[i] = a.entry.ToMap()
+		}
+		cred.Status = entries
+	}
+	return allocated, nil
+}
+
+// releaseStatus releases the status entries of a credential which was not issued because of err
+func releaseStatus(allocated []statusAllocation, err error) error {
+	for _, a := range allocated {
+		if rerr := a.manager.Release(a.entry); rerr != nil {
+			return fmt.Errorf("%w, release status entry %s: %v", err, a.entry.ID, rerr)
+		}
+	}
+	return err
+}
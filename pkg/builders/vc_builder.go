@@ -2,17 +2,17 @@ package builders
 
 import (
 	"fmt"
-	"strconv"
+	"time"
 
 	"github.com/suutaku/go-bbs/pkg/bbs"
 	"github.com/suutaku/go-vc/pkg/common"
 	"github.com/suutaku/go-vc/pkg/credential"
 	"github.com/suutaku/go-vc/pkg/proof"
 	"github.com/suutaku/go-vc/pkg/resolver"
+	"github.com/suutaku/go-vc/pkg/schema"
 	"github.com/suutaku/go-vc/pkg/status"
 	"github.com/suutaku/go-vc/pkg/suite"
-	"github.com/suutaku/go-vc/pkg/suite/bbsblssignature2020"
-	"github.com/suutaku/go-vc/pkg/suite/bbsblssignatureproof2020"
+	"github.com/suutaku/go-vc/pkg/verifier"
 )
 
 type VCBuilder struct {
@@ -23,13 +23,14 @@ func NewVCBuilder(opts ...BuilderOption) *VCBuilder {
 
 	options := prepareOpts(opts)
 
-	// if no suites parsed, create suites with verify function only
+	// for suites not parsed, create suites with verify function only
 	if options.signatureSuites == nil {
 		options.signatureSuites = make(map[string]suite.SignatureSuite)
-		bbss := bbsblssignature2020.NewSignatureSuite(nil, false)
-		options.signatureSuites[bbss.Alg()] = bbss
-		bbsps := bbsblssignatureproof2020.NewSignatureSuite(nil, false)
-		options.signatureSuites[bbsps.Alg()] = bbsps
+	}
+	for _, s := range verifier.VerificationSuites() {
+		if _, ok := options.signatureSuites[s.Alg()]; !ok {
+			options.signatureSuites[s.Alg()] = s
+		}
 	}
 	// if no linked data proof context parsed, create default context
 	if options.ldpCtx == nil {
@@ -50,7 +51,15 @@ func NewVCBuilder(opts ...BuilderOption) *VCBuilder {
 func (vcb *VCBuilder) AddLinkedDataProof(cred *credential.Credential, opts ...BuilderOption) (*credential.Credential, error) {
 	// reset options if need
 	vcb.options.Merge(opts)
-	s := vcb.options.signatureSuites[vcb.options.ldpCtx.SignatureType]
+	s, ok := vcb.options.signatureSuites[vcb.options.ldpCtx.SignatureType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signature type %s", vcb.options.ldpCtx.SignatureType)
+	}
+	if vcb.options.validate {
+		if errs := cred.Validate(vcb.options.dataModel); errs != nil {
+			return nil, fmt.Errorf("invalid credential: %w", errs)
+		}
+	}
 	err := cred.AddLinkedDataProof(s, vcb.options.ldpCtx, vcb.options.processorOpts...)
 	return cred, err
 }
@@ -61,6 +70,14 @@ func (vcb *VCBuilder) GenerateBBSSelectiveDisclosure(cred, revealed *credential.
 	return cred.GenerateBBSSelectiveDisclosure(s, revealed, pubResolver, nonce, vcb.options.processorOpts...)
 }
 
+// GenerateBBS2023SelectiveDisclosure derives a bbs-2023 credential revealing statements selected by
+// selectivePointers (JSON pointers) in addition to the issuer's mandatory pointers
+func (vcb *VCBuilder) GenerateBBS2023SelectiveDisclosure(cred *credential.Credential, selectivePointers []string, presentationHeader []byte, opts ...BuilderOption) (*credential.Credential, error) {
+	vcb.options.Merge(opts)
+	s := vcb.options.signatureSuites["bbs-2023"]
+	return cred.DeriveDataIntegrityProof(s, selectivePointers, presentationHeader, vcb.options.processorOpts...)
+}
+
 func (vcb *VCBuilder) PreBlindSign(cred, revealed *credential.Credential, issuerPubResolver resolver.PublicKeyResolver, nonce []byte, opts ...BuilderOption) (*bbs.BlindSignatureContext, []int, int, error) {
 	vcb.options.Merge(opts)
 	s := vcb.options.signatureSuites["BbsBlsSignature2020"]
@@ -79,9 +96,28 @@ func (vcb *VCBuilder) CompleteSignature(cred *credential.Credential, blindSig *b
 	return cred.CompleteSignature(s, vcb.options.ldpCtx, blindSig)
 }
 
+// Verify verifies the proofs and validity period of the credential
 func (vcb *VCBuilder) Verify(cred *credential.Credential, issuerPubResolver resolver.PublicKeyResolver, opts ...BuilderOption) error {
 	vcb.options.Merge(opts)
-	return cred.VerifyProof(vcb.options.signatureSuites, issuerPubResolver, vcb.options.processorOpts...)
+	if err := cred.VerifyProof(vcb.options.signatureSuites, issuerPubResolver, vcb.options.processorOpts...); err != nil {
+		return err
+	}
+	return cred.CheckValidity(vcb.options.clock(), vcb.options.skew)
+}
+
+// NewVerifier creates a verifier using the signature suites and json-ld processor options of this builder
+func (vcb *VCBuilder) NewVerifier(issuerPubResolver resolver.PublicKeyResolver, opts ...verifier.VerifierOption) *verifier.Verifier {
+	suites := make([]suite.SignatureSuite, 0, len(vcb.options.signatureSuites))
+	for _, s := range vcb.options.signatureSuites {
+		suites = append(suites, s)
+	}
+	opts = append([]verifier.VerifierOption{
+		verifier.WithSignatureSuites(suites...),
+		verifier.WithProcessorOptions(vcb.options.processorOpts...),
+		verifier.WithClock(vcb.options.clock),
+		verifier.WithClockSkew(vcb.options.skew),
+	}, opts...)
+	return verifier.NewVerifier(issuerPubResolver, opts...)
 }
 
 // GenStatusCredential
@@ -93,8 +129,14 @@ func (vcb *VCBuilder) Verify(cred *credential.Credential, issuerPubResolver reso
 // 4) Set the encodedList to compressed bitstring.
 // 5) Generate a proof for the RLC and publish it to the endpoint listed in the verifiable credential.
 func (vcb *VCBuilder) GenStatusCredentialList(id string, issuedCred []credential.Credential) (*credential.Credential, error) {
+	return vcb.GenPurposeStatusCredentialList(id, status.StatusPurposeRevocation, issuedCred)
+}
+
+// GenPurposeStatusCredentialList generates the status list credential of a status purpose, e.g. a suspension list
+// from the suspended credentials. A credential is unsuspended by generating the list without it.
+func (vcb *VCBuilder) GenPurposeStatusCredentialList(id, purpose string, issuedCred []credential.Credential) (*credential.Credential, error) {
 	preBuildCred := &credential.Credential{
-		Context: []string{
+		Context: credential.Contexts{
 			common.DefaultVCJsonLDContext,
 			common.DefaultBbsJsonLDContext,
 			common.DefaultStatusVCJsonLDContext,
@@ -104,10 +146,11 @@ func (vcb *VCBuilder) GenStatusCredentialList(id string, issuedCred []credential
 			common.DefaultVCJsonLDContextTypeVC,
 			common.DefaultVCJsonLDContextTypeSC,
 		},
-		Issuer: vcb.options.did,
+		Issuer:       credential.NewIssuer(vcb.options.did),
+		IssuanceDate: &common.FormatedTime{Time: vcb.options.clock().UTC().Truncate(time.Second)},
 		Subject: map[string]interface{}{
 			"type":          status.StatusList2021,
-			"statusPurpose": status.StatusPurposeRevocation,
+			"statusPurpose": purpose,
 		},
 	}
 	statCred, err := status.GenStatusCredential(issuedCred, preBuildCred)
@@ -117,6 +160,19 @@ func (vcb *VCBuilder) GenStatusCredentialList(id string, issuedCred []credential
 	return vcb.AddLinkedDataProof(statCred)
 }
 
+// NewStatusListManager creates a status list manager whose status list credentials are issued
+// and signed by this builder
+func (vcb *VCBuilder) NewStatusListManager(storage status.Storage, listBaseURL string, opts ...status.ManagerOption) *status.StatusListManager {
+	opts = append([]status.ManagerOption{
+		status.WithIssuer(vcb.options.did),
+		status.WithIssuanceClock(vcb.options.clock),
+		status.WithSigner(func(cred *credential.Credential) (*credential.Credential, error) {
+			return vcb.AddLinkedDataProof(cred)
+		}),
+	}, opts...)
+	return status.NewStatusListManager(storage, listBaseURL, opts...)
+}
+
 // ValidateStatusCredential
 // 1) Let credentialToValidate be a verifiable credentials containing a credentialStatus entry that is a
 // StatusList2021Entry.
@@ -134,29 +190,115 @@ func (vcb *VCBuilder) ValidateStatusCredential(statusList, credToValid *credenti
 	if err != nil {
 		return false, err
 	}
-	// resp, err := http.Get(credToValid.Status["id"].(string))
-	// if err != nil {
-	// 	return false, fmt.Errorf("cannot resolve status entry id: %w", err)
-	// }
-	// list := make(map[string]interface{})
-	// json.NewDecoder(resp.Body).Decode(&list)
 	credSubject, ok := statusList.Subject.(map[string]interface{})
 	if !ok {
 		return false, fmt.Errorf("status credential don't have credentialSubject item")
 	}
+	entry, err := statusEntryOf(credToValid, statusList.Id, credSubject["statusPurpose"])
+	if err != nil {
+		return false, err
+	}
+	res, err := status.CheckStatus(entry, statusList)
+	if err != nil {
+		return false, err
+	}
+	return res.Set, nil
+}
 
-	if credToValid.Status["statusPurpose"].(string) != credSubject["statusPurpose"].(string) {
-		return false, fmt.Errorf("status purpose not matched, list %s, entry %s", credSubject["statusPurpose"].(string), credToValid.Status["statusPurpose"].(string))
+// CheckCredentialStatus verifies the credential and checks every credentialStatus entry in the status list credential
+// whose id is the entry's statusListCredential. The purposes set are reported by status.SetPurposes.
+func (vcb *VCBuilder) CheckCredentialStatus(credToValid *credential.Credential, statusLists []*credential.Credential,
+	issuerKeyResolver resolver.PublicKeyResolver) ([]*status.Result, error) {
+	return vcb.checkCredentialStatus(credToValid, issuerKeyResolver, func(listID string) (*credential.Credential, error) {
+		for _, v := range statusLists {
+			if v.Id != listID {
+				continue
+			}
+			if err := vcb.Verify(v, issuerKeyResolver); err != nil {
+				return nil, fmt.Errorf("status list %s: %w", listID, err)
+			}
+			return v, nil
+		}
+		return nil, fmt.Errorf("status list %s not found", listID)
+	})
+}
+
+// ResolveCredentialStatus verifies the credential and checks every credentialStatus entry in the status list
+// credential dereferenced by listResolver, see NewStatusListResolver.
+func (vcb *VCBuilder) ResolveCredentialStatus(credToValid *credential.Credential, listResolver status.StatusListResolver,
+	issuerKeyResolver resolver.PublicKeyResolver) ([]*status.Result, error) {
+	return vcb.checkCredentialStatus(credToValid, issuerKeyResolver, listResolver.Resolve)
+}
+
+// NewStatusListResolver creates a status list resolver which verifies the proof of fetched lists with issuerKeyResolver
+func (vcb *VCBuilder) NewStatusListResolver(issuerKeyResolver resolver.PublicKeyResolver, opts ...status.ResolverOption) *status.HTTPStatusListResolver {
+	opts = append([]status.ResolverOption{
+		status.WithListVerifier(func(cred *credential.Credential) error {
+			return vcb.Verify(cred, issuerKeyResolver)
+		}),
+	}, opts...)
+	return status.NewHTTPStatusListResolver(opts...)
+}
+
+// NewSchemaValidator creates a credentialSchema validator which verifies the proof of JsonSchemaCredentials with
+// issuerKeyResolver, pass its Validate method to verifier.WithSchemaValidator.
+func (vcb *VCBuilder) NewSchemaValidator(issuerKeyResolver resolver.PublicKeyResolver, opts ...schema.ValidatorOption) *schema.Validator {
+	opts = append([]schema.ValidatorOption{
+		schema.WithSchemaVerifier(func(cred *credential.Credential) error {
+			return vcb.Verify(cred, issuerKeyResolver)
+		}),
+	}, opts...)
+	return schema.NewValidator(opts...)
+}
+
+// checkCredentialStatus checks every credentialStatus entry of a verified credential, status lists must be issued
+// by the credential issuer
+func (vcb *VCBuilder) checkCredentialStatus(credToValid *credential.Credential, issuerKeyResolver resolver.PublicKeyResolver,
+	statusList func(listID string) (*credential.Credential, error)) ([]*status.Result, error) {
+	if err := vcb.Verify(credToValid, issuerKeyResolver); err != nil {
+		return nil, err
 	}
-	idx, err := strconv.ParseInt(credToValid.Status["statusListIndex"].(string), 10, 64)
+	entries, err := credential.GetStatusEntries(credToValid.Status)
 	if err != nil {
-		return false, fmt.Errorf("invlaid status list index %s", credToValid.Status["statusListIndex"].(string))
+		return nil, err
 	}
+	ret := make([]*status.Result, 0, len(entries))
+	for _, entry := range entries {
+		listID, _ := entry["statusListCredential"].(string)
+		list, err := statusList(listID)
+		if err != nil {
+			return nil, err
+		}
+		if list.IssuerID() != credToValid.IssuerID() {
+			return nil, fmt.Errorf("status list issuer %s not matched credential issuer %s", list.IssuerID(), credToValid.IssuerID())
+		}
+		res, err := status.CheckStatus(entry, list)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, res)
+	}
+	return ret, nil
+}
 
-	encodedList, ok := credSubject["encodedList"].(string)
-	if !ok {
-		return false, fmt.Errorf("status credential don't have encodedList item")
+// statusEntryOf returns the credentialStatus entry of a status list, the entry referencing the list is preferred
+// to one only matching its purpose
+func statusEntryOf(cred *credential.Credential, listID string, purpose interface{}) (map[string]interface{}, error) {
+	entries, err := credential.GetStatusEntries(cred.Status)
+	if err != nil {
+		return nil, err
+	}
+	var ret map[string]interface{}
+	for _, entry := range entries {
+		if entry["statusListCredential"] == listID {
+			return entry, nil
+		}
+		if ret == nil && entry["statusPurpose"] == purpose {
+			ret = entry
+		}
+	}
+	if ret == nil {
+		return nil, fmt.Errorf("credential don't have %v status entry", purpose)
 	}
-	bitStr := status.ParseBitString(encodedList)
-	return bitStr.Check(int(idx))
+	return ret, nil
 }
@@ -1,14 +1,35 @@
 package builders
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
 	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/suutaku/go-bbs/pkg/bbs"
+	"github.com/suutaku/go-vc/pkg/common"
 	"github.com/suutaku/go-vc/pkg/credential"
+	"github.com/suutaku/go-vc/pkg/jose"
+	"github.com/suutaku/go-vc/pkg/ldcontext"
+	"github.com/suutaku/go-vc/pkg/presentation"
 	"github.com/suutaku/go-vc/pkg/processor"
+	"github.com/suutaku/go-vc/pkg/proof"
 	"github.com/suutaku/go-vc/pkg/resolver"
+	"github.com/suutaku/go-vc/pkg/schema"
+	"github.com/suutaku/go-vc/pkg/status"
+	"github.com/suutaku/go-vc/pkg/verifier"
 	"github.com/suutaku/go-vc/test"
 )
 
@@ -18,11 +39,15 @@ const (
 	credentialRevealedDocPath      string = "vc-json-doc-revealed.json"
 	credentialBlindRevealedDocPath string = "vc-json-doc-blind.json"
 	credentialCustomDocPath        string = "vc-json-doc-custom.jsonld"
+	credentialEd25519DocPath       string = "vc-json-doc-ed25519.json"
+	credentialECDSADocPath         string = "vc-json-doc-ecdsa.json"
+	credentialBBS2023DocPath       string = "vc-json-doc-bbs2023.json"
+	credentialJWSDocPath           string = "vc-json-doc-jws.json"
 	issuerKeyPath                  string = "issuer-private-key.txt"
 	holderKeyPath                  string = "holder-private-key.txt"
 )
 
-func genIssuerBuilderAndPublicKeyResolver(t *testing.T) (*VCBuilder, resolver.PublicKeyResolver) {
+func genIssuerBuilderAndPublicKeyResolver(t *testing.T, opts ...BuilderOption) (*VCBuilder, resolver.PublicKeyResolver) {
 	iKeyStr, err := test.GetTestResource(issuerKeyPath)
 	assert.NoError(t, err, "cannot get test resource")
 	iKeyBytes, err := hex.DecodeString(string(iKeyStr))
@@ -36,11 +61,12 @@ func genIssuerBuilderAndPublicKeyResolver(t *testing.T) (*VCBuilder, resolver.Pu
 		Type:  "Bls12381G2Key2020",
 		Value: pubBytes,
 	}, nil)
-	builder := NewVCBuilder(WithPrivateKey(iPriv), WithDID("did:cot:6u3SCqoKfARwgbssjie1agpsoPitjKwkeFZxtJGb5BqY"), WithProcessorOptions(processor.WithValidateRDF()))
+	opts = append([]BuilderOption{WithPrivateKey(iPriv), WithDID("did:cot:6u3SCqoKfARwgbssjie1agpsoPitjKwkeFZxtJGb5BqY"), WithProcessorOptions(processor.WithValidateRDF())}, opts...)
+	builder := NewVCBuilder(opts...)
 	return builder, pubResv
 }
 
-func genHolderBuilderAndPublicKeyResolver(t *testing.T) (*VCBuilder, resolver.PublicKeyResolver) {
+func genHolderBuilderAndPublicKeyResolver(t *testing.T, opts ...BuilderOption) (*VCBuilder, resolver.PublicKeyResolver) {
 	hKeyStr, err := test.GetTestResource(holderKeyPath)
 	assert.NoError(t, err, "cannot get test resource")
 	hKeyBytes, err := hex.DecodeString(string(hKeyStr))
@@ -54,7 +80,8 @@ func genHolderBuilderAndPublicKeyResolver(t *testing.T) (*VCBuilder, resolver.Pu
 		Type:  "Bls12381G2Key2020",
 		Value: pubBytes,
 	}, nil)
-	builder := NewVCBuilder(WithPrivateKey(hPriv), WithProcessorOptions(processor.WithValidateRDF()))
+	opts = append([]BuilderOption{WithPrivateKey(hPriv), WithProcessorOptions(processor.WithValidateRDF())}, opts...)
+	builder := NewVCBuilder(opts...)
 	return builder, pubResv
 }
 
@@ -94,6 +121,16 @@ func getTestBlindRevealedCredential(t *testing.T) *credential.Credential {
 	return cred
 }
 
+// offlineProcessorOptions returns processor options which load the test contexts without network
+func offlineProcessorOptions(t *testing.T) []processor.ProcessorOpts {
+	ctxBytes, err := test.GetTestResource("citizenship-v1.jsonld")
+	require.NoError(t, err)
+	loader, err := ldcontext.NewDocumentLoader(ldcontext.WithoutNetwork(),
+		ldcontext.WithContext("https://w3id.org/citizenship/v1", ctxBytes))
+	require.NoError(t, err)
+	return []processor.ProcessorOpts{processor.WithValidateRDF(), processor.WithDocumentLoader(loader)}
+}
+
 func TestLinkedDataProof(t *testing.T) {
 	iBuilder, iResolver := genIssuerBuilderAndPublicKeyResolver(t)
 	assert.NotNil(t, iBuilder, "cannot create issuer builder")
@@ -114,6 +151,210 @@ func TestLinkedDataProof(t *testing.T) {
 
 }
 
+func TestEd25519LinkedDataProof(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	priv := ed25519.NewKeyFromSeed(seed)
+	pubResv := resolver.NewTestPublicKeyResolver(&resolver.PublicKey{
+		Type:  "Ed25519VerificationKey2020",
+		Value: priv.Public().(ed25519.PublicKey),
+	}, nil)
+	did := "did:example:489398593"
+	builder := NewVCBuilder(
+		WithEd25519PrivateKey(priv),
+		WithDID(did),
+		WithProcessorOptions(offlineProcessorOptions(t)...),
+		WithLinkedDataProofContext(&proof.LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2020",
+			SignatureRepresentation: proof.SignatureProofValue,
+			VerificationMethod:      did + "#owner",
+		}),
+	)
+
+	cred := getTestCredentialWithName(t, credentialEd25519DocPath)
+	require.NotNil(t, cred, "cannot get credential")
+
+	signedCred, err := builder.AddLinkedDataProof(cred)
+	require.NoError(t, err, "issuer cannot sign credential")
+	t.Logf("signed credential:\n%s\n", signedCred.ToString())
+	proofs, err := credential.GetProofs(signedCred.Proof)
+	require.NoError(t, err)
+	assert.Equal(t, "Ed25519Signature2020", proofs[0]["type"])
+	assert.Equal(t, byte('z'), proofs[0]["proofValue"].(string)[0], "proofValue must be multibase base58-btc")
+
+	// verifier only have BBS+ keys parsed, Ed25519 suite was registered automatically
+	vBuilder, _ := genHolderBuilderAndPublicKeyResolver(t, WithProcessorOptions(offlineProcessorOptions(t)...))
+	err = vBuilder.Verify(signedCred, pubResv)
+	assert.NoError(t, err, "invalid signature")
+
+	signedCred.Subject.(map[string]interface{})["givenName"] = "JANE"
+	err = vBuilder.Verify(signedCred, pubResv)
+	assert.Error(t, err, "tampered credential must not be verified")
+}
+
+func TestECDSALinkedDataProof(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+			require.NoError(t, err)
+			pubResv := resolver.NewTestPublicKeyResolver(&resolver.PublicKey{
+				Type:  "Multikey",
+				Value: elliptic.MarshalCompressed(curve, priv.X, priv.Y),
+			}, nil)
+			did := "did:example:489398593"
+			procOpts := offlineProcessorOptions(t)
+			builder := NewVCBuilder(
+				WithECDSAPrivateKey(priv),
+				WithDID(did),
+				WithProcessorOptions(procOpts...),
+				WithLinkedDataProofContext(&proof.LinkedDataProofContext{
+					SignatureType:           "ecdsa-rdfc-2019",
+					SignatureRepresentation: proof.SignatureProofValue,
+					VerificationMethod:      did + "#owner",
+				}),
+			)
+
+			cred := getTestCredentialWithName(t, credentialECDSADocPath)
+			require.NotNil(t, cred, "cannot get credential")
+
+			signedCred, err := builder.AddLinkedDataProof(cred)
+			require.NoError(t, err, "issuer cannot sign credential")
+			t.Logf("signed credential:\n%s\n", signedCred.ToString())
+			proofs, err := credential.GetProofs(signedCred.Proof)
+			require.NoError(t, err)
+			assert.Equal(t, "DataIntegrityProof", proofs[0]["type"])
+			assert.Equal(t, "ecdsa-rdfc-2019", proofs[0]["cryptosuite"])
+
+			vBuilder := NewVCBuilder(WithProcessorOptions(procOpts...))
+			err = vBuilder.Verify(signedCred, pubResv)
+			assert.NoError(t, err, "invalid signature")
+
+			signedCred.Subject.(map[string]interface{})["givenName"] = "JANE"
+			err = vBuilder.Verify(signedCred, pubResv)
+			assert.Error(t, err, "tampered credential must not be verified")
+		})
+	}
+}
+
+func TestJWSLinkedDataProof(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	k1, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	_, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaPriv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys := []struct {
+		alg  string
+		priv crypto.PrivateKey
+		pub  crypto.PublicKey
+	}{
+		{"ES256", p256, &p256.PublicKey},
+		{"ES256K", k1, k1.PubKey()},
+		{"EdDSA", edPriv, edPriv.Public()},
+		{"PS256", rsaPriv, &rsaPriv.PublicKey},
+	}
+	for _, k := range keys {
+		t.Run(k.alg, func(t *testing.T) {
+			jwk, err := jose.NewJWK(k.pub)
+			require.NoError(t, err)
+			pubResv := resolver.NewTestPublicKeyResolver(&resolver.PublicKey{
+				Type: "JsonWebKey2020",
+				Jwk:  jwk,
+			}, nil)
+			did := "did:example:489398593"
+			procOpts := offlineProcessorOptions(t)
+			builder := NewVCBuilder(
+				WithJWSPrivateKey(k.priv),
+				WithDID(did),
+				WithProcessorOptions(procOpts...),
+				WithLinkedDataProofContext(&proof.LinkedDataProofContext{
+					SignatureType:           "JsonWebSignature2020",
+					SignatureRepresentation: proof.SignatureJWS,
+					VerificationMethod:      did + "#owner",
+				}),
+			)
+
+			cred := getTestCredentialWithName(t, credentialJWSDocPath)
+			require.NotNil(t, cred, "cannot get credential")
+
+			signedCred, err := builder.AddLinkedDataProof(cred)
+			require.NoError(t, err, "issuer cannot sign credential")
+			t.Logf("signed credential:\n%s\n", signedCred.ToString())
+			proofs, err := credential.GetProofs(signedCred.Proof)
+			require.NoError(t, err)
+			jws := proof.NewJwt()
+			assert.NoError(t, jws.Parse(proofs[0]["jws"].(string)))
+			alg, err := jws.ValidateHeader()
+			require.NoError(t, err)
+			assert.Equal(t, k.alg, alg)
+
+			vBuilder := NewVCBuilder(WithProcessorOptions(procOpts...))
+			err = vBuilder.Verify(signedCred, pubResv)
+			assert.NoError(t, err, "invalid signature")
+
+			signedCred.Subject.(map[string]interface{})["givenName"] = "JANE"
+			err = vBuilder.Verify(signedCred, pubResv)
+			assert.Error(t, err, "tampered credential must not be verified")
+		})
+	}
+}
+
+func TestOfflineJWSLinkedDataProof(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwk, err := jose.NewJWK(&priv.PublicKey)
+	require.NoError(t, err)
+	// JWS proof options are canonicalized with the security v2 and jws v1 contexts, which must be embedded
+	procOpts := offlineProcessorOptions(t)
+	builder := NewVCBuilder(
+		WithJWSPrivateKey(priv),
+		WithDID("did:example:489398593"),
+		WithProcessorOptions(procOpts...),
+		WithLinkedDataProofContext(&proof.LinkedDataProofContext{
+			SignatureType:           "JsonWebSignature2020",
+			SignatureRepresentation: proof.SignatureJWS,
+			VerificationMethod:      "did:example:489398593#owner",
+		}),
+	)
+	signed, err := builder.AddLinkedDataProof(getTestCredentialWithName(t, credentialJWSDocPath))
+	require.NoError(t, err)
+	pubResv := resolver.NewTestPublicKeyResolver(&resolver.PublicKey{Type: "JsonWebKey2020", Jwk: jwk}, nil)
+	assert.NoError(t, NewVCBuilder(WithProcessorOptions(procOpts...)).Verify(signed, pubResv))
+}
+
+func TestJWSHeaderValidation(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwk, err := jose.NewJWK(&priv.PublicKey)
+	require.NoError(t, err)
+	pubResv := resolver.NewTestPublicKeyResolver(&resolver.PublicKey{Type: "JsonWebKey2020", Jwk: jwk}, nil)
+	builder := NewVCBuilder(
+		WithJWSPrivateKey(priv),
+		WithProcessorOptions(offlineProcessorOptions(t)...),
+		WithLinkedDataProofContext(&proof.LinkedDataProofContext{
+			SignatureType:           "JsonWebSignature2020",
+			SignatureRepresentation: proof.SignatureJWS,
+			VerificationMethod:      "did:example:489398593#owner",
+		}),
+	)
+	signedCred, err := builder.AddLinkedDataProof(getTestCredentialWithName(t, credentialJWSDocPath))
+	require.NoError(t, err, "issuer cannot sign credential")
+	p := signedCred.Proof.(*proof.Proof)
+	parts := strings.Split(p.JWS, ".")
+
+	for name, header := range map[string]string{
+		"encoded payload":   `{"alg":"ES256","b64":true,"crit":["b64"]}`,
+		"b64 not critical":  `{"alg":"ES256","b64":false}`,
+		"unknown critical":  `{"alg":"ES256","b64":false,"crit":["b64","exp"]}`,
+		"alg not match key": `{"alg":"EdDSA","b64":false,"crit":["b64"]}`,
+	} {
+		p.JWS = base64.RawURLEncoding.EncodeToString([]byte(header)) + ".." + parts[2]
+		assert.Error(t, builder.Verify(signedCred, pubResv), name)
+	}
+}
+
 func TestSelectiveDisclosure(t *testing.T) {
 	iBuilder, iResolver := genIssuerBuilderAndPublicKeyResolver(t)
 	assert.NotNil(t, iBuilder, "cannot create issuer builder")
@@ -126,7 +367,7 @@ func TestSelectiveDisclosure(t *testing.T) {
 	assert.NotNil(t, cred, "cannot get credential")
 
 	signed, err := iBuilder.AddLinkedDataProof(cred)
-	assert.NoError(t, err, "issuer cannot sign credential")
+	require.NoError(t, err, "issuer cannot sign credential")
 	t.Logf("signed credential:\n%s\n", signed.ToString())
 
 	err = hBuilder.Verify(signed, iResolver)
@@ -136,13 +377,55 @@ func TestSelectiveDisclosure(t *testing.T) {
 	assert.NotNil(t, revealed, "cannot get revealed credential")
 
 	disclosure, err := hBuilder.GenerateBBSSelectiveDisclosure(signed, revealed, iResolver, []byte("nonce"))
-	assert.NoError(t, err, "cannot generate selective disclosure")
+	require.NoError(t, err, "cannot generate selective disclosure")
 	t.Logf("generated selective disclosure credential:\n%s\n", disclosure.ToString())
 
 	err = hBuilder.Verify(disclosure, iResolver)
 	assert.NoError(t, err, "invalid signature")
 }
 
+func TestBBS2023SelectiveDisclosure(t *testing.T) {
+	procOpts := WithProcessorOptions(offlineProcessorOptions(t)...)
+	iBuilder, iResolver := genIssuerBuilderAndPublicKeyResolver(t, procOpts)
+	require.NotNil(t, iBuilder, "cannot create issuer builder")
+	hBuilder, _ := genHolderBuilderAndPublicKeyResolver(t, procOpts)
+	require.NotNil(t, hBuilder, "cannot create holder builder")
+
+	cred := getTestCredentialWithName(t, credentialBBS2023DocPath)
+	require.NotNil(t, cred, "cannot get credential")
+
+	signed, err := iBuilder.AddLinkedDataProof(cred, WithLinkedDataProofContext(&proof.LinkedDataProofContext{
+		SignatureType:           "bbs-2023",
+		SignatureRepresentation: proof.SignatureProofValue,
+		VerificationMethod:      "did:example:489398593#owner",
+		MandatoryPointers:       []string{"/issuer", "/credentialSubject/birthCountry"},
+	}))
+	assert.NoError(t, err, "issuer cannot sign credential")
+	t.Logf("signed credential:\n%s\n", signed.ToString())
+	proofs, err := credential.GetProofs(signed.Proof)
+	require.NoError(t, err)
+	assert.Equal(t, "DataIntegrityProof", proofs[0]["type"])
+	assert.Equal(t, "bbs-2023", proofs[0]["cryptosuite"])
+
+	err = hBuilder.Verify(signed, iResolver)
+	assert.NoError(t, err, "invalid base proof")
+
+	disclosure, err := hBuilder.GenerateBBS2023SelectiveDisclosure(signed, []string{"/credentialSubject/givenName"}, []byte("nonce"))
+	require.NoError(t, err, "cannot generate selective disclosure")
+	t.Logf("generated selective disclosure credential:\n%s\n", disclosure.ToString())
+	subject := disclosure.Subject.(map[string]interface{})
+	assert.Equal(t, "JOHN", subject["givenName"])
+	assert.Equal(t, "Bahamas", subject["birthCountry"])
+	assert.NotContains(t, subject, "familyName")
+
+	err = hBuilder.Verify(disclosure, iResolver)
+	assert.NoError(t, err, "invalid derived proof")
+
+	subject["givenName"] = "JANE"
+	err = hBuilder.Verify(disclosure, iResolver)
+	assert.Error(t, err, "tampered credential must not be verified")
+}
+
 func TestBlindSign(t *testing.T) {
 	iBuilder, iResolver := genIssuerBuilderAndPublicKeyResolver(t)
 	assert.NotNil(t, iBuilder, "cannot create issuer builder")
@@ -174,7 +457,7 @@ func TestBlindSign(t *testing.T) {
 	revealed := getTestRevealedCredential(t)
 	assert.NotNil(t, revealed, "cannot get revealed credential")
 	disclosure, err := hBuilder.GenerateBBSSelectiveDisclosure(cred, revealed, iResolver, []byte("nonce"))
-	assert.NoError(t, err, "cannot generate selective disclosure")
+	require.NoError(t, err, "cannot generate selective disclosure")
 	t.Logf("generated selective disclosure credential:\n%s\n", disclosure.ToString())
 
 	err = hBuilder.Verify(disclosure, iResolver)
@@ -203,3 +486,458 @@ func TestStatusCredential(t *testing.T) {
 	err = iBuilder.Verify(statusCred, iResolver)
 	assert.NoError(t, err)
 }
+
+func TestStatusListManager(t *testing.T) {
+	iBuilder, iResolver := genIssuerBuilderAndPublicKeyResolver(t)
+	require.NotNil(t, iBuilder, "cannot create issuer builder")
+
+	m := iBuilder.NewStatusListManager(status.NewMemoryStorage(), "http://ssis.cotnetwork.com/status")
+	entry, err := m.Allocate()
+	require.NoError(t, err)
+	assert.NoError(t, m.SetStatus(entry, true))
+
+	statusCred, err := m.StatusCredential(entry.Credential)
+	require.NoError(t, err)
+	require.NotNil(t, statusCred.Proof)
+	err = iBuilder.Verify(statusCred, iResolver)
+	assert.NoError(t, err)
+
+	m = iBuilder.NewStatusListManager(status.NewMemoryStorage(), "http://ssis.cotnetwork.com/bitstring-status",
+		status.WithBitstringStatusList())
+	entry, err = m.Allocate()
+	require.NoError(t, err)
+	statusCred, err = m.StatusCredential(entry.Credential)
+	require.NoError(t, err)
+	require.NotNil(t, statusCred.Proof)
+	err = iBuilder.Verify(statusCred, iResolver)
+	assert.NoError(t, err)
+}
+
+func TestSuspensionStatus(t *testing.T) {
+	iBuilder, iResolver := genIssuerBuilderAndPublicKeyResolver(t)
+	require.NotNil(t, iBuilder, "cannot create issuer builder")
+
+	cred := getTestCredential(t)
+	require.NotNil(t, cred, "cannot get credential")
+	revocationList := "http://ssis.cotnetwork.com/status/revocation/1"
+	suspensionList := "http://ssis.cotnetwork.com/status/suspension/1"
+	cred.Status = []interface{}{
+		(&status.StatusEntry{
+			ID:         revocationList + "#5",
+			Type:       status.StatusList2021Entry,
+			Purpose:    status.StatusPurposeRevocation,
+			ListIndex:  "5",
+			Credential: revocationList,
+		}).ToMap(),
+		(&status.StatusEntry{
+			ID:         suspensionList + "#7",
+			Type:       status.StatusList2021Entry,
+			Purpose:    status.StatusPurposeSuspension,
+			ListIndex:  "7",
+			Credential: suspensionList,
+		}).ToMap(),
+	}
+	revocationCred, err := iBuilder.GenStatusCredentialList(revocationList, nil)
+	require.NoError(t, err)
+	// status lists must be issued by the credential issuer
+	cred.Issuer = revocationCred.Issuer
+	signed, err := iBuilder.AddLinkedDataProof(cred)
+	require.NoError(t, err)
+	suspensionCred, err := iBuilder.GenPurposeStatusCredentialList(suspensionList, status.StatusPurposeSuspension,
+		[]credential.Credential{*signed})
+	assert.NoError(t, err)
+
+	results, err := iBuilder.CheckCredentialStatus(signed, []*credential.Credential{revocationCred, suspensionCred}, iResolver)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, []string{status.StatusPurposeSuspension}, status.SetPurposes(results))
+	suspended, err := iBuilder.ValidateStatusCredential(suspensionCred, signed, iResolver)
+	require.NoError(t, err)
+	assert.True(t, suspended)
+
+	// unsuspend
+	suspensionCred, err = iBuilder.GenPurposeStatusCredentialList(suspensionList, status.StatusPurposeSuspension, nil)
+	require.NoError(t, err)
+	results, err = iBuilder.CheckCredentialStatus(signed, []*credential.Credential{revocationCred, suspensionCred}, iResolver)
+	require.NoError(t, err)
+	assert.Empty(t, status.SetPurposes(results))
+
+	_, err = iBuilder.CheckCredentialStatus(signed, []*credential.Credential{revocationCred}, iResolver)
+	assert.Error(t, err)
+}
+
+func TestResolveCredentialStatus(t *testing.T) {
+	iBuilder, iResolver := genIssuerBuilderAndPublicKeyResolver(t)
+	require.NotNil(t, iBuilder, "cannot create issuer builder")
+
+	lists := make(map[string]*credential.Credential)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		list, ok := lists[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(list.ToBytes())
+	}))
+	defer ts.Close()
+
+	m := iBuilder.NewStatusListManager(status.NewMemoryStorage(), ts.URL+"/status", status.WithListSize(64))
+	entry, err := m.Allocate()
+	require.NoError(t, err)
+	assert.NoError(t, m.SetStatus(entry, true))
+	list, err := m.StatusCredential(entry.Credential)
+	require.NoError(t, err)
+	lists["/status/0"] = list
+
+	cred := getTestCredential(t)
+	require.NotNil(t, cred, "cannot get credential")
+	cred.Issuer = list.Issuer
+	cred.Status = entry.ToMap()
+	signed, err := iBuilder.AddLinkedDataProof(cred)
+	require.NoError(t, err)
+
+	listResolver := iBuilder.NewStatusListResolver(iResolver)
+	results, err := iBuilder.ResolveCredentialStatus(signed, listResolver, iResolver)
+	require.NoError(t, err)
+	assert.Equal(t, []string{status.StatusPurposeRevocation}, status.SetPurposes(results))
+
+	// lists of another issuer are rejected
+	other := iBuilder.NewStatusListManager(status.NewMemoryStorage(), ts.URL+"/other", status.WithListSize(64),
+		status.WithIssuer("did:example:other"))
+	otherEntry, err := other.Allocate()
+	require.NoError(t, err)
+	lists["/other/0"], err = other.StatusCredential(otherEntry.Credential)
+	assert.NoError(t, err)
+	cred = getTestCredential(t)
+	cred.Issuer = list.Issuer
+	cred.Status = otherEntry.ToMap()
+	signed, err = iBuilder.AddLinkedDataProof(cred)
+	require.NoError(t, err)
+	_, err = iBuilder.ResolveCredentialStatus(signed, listResolver, iResolver)
+	assert.ErrorContains(t, err, "issuer")
+}
+
+func TestBuilderVerifier(t *testing.T) {
+	iBuilder, iResolver := genIssuerBuilderAndPublicKeyResolver(t, WithProcessorOptions(offlineProcessorOptions(t)...))
+	require.NotNil(t, iBuilder, "cannot create issuer builder")
+
+	cred := getTestCredential(t)
+	require.NotNil(t, cred, "cannot get credential")
+	cred.Issuer = credential.NewIssuer("did:cot:6u3SCqoKfARwgbssjie1agpsoPitjKwkeFZxtJGb5BqY")
+	signed, err := iBuilder.AddLinkedDataProof(cred)
+	require.NoError(t, err)
+
+	// credentialStatus is not checked without status list resolver
+	result := iBuilder.NewVerifier(iResolver).Verify(signed)
+	assert.False(t, result.Verified)
+	assert.Equal(t, verifier.OutcomePassed, result.Check(verifier.CheckProof).Outcome)
+	assert.Equal(t, verifier.OutcomeFailed, result.Check(verifier.CheckStatus).Outcome)
+	result = iBuilder.NewVerifier(iResolver, verifier.WithoutStatusCheck()).Verify(signed)
+	assert.True(t, result.Verified, result.Errors())
+	assert.Equal(t, verifier.OutcomeSkipped, result.Check(verifier.CheckStatus).Outcome)
+}
+
+func TestVerifyValidityPeriod(t *testing.T) {
+	iBuilder, iResolver := genIssuerBuilderAndPublicKeyResolver(t, WithProcessorOptions(offlineProcessorOptions(t)...))
+	require.NotNil(t, iBuilder, "cannot create issuer builder")
+
+	signed, err := iBuilder.AddLinkedDataProof(getTestCredential(t))
+	require.NoError(t, err)
+	expiration := time.Date(2029, 12, 3, 12, 19, 52, 0, time.UTC)
+
+	err = iBuilder.Verify(signed, iResolver, WithClock(func() time.Time { return expiration.Add(time.Minute) }))
+	assert.ErrorIs(t, err, credential.ErrExpired)
+	err = iBuilder.Verify(signed, iResolver, WithClockSkew(2*time.Minute))
+	assert.NoError(t, err)
+	err = iBuilder.Verify(signed, iResolver, WithClock(func() time.Time { return time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC) }))
+	assert.ErrorIs(t, err, credential.ErrNotYetValid)
+}
+
+func TestDataModelValidation(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	priv := ed25519.NewKeyFromSeed(seed)
+	did := "did:example:489398593"
+	builder := NewVCBuilder(
+		WithEd25519PrivateKey(priv),
+		WithDID(did),
+		WithDataModelValidation(""),
+		WithProcessorOptions(offlineProcessorOptions(t)...),
+		WithLinkedDataProofContext(&proof.LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2020",
+			SignatureRepresentation: proof.SignatureProofValue,
+			VerificationMethod:      did + "#owner",
+		}),
+	)
+
+	cred := getTestCredentialWithName(t, credentialEd25519DocPath)
+	cred.Issuer = nil
+	_, err := builder.AddLinkedDataProof(cred)
+	assert.ErrorContains(t, err, "/issuer: is required")
+
+	cred = getTestCredentialWithName(t, credentialEd25519DocPath)
+	_, err = builder.AddLinkedDataProof(cred)
+	assert.NoError(t, err)
+}
+
+func TestValidatedStatusList(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	builder, pubResv := genIssuerBuilderAndPublicKeyResolver(t, WithDataModelValidation(""), WithClock(func() time.Time { return now }))
+
+	statusCred, err := builder.GenStatusCredentialList("http://ssis.cotnetwork.com/status/3", nil)
+	require.NoError(t, err)
+	require.NotNil(t, statusCred.IssuanceDate)
+	assert.Equal(t, now, statusCred.IssuanceDate.Time)
+	assert.NoError(t, builder.Verify(statusCred, pubResv))
+
+	m := builder.NewStatusListManager(status.NewMemoryStorage(), "http://ssis.cotnetwork.com/status")
+	entry, err := m.Allocate()
+	require.NoError(t, err)
+	statusCred, err = m.StatusCredential(entry.Credential)
+	require.NoError(t, err)
+	require.NotNil(t, statusCred.IssuanceDate)
+	assert.Equal(t, now, statusCred.IssuanceDate.Time)
+
+	m = builder.NewStatusListManager(status.NewMemoryStorage(), "http://ssis.cotnetwork.com/bitstring-status",
+		status.WithBitstringStatusList())
+	entry, err = m.Allocate()
+	require.NoError(t, err)
+	statusCred, err = m.StatusCredential(entry.Credential)
+	require.NoError(t, err)
+	require.NotNil(t, statusCred.ValidFrom)
+	assert.Equal(t, now, statusCred.ValidFrom.Time)
+	assert.NoError(t, builder.Verify(statusCred, pubResv))
+}
+
+func TestSchemaValidation(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	priv := ed25519.NewKeyFromSeed(seed)
+	pubResv := resolver.NewTestPublicKeyResolver(&resolver.PublicKey{
+		Type:  "Ed25519VerificationKey2020",
+		Value: priv.Public().(ed25519.PublicKey),
+	}, nil)
+	did := "did:example:489398593"
+	builder := NewVCBuilder(
+		WithEd25519PrivateKey(priv),
+		WithDID(did),
+		WithLinkedDataProofContext(&proof.LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2020",
+			SignatureRepresentation: proof.SignatureProofValue,
+			VerificationMethod:      did + "#owner",
+		}),
+	)
+
+	schemaCred := getTestCredentialWithName(t, "json-schema-credential.json")
+	signedSchema, err := builder.AddLinkedDataProof(schemaCred)
+	require.NoError(t, err)
+	schemaURL := schemaCred.Id
+	loader := schema.MapLoader{schemaURL: signedSchema.ToBytes()}
+
+	// JsonSchemaCredential is defined by the VC 2.0 context
+	cred := getTestCredentialWithName(t, credentialEd25519DocPath)
+	cred.Context = credential.Contexts{common.VC2JsonLDContext}
+	cred.IssuanceDate, cred.ExpirationDate = nil, nil
+	cred.Schema = map[string]interface{}{"id": schemaURL, "type": schema.JsonSchemaCredential}
+	signed, err := builder.AddLinkedDataProof(cred)
+	require.NoError(t, err)
+
+	validator := builder.NewSchemaValidator(pubResv, schema.WithLoader(loader))
+	result := builder.NewVerifier(pubResv, verifier.WithSchemaValidator(validator.Validate)).Verify(signed)
+	assert.True(t, result.Verified, result.Errors())
+	assert.Equal(t, verifier.OutcomePassed, result.Check(verifier.CheckSchema).Outcome)
+
+	// tampered schema credential
+	signedSchema.Subject.(map[string]interface{})["jsonSchema"] = map[string]interface{}{}
+	loader[schemaURL] = signedSchema.ToBytes()
+	validator = builder.NewSchemaValidator(pubResv, schema.WithLoader(loader))
+	result = builder.NewVerifier(pubResv, verifier.WithSchemaValidator(validator.Validate)).Verify(signed)
+	assert.Equal(t, verifier.OutcomeFailed, result.Check(verifier.CheckSchema).Outcome)
+}
+
+func TestIssueFromTemplate(t *testing.T) {
+	priv := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	pubResv := resolver.NewTestPublicKeyResolver(&resolver.PublicKey{
+		Type:  "Ed25519VerificationKey2020",
+		Value: priv.Public().(ed25519.PublicKey),
+	}, nil)
+	did := "did:example:489398593"
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	builder := NewVCBuilder(
+		WithEd25519PrivateKey(priv),
+		WithDID(did),
+		WithClock(func() time.Time { return now }),
+		WithDataModelValidation(""),
+		WithLinkedDataProofContext(&proof.LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2020",
+			SignatureRepresentation: proof.SignatureProofValue,
+			VerificationMethod:      did + "#owner",
+		}),
+	)
+	revocationStorage, suspensionStorage := status.NewMemoryStorage(), status.NewMemoryStorage()
+	revocation := builder.NewStatusListManager(revocationStorage, "https://example.com/status/revocation",
+		status.WithBitstringStatusList())
+	suspension := builder.NewStatusListManager(suspensionStorage, "https://example.com/status/suspension",
+		status.WithBitstringStatusList(), status.WithStatusPurpose(status.StatusPurposeSuspension))
+	assert.Error(t, builder.RegisterTemplate(&Template{}))
+	assert.NoError(t, builder.RegisterTemplate(&Template{
+		Name:           "resident",
+		Types:          []string{"PermanentResidentCard"},
+		StatusPurposes: []string{status.StatusPurposeRevocation, status.StatusPurposeSuspension},
+		Validity:       365 * 24 * time.Hour,
+		RequiredClaims: []string{"id", "givenName", "familyName"},
+	}))
+
+	_, err := builder.IssueFromTemplate("unknown", nil)
+	assert.ErrorContains(t, err, "unknown credential template")
+	_, err = builder.IssueFromTemplate("resident", map[string]interface{}{"givenName": "JOHN"})
+	assert.ErrorContains(t, err, "missing required claims id, familyName")
+
+	claims := map[string]interface{}{
+		"id":         "did:example:b34ca6cd37bbf23",
+		"givenName":  "JOHN",
+		"familyName": "SMITH",
+	}
+	// status list managers are registered once
+	_, err = builder.IssueFromTemplate("resident", claims)
+	assert.ErrorContains(t, err, "no status list manager")
+	cred, err := builder.IssueFromTemplate("resident", claims, WithStatusListManagers(revocation, suspension))
+	require.NoError(t, err)
+	other, err := builder.IssueFromTemplate("resident", claims)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(cred.Id, "urn:uuid:"))
+	assert.NotEqual(t, cred.Id, other.Id)
+	assert.Equal(t, did, cred.IssuerID())
+	assert.Equal(t, []string{"VerifiableCredential", "PermanentResidentCard"}, cred.Type)
+	assert.Equal(t, now, cred.ValidFrom.Time)
+	assert.Equal(t, now.Add(365*24*time.Hour), cred.ValidUntil.Time)
+	assert.Nil(t, cred.IssuanceDate)
+	assert.Equal(t, "JOHN", cred.Subjects()[0]["givenName"])
+	entries, err := credential.GetStatusEntries(cred.Status)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	var lists []*credential.Credential
+	for _, m := range []*status.StatusListManager{revocation, suspension} {
+		ids, err := m.Lists()
+		require.NoError(t, err)
+		list, err := m.StatusCredential(ids[0])
+		require.NoError(t, err)
+		lists = append(lists, list)
+	}
+	results, err := builder.CheckCredentialStatus(cred, lists, pubResv)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	for _, r := range results {
+		assert.False(t, r.Set, r.Purpose)
+	}
+
+	// no status entries are kept for credentials which are not issued
+	used := func() []int {
+		var ret []int
+		for storage, purpose := range map[*status.MemoryStorage]string{
+			revocationStorage: status.StatusPurposeRevocation, suspensionStorage: status.StatusPurposeSuspension,
+		} {
+			recs, err := storage.Lists(purpose)
+			require.NoError(t, err)
+			require.Len(t, recs, 1)
+			ret = append(ret, recs[0].Used)
+		}
+		return ret
+	}
+	assert.Equal(t, []int{2, 2}, used())
+	_, err = builder.IssueFromTemplate("resident", map[string]interface{}{
+		"id":         "not a url",
+		"givenName":  "JOHN",
+		"familyName": "SMITH",
+	})
+	assert.ErrorContains(t, err, "invalid credential")
+	assert.Equal(t, []int{2, 2}, used())
+	_, err = builder.IssueFromTemplate("resident", claims, WithLinkedDataProofContext(&proof.LinkedDataProofContext{
+		SignatureType: "UnknownSignature2020",
+	}))
+	assert.ErrorContains(t, err, "unsupported signature type")
+	assert.Equal(t, []int{2, 2}, used())
+}
+
+func TestKeyDIDVerification(t *testing.T) {
+	priv := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	did, err := resolver.NewKeyDID(priv.Public())
+	require.NoError(t, err)
+	procOpts := WithProcessorOptions(offlineProcessorOptions(t)...)
+	builder := NewVCBuilder(
+		WithEd25519PrivateKey(priv),
+		WithDID(did),
+		procOpts,
+		WithLinkedDataProofContext(&proof.LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2020",
+			SignatureRepresentation: proof.SignatureProofValue,
+			VerificationMethod:      did + "#" + strings.TrimPrefix(did, "did:key:"),
+		}),
+	)
+	cred := getTestCredentialWithName(t, credentialEd25519DocPath)
+	cred.Issuer = credential.NewIssuer(did)
+	signed, err := builder.AddLinkedDataProof(cred)
+	require.NoError(t, err)
+
+	keyResolver := resolver.NewKeyDIDResolver()
+	assert.NoError(t, builder.Verify(signed, keyResolver))
+	result := builder.NewVerifier(keyResolver).Verify(signed)
+	assert.True(t, result.Verified, result.Errors())
+	// the did:key document authorises its key for assertionMethod
+	assert.Equal(t, verifier.OutcomePassed, result.Check(verifier.CheckProofPurpose).Outcome)
+	assert.Empty(t, result.Check(verifier.CheckProofPurpose).Warnings)
+
+	// signed by another key than the did:key one
+	seed := make([]byte, ed25519.SeedSize)
+	seed[0] = 1
+	forged, err := NewVCBuilder(
+		WithEd25519PrivateKey(ed25519.NewKeyFromSeed(seed)),
+		WithDID(did),
+		procOpts,
+		WithLinkedDataProofContext(builder.options.ldpCtx),
+	).AddLinkedDataProof(getTestCredentialWithName(t, credentialEd25519DocPath))
+	assert.NoError(t, err)
+	assert.Error(t, builder.Verify(forged, keyResolver))
+}
+
+func TestHolderDIDPresentation(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwk, err := jose.NewJWK(&p256.PublicKey)
+	require.NoError(t, err)
+	jwkDID, err := resolver.NewJWKDID(jwk)
+	require.NoError(t, err)
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	mk, err := resolver.MultikeyOf(edPub)
+	require.NoError(t, err)
+	peerDID := "did:peer:2.V" + mk + ".A" + mk
+
+	for _, c := range []struct {
+		holder   string
+		vm       string
+		option   BuilderOption
+		ldpCtx   *proof.LinkedDataProofContext
+		resolver resolver.PublicKeyResolver
+	}{
+		{jwkDID, jwkDID + "#0", WithJWSPrivateKey(p256), &proof.LinkedDataProofContext{
+			SignatureType:           "JsonWebSignature2020",
+			SignatureRepresentation: proof.SignatureJWS,
+		}, resolver.NewJWKDIDResolver()},
+		{peerDID, peerDID + "#key-2", WithEd25519PrivateKey(edPriv), &proof.LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2020",
+			SignatureRepresentation: proof.SignatureProofValue,
+		}, resolver.NewPeerDIDResolver()},
+	} {
+		c.ldpCtx.VerificationMethod = c.vm
+		holder := NewPRBuilder(c.option, WithDID(c.holder), WithLinkedDataProofContext(c.ldpCtx),
+			WithProcessorOptions(offlineProcessorOptions(t)...))
+		pr := presentation.NewPresentation()
+		pr.Holder = c.holder
+		pr.Credential = append(pr.Credential, *getTestCredentialWithName(t, credentialEd25519DocPath))
+		pr, err = holder.AddLinkedDataProof(pr)
+		require.NoError(t, err, c.holder)
+		assert.NoError(t, holder.Verify(pr, c.resolver), c.holder)
+		assert.Error(t, holder.Verify(pr, resolver.NewKeyDIDResolver()), c.holder)
+	}
+}
@@ -1,10 +1,14 @@
 package common
 
+// The ssis.cotnetwork.com contexts are not embedded in package ldcontext, they are fetched from
+// network unless registered with ldcontext.WithContext.
 const (
 	DefaultVCJsonLDContext       string = "https://ssis.cotnetwork.com/v1/schema/credentials/v2.0"
 	DefaultBbsJsonLDContext      string = "https://ssis.cotnetwork.com/v1/schema/bbs/v1.1"
 	DefaultStatusVCJsonLDContext string = "https://ssis.cotnetwork.com/v1/schema/status-list/v1.1"
+	VC2JsonLDContext             string = "https://www.w3.org/ns/credentials/v2"
 	DefaultVCJsonLDContextTypeSC string = "StatusList2021Credential"
+	VCJsonLDContextTypeBSC       string = "BitstringStatusListCredential"
 	DefaultVCJsonLDContextTypeVC string = "VerifiableCredential"
 	DefaultVCJsonLDContextTypePR string = "VerifiablePresentation"
 )
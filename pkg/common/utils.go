@@ -6,25 +6,64 @@ import (
 	"time"
 )
 
+// xsdDateTime is a xsd:dateTime without time zone, read as UTC
+const xsdDateTime = "2006-01-02T15:04:05.999999999"
+
 type FormatedTime struct {
 	time.Time
+	// raw is the unmarshalled value, marshalled back while Time is unchanged so signed dates keep their form
+	raw     string
+	rawTime time.Time
+	// parseErr is set when raw is not a xsd:dateTime, the value is reported by credential validation
+	parseErr error
 }
 
 func NewFormatedTime() *FormatedTime {
 	return &FormatedTime{
-		time.Now(),
+		Time: time.Now(),
 	}
 }
 
-func (ft *FormatedTime) UnmarshalJSON(data []byte) (err error) {
+func (ft *FormatedTime) UnmarshalJSON(data []byte) error {
 	if string(data) == "{}" {
 		return nil
 	}
 	s := strings.Trim(string(data), "\"")
-	ft.Time, err = time.Parse(time.RFC3339, string(s))
-	return err
+	ft.Time, ft.parseErr = time.Parse(time.RFC3339, s)
+	if ft.parseErr != nil {
+		if t, xsdErr := time.Parse(xsdDateTime, s); xsdErr == nil {
+			ft.Time, ft.parseErr = t, nil
+		}
+	}
+	ft.raw, ft.rawTime = s, ft.Time
+	return nil
+}
+
+// Err returns the error parsing the unmarshalled value while Time is unchanged, nil when it is a xsd:dateTime
+func (ft *FormatedTime) Err() error {
+	if !ft.Time.Equal(ft.rawTime) {
+		return nil
+	}
+	return ft.parseErr
+}
+
+// Raw returns the unmarshalled value
+func (ft *FormatedTime) Raw() string {
+	return ft.raw
+}
+
+// HasTimeZone reports whether the time has a time zone, as a xsd:dateTimeStamp
+func (ft *FormatedTime) HasTimeZone() bool {
+	if ft.raw == "" || !ft.Time.Equal(ft.rawTime) {
+		return true
+	}
+	_, err := time.Parse(time.RFC3339, ft.raw)
+	return err == nil
 }
 
 func (ft *FormatedTime) MarshalJSON() ([]byte, error) {
+	if ft.raw != "" && ft.Time.Equal(ft.rawTime) {
+		return []byte(fmt.Sprintf("\"%s\"", ft.raw)), nil
+	}
 	return []byte(fmt.Sprintf("\"%s\"", ft.Format(time.RFC3339))), nil
 }
@@ -3,8 +3,10 @@ package common
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFormatedTime(t *testing.T) {
@@ -42,3 +44,35 @@ func TestFormatedTime(t *testing.T) {
 	assert.NoError(t, err)
 	t.Logf("%s\n", str)
 }
+
+func TestFormatedTimeRoundTrip(t *testing.T) {
+	for _, v := range []string{`"2019-12-03T12:19:52Z"`, `"2019-12-03T12:19:52.123Z"`, `"2023-03-30T23:40:52+08:00"`, `"2010-01-01T19:23:24"`} {
+		tm := &FormatedTime{}
+		assert.NoError(t, json.Unmarshal([]byte(v), tm))
+		b, err := json.Marshal(tm)
+		require.NoError(t, err)
+		assert.Equal(t, v, string(b))
+	}
+	tm := &FormatedTime{}
+	assert.NoError(t, json.Unmarshal([]byte(`"2010-01-01T19:23:24"`), tm))
+	assert.Equal(t, time.Date(2010, 1, 1, 19, 23, 24, 0, time.UTC), tm.Time)
+	tm.Time = tm.Add(time.Hour)
+	b, err := json.Marshal(tm)
+	require.NoError(t, err)
+	assert.Equal(t, `"2010-01-01T20:23:24Z"`, string(b))
+	assert.True(t, tm.HasTimeZone())
+
+	// malformed values are kept for validation
+	tm = &FormatedTime{}
+	assert.NoError(t, json.Unmarshal([]byte(`"yesterday"`), tm))
+	assert.Error(t, tm.Err())
+	assert.Equal(t, "yesterday", tm.Raw())
+	b, err = json.Marshal(tm)
+	require.NoError(t, err)
+	assert.Equal(t, `"yesterday"`, string(b))
+
+	tm = &FormatedTime{}
+	assert.NoError(t, json.Unmarshal([]byte(`"2010-01-01T19:23:24"`), tm))
+	assert.NoError(t, tm.Err())
+	assert.False(t, tm.HasTimeZone())
+}
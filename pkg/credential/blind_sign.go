@@ -42,7 +42,7 @@ func (cred *Credential) PreBlindSign(s suite.SignatureSuite, revealDoc *Credenti
 		p.ProofPurpose = defaultProofPurpose
 	}
 	if context.SignatureRepresentation == proof.SignatureJWS {
-		p.JWS = proof.NewJwt().NewHeader(s.Alg() + "..")
+		p.JWS = newDetachedJWS(s)
 	}
 
 	docMsg, err := CreateVerifyData(s, cred.ToMap(), p, opts...)
@@ -54,18 +54,10 @@ func (cred *Credential) PreBlindSign(s suite.SignatureSuite, revealDoc *Credenti
 		return nil, nil, 0, err
 	}
 
-	pid, err := p.PublicKeyId()
+	pubKeyValue, err := getPublicKey(p, issuerPubResolver)
 	if err != nil {
 		return nil, nil, 0, err
 	}
-	pbk, err := issuerPubResolver.Resolve(pid)
-	if pbk == nil {
-		return nil, nil, 0, fmt.Errorf("cannot resolve public key: %w", err)
-	}
-	pubKeyValue := pbk.Value
-	if p.SignatureRepresentation == proof.SignatureJWS {
-		pubKeyValue = pbk.Jwk
-	}
 
 	ctx, revlIdx, msgCout, err := s.(*bbsblssignature2020.SignatureSuite).Blinder.CreateContext(docMsg, recealMsg, pubKeyValue, nonceBytes)
 	return ctx, revlIdx, msgCout, err
@@ -98,7 +90,7 @@ func (cred *Credential) BlindSign(s suite.SignatureSuite, blindCtx *bbs.BlindSig
 		p.ProofPurpose = defaultProofPurpose
 	}
 	if context.SignatureRepresentation == proof.SignatureJWS {
-		p.JWS = proof.NewJwt().NewHeader(s.Alg() + "..")
+		p.JWS = newDetachedJWS(s)
 	}
 
 	revealedVerifyMsgs, err := CreateVerifyData(s, cred.ToMap(), p, opts...)
@@ -139,7 +131,7 @@ func (cred *Credential) CompleteSignature(s suite.SignatureSuite, lcon *proof.Li
 	}
 
 	if context.SignatureRepresentation == proof.SignatureJWS {
-		p.JWS = proof.NewJwt().NewHeader(s.Alg() + "..")
+		p.JWS = newDetachedJWS(s)
 	}
 
 	signature, err := s.(*bbsblssignature2020.SignatureSuite).Blinder.CompleteSignature(blindSig)
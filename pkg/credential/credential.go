@@ -1,36 +1,53 @@
 package credential
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/suutaku/go-vc/pkg/common"
 	"github.com/suutaku/go-vc/pkg/proof"
 )
 
 type Credential struct {
-	Context     []string               `json:"@context,omitempty"`
-	Id          string                 `json:"id,omitempty"`
-	Type        interface{}            `json:"type,omitempty"`
-	Subject     interface{}            `json:"credentialSubject,omitempty"`
-	ValidFrom   *common.FormatedTime   `json:"validFrom,omitempty"`
-	ValidUntil  *common.FormatedTime   `json:"validUntil,omitempty"`
-	Proof       interface{}            `json:"proof,omitempty"`
-	Status      map[string]interface{} `json:"credentialStatus,omitempty"`
-	Issuer      string                 `json:"issuer,omitempty"`
-	Evidence    interface{}            `json:"evidence,omitempty"`
-	Name        string                 `json:"name,omitempty"`
-	Description string                 `json:"description,omitempty"`
-	Holder      string                 `json:"holder,omitempty"`
+	Context    Contexts             `json:"@context,omitempty"`
+	Id         string               `json:"id,omitempty"`
+	Type       interface{}          `json:"type,omitempty"`
+	Subject    interface{}          `json:"credentialSubject,omitempty"`
+	ValidFrom  *common.FormatedTime `json:"validFrom,omitempty"`
+	ValidUntil *common.FormatedTime `json:"validUntil,omitempty"`
+	// VC 1.1 validity period, see ValidityPeriod
+	IssuanceDate   *common.FormatedTime `json:"issuanceDate,omitempty"`
+	ExpirationDate *common.FormatedTime `json:"expirationDate,omitempty"`
+	Proof          interface{}          `json:"proof,omitempty"`
+	Status         interface{}          `json:"credentialStatus,omitempty"`
+	Issuer         *Issuer              `json:"issuer,omitempty"`
+	Evidence       interface{}          `json:"evidence,omitempty"`
+	Name           LangString           `json:"name,omitempty"`
+	Description    LangString           `json:"description,omitempty"`
+	Holder         string               `json:"holder,omitempty"`
 	// for advanced concepts
-	Schema       interface{}            `json:"credentialSchema,omitempty"`
-	Refresh      map[string]interface{} `json:"refreshService,omitempty"`
-	Terms        interface{}            `json:"termsOfUse,omitempty"`
-	CustomFields map[string]interface{} `json:"-"`
+	Schema          interface{}            `json:"credentialSchema,omitempty"`
+	Refresh         map[string]interface{} `json:"refreshService,omitempty"`
+	Terms           interface{}            `json:"termsOfUse,omitempty"`
+	RelatedResource RelatedResources       `json:"relatedResource,omitempty"`
+	CustomFields    map[string]interface{} `json:"-"`
+	// singleContext and singleRelatedResource marshal a single value unmarshalled without array back
+	// the same way, so signed documents keep their shape
+	singleContext         bool
+	singleRelatedResource bool
 }
 
+var (
+	// ErrNotYetValid is returned by CheckValidity before the validity period
+	ErrNotYetValid = errors.New("credential is not yet valid")
+	// ErrExpired is returned by CheckValidity after the validity period
+	ErrExpired = errors.New("credential expired")
+)
+
 func NewCredential() *Credential {
 	return &Credential{}
 }
@@ -41,7 +58,17 @@ func (rc *Credential) MarshalJSON() ([]byte, error) {
 
 	alias := (*Alias)(rc)
 
-	return common.MarshalWithCustomFields(alias, rc.CustomFields)
+	vm, err := common.MergeCustomFields(alias, rc.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+	if rc.singleContext && len(rc.Context) == 1 {
+		vm["@context"] = rc.Context[0]
+	}
+	if rr, ok := vm["relatedResource"].([]interface{}); ok && rc.singleRelatedResource && len(rr) == 1 {
+		vm["relatedResource"] = rr[0]
+	}
+	return json.Marshal(vm)
 }
 
 // UnmarshalJSON defines custom unmarshalling of rawCredential from JSON.
@@ -56,6 +83,102 @@ func (rc *Credential) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	var forms struct {
+		Context         json.RawMessage `json:"@context"`
+		RelatedResource json.RawMessage `json:"relatedResource"`
+	}
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return err
+	}
+	rc.singleContext = isSingleValue(forms.Context)
+	rc.singleRelatedResource = isSingleValue(forms.RelatedResource)
+	return nil
+}
+
+// isSingleValue reports whether a JSON value is present and not an array
+func isSingleValue(data json.RawMessage) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] != '[' && string(data) != "null"
+}
+
+// IssuerID returns the id of the issuer
+func (cred *Credential) IssuerID() string {
+	if cred.Issuer == nil {
+		return ""
+	}
+	return cred.Issuer.ID
+}
+
+// HasType reports whether name is one of the credential types
+func (cred *Credential) HasType(name string) bool {
+	return hasType(cred.Type, name)
+}
+
+// Subjects returns the credentialSubject objects, the value may be an object or an array of objects
+func (cred *Credential) Subjects() []map[string]interface{} {
+	switch s := cred.Subject.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{s}
+	case []map[string]interface{}:
+		return s
+	case []interface{}:
+		ret := make([]map[string]interface{}, 0, len(s))
+		for _, v := range s {
+			if subject, ok := v.(map[string]interface{}); ok {
+				ret = append(ret, subject)
+			}
+		}
+		return ret
+	}
+	return nil
+}
+
+// ValidityPeriod returns validFrom and validUntil, issuanceDate and expirationDate (VC 1.1) are used
+// when they are absent. A nil time is unbounded.
+func (cred *Credential) ValidityPeriod() (from, until *time.Time) {
+	if cred.ValidFrom != nil {
+		from = &cred.ValidFrom.Time
+	} else if cred.IssuanceDate != nil {
+		from = &cred.IssuanceDate.Time
+	}
+	if cred.ValidUntil != nil {
+		until = &cred.ValidUntil.Time
+	} else if cred.ExpirationDate != nil {
+		until = &cred.ExpirationDate.Time
+	}
+	return from, until
+}
+
+// namedDate is a date property of a credential
+type namedDate struct {
+	name  string
+	value *common.FormatedTime
+}
+
+// dates returns the date properties of the credential, nil when absent
+func (cred *Credential) dates() []namedDate {
+	return []namedDate{
+		{"validFrom", cred.ValidFrom},
+		{"validUntil", cred.ValidUntil},
+		{"issuanceDate", cred.IssuanceDate},
+		{"expirationDate", cred.ExpirationDate},
+	}
+}
+
+// CheckValidity checks now is within the validity period, skew is the tolerated clock difference with the issuer
+func (cred *Credential) CheckValidity(now time.Time, skew time.Duration) error {
+	for _, d := range cred.dates() {
+		if d.value != nil && d.value.Err() != nil {
+			return fmt.Errorf("invalid %s %q", d.name, d.value.Raw())
+		}
+	}
+	from, until := cred.ValidityPeriod()
+	if from != nil && now.Add(skew).Before(*from) {
+		return fmt.Errorf("%w before %s", ErrNotYetValid, from.Format(time.RFC3339))
+	}
+	if until != nil && now.Add(-skew).After(*until) {
+		return fmt.Errorf("%w at %s", ErrExpired, until.Format(time.RFC3339))
+	}
 	return nil
 }
 
@@ -138,6 +261,53 @@ func GetProofs(raw interface{}) ([]map[string]interface{}, error) {
 	}
 }
 
+// GetStatusEntries returns the credentialStatus entries, the value may be an entry or an array of entries
+func GetStatusEntries(raw interface{}) ([]map[string]interface{}, error) {
+	return getEntries(raw, "credential status")
+}
+
+// GetSchemaEntries returns the credentialSchema entries, the value may be an entry or an array of entries
+func GetSchemaEntries(raw interface{}) ([]map[string]interface{}, error) {
+	return getEntries(raw, "credential schema")
+}
+
+func getEntries(raw interface{}, name string) ([]map[string]interface{}, error) {
+	switch s := raw.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return []map[string]interface{}{s}, nil
+	case []map[string]interface{}:
+		return s, nil
+	case []interface{}:
+		entries := make([]map[string]interface{}, len(s))
+		for i := range s {
+			entry, ok := s[i].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s is not a JSON map", name)
+			}
+			entries[i] = entry
+		}
+		return entries, nil
+	default:
+		// typed entries, e.g. status.StatusEntry
+		b, err := json.Marshal(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if _, ok := v.(map[string]interface{}); !ok {
+			if _, ok := v.([]interface{}); !ok {
+				return nil, fmt.Errorf("%s is not map or array of maps", name)
+			}
+		}
+		return getEntries(v, name)
+	}
+}
+
 func GetBLSProofs(raw interface{}) ([]map[string]interface{}, error) {
 	allProofs, err := GetProofs(raw)
 	if err != nil {
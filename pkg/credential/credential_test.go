@@ -1 +1,206 @@
 package credential
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suutaku/go-vc/test"
+)
+
+func TestGetStatusEntries(t *testing.T) {
+	entry := map[string]interface{}{"type": "StatusList2021Entry", "statusPurpose": "revocation"}
+	cred := NewCredential()
+	assert.NoError(t, cred.FromBytes([]byte(`{"credentialStatus":[{"statusPurpose":"revocation"},{"statusPurpose":"suspension"}]}`)))
+	entries, err := GetStatusEntries(cred.Status)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "suspension", entries[1]["statusPurpose"])
+
+	entries, err = GetStatusEntries(entry)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{entry}, entries)
+
+	entries, err = GetStatusEntries(nil)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = GetStatusEntries([]interface{}{"revocation"})
+	assert.Error(t, err)
+}
+
+func TestValidityPeriod(t *testing.T) {
+	cred := NewCredential()
+	assert.NoError(t, cred.FromBytes([]byte(`{"issuanceDate":"2019-12-03T12:19:52Z","expirationDate":"2029-12-03T12:19:52.500Z"}`)))
+	assert.NotContains(t, cred.CustomFields, "issuanceDate")
+	assert.NotContains(t, cred.CustomFields, "expirationDate")
+	assert.JSONEq(t, `{"issuanceDate":"2019-12-03T12:19:52Z","expirationDate":"2029-12-03T12:19:52.500Z"}`, string(cred.ToBytes()))
+	from, until := cred.ValidityPeriod()
+	assert.Equal(t, time.Date(2019, 12, 3, 12, 19, 52, 0, time.UTC), from.UTC())
+	assert.Equal(t, time.Date(2029, 12, 3, 12, 19, 52, 5e8, time.UTC), until.UTC())
+
+	assert.NoError(t, cred.CheckValidity(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 0))
+	err := cred.CheckValidity(from.Add(-time.Minute), 0)
+	assert.ErrorIs(t, err, ErrNotYetValid)
+	assert.NoError(t, cred.CheckValidity(from.Add(-time.Minute), 2*time.Minute))
+	err = cred.CheckValidity(until.Add(time.Minute), 0)
+	assert.ErrorIs(t, err, ErrExpired)
+	assert.NoError(t, cred.CheckValidity(until.Add(time.Minute), 2*time.Minute))
+
+	// validFrom and validUntil take precedence
+	cred = NewCredential()
+	assert.NoError(t, cred.FromBytes([]byte(`{"validFrom":"2020-01-01T00:00:00Z","issuanceDate":"2019-12-03T12:19:52Z"}`)))
+	from, until = cred.ValidityPeriod()
+	assert.Equal(t, 2020, from.Year())
+	assert.Nil(t, until)
+	assert.NoError(t, cred.CheckValidity(time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC), 0))
+
+	// malformed dates are not an unbounded validity period
+	cred = NewCredential()
+	assert.NoError(t, cred.FromBytes([]byte(`{"validUntil":"tomorrow"}`)))
+	assert.EqualError(t, cred.CheckValidity(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 0), `invalid validUntil "tomorrow"`)
+}
+
+func TestVC2RoundTrip(t *testing.T) {
+	for _, name := range []string{"vc-json-doc-v2-issuer.json", "vc-json-doc-v2-subjects.json", "vc-json-doc-v2-related.json", "vc-json-doc-all.json"} {
+		t.Run(name, func(t *testing.T) {
+			b, err := test.GetTestResource(name)
+			require.NoError(t, err)
+			cred := NewCredential()
+			assert.NoError(t, cred.FromBytes(b))
+			// marshalled maps have sorted keys, compare with the compact form of the document
+			var doc interface{}
+			require.NoError(t, json.Unmarshal(b, &doc))
+			expected, err := json.Marshal(doc)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(cred.ToBytes()))
+		})
+	}
+
+	b, err := test.GetTestResource("vc-json-doc-v2-related.json")
+	require.NoError(t, err)
+	cred := NewCredential()
+	require.NoError(t, cred.FromBytes(b))
+	require.Len(t, cred.RelatedResource, 1)
+	assert.Equal(t, "Verifiable Credentials Data Model v2.0 context", cred.RelatedResource[0].CustomFields["name"])
+	// a second value is marshalled as an array
+	cred.Context = append(cred.Context, "https://www.w3.org/ns/credentials/examples/v2")
+	cred.RelatedResource = append(cred.RelatedResource, RelatedResource{ID: "https://www.w3.org/ns/credentials/examples/v2"})
+	doc := cred.ToMap()
+	assert.Len(t, doc["@context"], 2)
+	assert.Len(t, doc["relatedResource"], 2)
+}
+
+func TestVC2Types(t *testing.T) {
+	b, err := test.GetTestResource("vc-json-doc-v2-issuer.json")
+	require.NoError(t, err)
+	cred := NewCredential()
+	assert.NoError(t, cred.FromBytes(b))
+	assert.Equal(t, "did:example:76e12ec712ebc6f1c221ebfeb1f", cred.IssuerID())
+	assert.Equal(t, "Example University", cred.Issuer.Name.String())
+	assert.Equal(t, "https://university.example/logo.png", cred.Issuer.Image)
+	assert.Len(t, cred.Name, 3)
+	assert.Equal(t, "Exemple d'Attestation d'Ancien Élève", cred.Name.Lang("fr"))
+	assert.Equal(t, "rtl", cred.Name[2].Direction)
+	assert.Equal(t, "Example Alumni Credential", cred.Name.Lang("de"))
+	assert.Equal(t, "en", cred.Description[0].Language)
+	assert.Len(t, cred.RelatedResource, 2)
+	assert.Equal(t, "image/png", cred.RelatedResource[1].MediaType)
+	assert.Empty(t, cred.CustomFields)
+
+	b, err = test.GetTestResource("vc-json-doc-v2-subjects.json")
+	require.NoError(t, err)
+	cred = NewCredential()
+	assert.NoError(t, cred.FromBytes(b))
+	assert.Equal(t, []string{"https://www.w3.org/ns/credentials/v2"}, cred.Context.URLs())
+	assert.Len(t, cred.Context, 2)
+	assert.Equal(t, "https://issuer.example/issuer/123", cred.IssuerID())
+	assert.Equal(t, "Marriage certificate", cred.Name.String())
+	subjects := cred.Subjects()
+	assert.Len(t, subjects, 2)
+	assert.Equal(t, "Morgan Doe", subjects[1]["name"])
+
+	// a single context and an issuer object with only an id are kept
+	cred = NewCredential()
+	assert.NoError(t, cred.FromBytes([]byte(`{"@context":"https://www.w3.org/ns/credentials/v2","issuer":{"id":"did:example:1"}}`)))
+	assert.Equal(t, Contexts{"https://www.w3.org/ns/credentials/v2"}, cred.Context)
+	assert.JSONEq(t, `{"@context":"https://www.w3.org/ns/credentials/v2","issuer":{"id":"did:example:1"}}`, string(cred.ToBytes()))
+	assert.Error(t, cred.FromBytes([]byte(`{"@context":[1]}`)))
+
+	cred = &Credential{Issuer: NewIssuer("did:example:1"), Name: NewLangString("name")}
+	assert.JSONEq(t, `{"issuer":"did:example:1","name":"name"}`, string(cred.ToBytes()))
+}
+
+func TestValidate(t *testing.T) {
+	for name, version := range map[string]DataModel{
+		"vc-json-doc-all.json":         DataModelV1,
+		"vc-json-doc-v2-issuer.json":   DataModelV2,
+		"vc-json-doc-v2-subjects.json": DataModelV2,
+	} {
+		b, err := test.GetTestResource(name)
+		require.NoError(t, err)
+		cred := NewCredential()
+		assert.NoError(t, cred.FromBytes(b))
+		assert.Equal(t, version, cred.DataModel(), name)
+		assert.Nil(t, cred.Validate(""), name)
+	}
+
+	cred := NewCredential()
+	assert.NoError(t, cred.FromBytes([]byte(`{
+		"@context": ["https://www.w3.org/ns/credentials/v2"],
+		"id": "not a url",
+		"type": ["ExampleCredential"],
+		"validFrom": "2020-01-01T00:00:00Z",
+		"validUntil": "2019-01-01T00:00:00Z",
+		"issuanceDate": "2019-01-01T00:00:00Z",
+		"credentialSubject": [{"id": "did:example:1"}, {}],
+		"credentialStatus": {"type": "BitstringStatusListEntry", "statusListIndex": "-1"},
+		"credentialSchema": [{"type": "JsonSchema"}],
+		"proof": {"type": "DataIntegrityProof", "proofPurpose": "assertionMethod", "verificationMethod": "did:example:1#key-1"}
+	}`)))
+	errs := cred.Validate(DataModelV2)
+	paths := make([]string, len(errs))
+	for i, fe := range errs {
+		paths[i] = fe.Path
+	}
+	assert.Equal(t, []string{
+		"/id",
+		"/type",
+		"/issuer",
+		"/credentialSubject/1",
+		"/issuanceDate",
+		"/validUntil",
+		"/credentialStatus/statusListIndex",
+		"/credentialStatus/statusPurpose",
+		"/credentialStatus/statusListCredential",
+		"/credentialSchema/0/id",
+		"/proof/cryptosuite",
+		"/proof/proofValue",
+	}, paths)
+	assert.Contains(t, errs.Error(), "/issuer: is required")
+
+	// checked against VC 1.1
+	errs = cred.Validate(DataModelV1)
+	assert.Equal(t, "/@context/0", errs[0].Path)
+
+	cred.Schema = []interface{}{"https://example.com/schema.json"}
+	assert.Contains(t, cred.Validate(DataModelV2).Error(), "/credentialSchema: credential schema is not a JSON map")
+
+	// date formats
+	cred = NewCredential()
+	assert.NoError(t, cred.FromBytes([]byte(`{
+		"@context": ["https://www.w3.org/ns/credentials/v2"],
+		"type": ["VerifiableCredential"],
+		"issuer": "did:example:1",
+		"validFrom": "2020-01-01",
+		"validUntil": "2030-01-01T00:00:00",
+		"credentialSubject": {"id": "did:example:2"}
+	}`)))
+	assert.Equal(t, `/validFrom: invalid dateTime "2020-01-01"; `+
+		`/validUntil: "2030-01-01T00:00:00" has no time zone, a dateTimeStamp is required`, cred.Validate("").Error())
+	cred.Context = Contexts{credentialsV1Context}
+	cred.IssuanceDate, cred.ValidFrom, cred.ValidUntil = cred.ValidFrom, nil, nil
+	assert.Equal(t, `/issuanceDate: invalid dateTime "2020-01-01"`, cred.Validate("").Error())
+}
@@ -43,9 +43,25 @@ func (cred *Credential) AddLinkedDataProof(s suite.SignatureSuite, lcon *proof.L
 	if p.ProofPurpose == "" {
 		p.ProofPurpose = defaultProofPurpose
 	}
+	if di, ok := s.(suite.DataIntegritySuite); ok {
+		p.Type = proof.DataIntegrityProof
+		p.Cryptosuite = di.Cryptosuite()
+	}
 
 	if context.SignatureRepresentation == proof.SignatureJWS {
-		p.JWS = proof.NewJwt().NewHeader(s.Alg() + "..")
+		p.JWS = newDetachedJWS(s)
+	}
+
+	if ds, ok := s.(suite.DocumentSigner); ok {
+		if context.SignatureRepresentation != proof.SignatureProofValue {
+			return fmt.Errorf("%s supports proofValue only", s.Alg())
+		}
+		value, err := ds.SignDocument(GetCopyWithoutProof(cred.ToMap()), p.ToMap(), context.MandatoryPointers, opts...)
+		if err != nil {
+			return err
+		}
+		p.ProofValue = value
+		return cred.AddProof(p)
 	}
 
 	message, err := CreateVerifyData(s, cred.ToMap(), p, opts...)
@@ -56,10 +72,36 @@ func (cred *Credential) AddLinkedDataProof(s suite.SignatureSuite, lcon *proof.L
 	if err != nil {
 		return err
 	}
-	p.ApplySignatureValue(context, sig)
+	if err := applySignatureValue(s, p, context, sig); err != nil {
+		return err
+	}
 	return cred.AddProof(p)
 }
 
+// newDetachedJWS returns the detached JWS of suite without signature ("header..")
+func newDetachedJWS(s suite.SignatureSuite) string {
+	alg := s.Alg()
+	if js, ok := s.(suite.JWSSigner); ok {
+		alg = js.JWSAlg()
+	}
+	return proof.NewJwt().NewHeader(alg) + ".."
+}
+
+// applySignatureValue sets signature to the proof, using the suite's own proofValue encoding if it has one.
+func applySignatureValue(s suite.SignatureSuite, p *proof.Proof, context *proof.Context, sig []byte) error {
+	enc, ok := s.(suite.ProofValueEncoder)
+	if !ok || context.SignatureRepresentation != proof.SignatureProofValue {
+		p.ApplySignatureValue(context, sig)
+		return nil
+	}
+	value, err := enc.EncodeProofValue(sig)
+	if err != nil {
+		return err
+	}
+	p.ProofValue = value
+	return nil
+}
+
 // CreateVerifyData creates data that is used to generate or verify a digital signature.
 // It depends on the signature value holder type.
 // In case of "proofValue", the standard Create Verify Hash algorithm is used.
@@ -135,6 +177,9 @@ func createVerifyJWS(s suite.SignatureSuite, jsonldDoc map[string]interface{}, p
 	if err != nil {
 		return nil, err
 	}
+	if _, err := jwtb.ValidateHeader(); err != nil {
+		return nil, err
+	}
 
 	return append([]byte(jwtb.Header()+"."), verifyData...), nil
 }
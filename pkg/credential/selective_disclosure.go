@@ -2,6 +2,7 @@ package credential
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 
 	"github.com/suutaku/go-vc/internal/tools"
@@ -63,6 +64,43 @@ func (cred *Credential) GenerateBBSSelectiveDisclosure(s suite.SignatureSuite, r
 	return ret, nil
 }
 
+// DeriveDataIntegrityProof derives a credential disclosing statements selected by selectivePointers
+// (JSON pointers) and the mandatory pointers of the base proof, using a selective disclosure
+// Data Integrity suite such as bbs-2023. presentationHeader is bound to the derived proof.
+func (cred *Credential) DeriveDataIntegrityProof(s suite.SignatureSuite, selectivePointers []string, presentationHeader []byte, opts ...processor.ProcessorOpts) (*Credential, error) {
+	deriver, ok := s.(suite.ProofDeriver)
+	if !ok {
+		return nil, fmt.Errorf("signature suite %s can not derive proofs", s.Alg())
+	}
+	if cred.Proof == nil {
+		return nil, fmt.Errorf("expected at least one proof present")
+	}
+	proofs, err := GetProofs(cred.Proof)
+	if err != nil {
+		return nil, err
+	}
+	doc := GetCopyWithoutProof(cred.ToMap())
+	for _, pm := range proofs {
+		if proof.NewProofFromMap(pm).SuiteName() != s.Alg() {
+			continue
+		}
+		revealed, proofValue, err := deriver.DeriveDocumentProof(doc, pm, selectivePointers, presentationHeader, opts...)
+		if err != nil {
+			return nil, err
+		}
+		derivedProof := make(map[string]interface{}, len(pm))
+		for k, v := range pm {
+			derivedProof[k] = v
+		}
+		derivedProof[jsonldProofValue] = proofValue
+		revealed[jsonldProof] = derivedProof
+		ret := NewCredential()
+		ret.FromMap(revealed)
+		return ret, nil
+	}
+	return nil, fmt.Errorf("no %s proof present", s.Alg())
+}
+
 func buildDocVerificationData(docCompacted, revealDoc map[string]interface{}, opts ...processor.ProcessorOpts) (*DocVerificationData, error) {
 	// create verify document data
 	docBytes, err := processor.Default().GetCanonicalDocument(docCompacted, opts...)
@@ -156,7 +194,7 @@ func createVerifyProofData(proofMap map[string]interface{}, opts ...processor.Pr
 }
 
 func generateSignatureProof(blsSignature map[string]interface{}, resolver resolver.PublicKeyResolver, nonce []byte, verData *VerificationData, s suite.SignatureSuite) (map[string]interface{}, error) {
-	pubKeyBytes, signatureBytes, pErr := getPublicKeyAndSignature(blsSignature, resolver)
+	pubKeyBytes, signatureBytes, pErr := getPublicKeyAndSignature(s, blsSignature, resolver)
 	if pErr != nil {
 		return nil, fmt.Errorf("get public key and signature: %w", pErr)
 	}
@@ -187,23 +225,41 @@ func toArrayOfBytes(messages []string) [][]byte {
 	return res
 }
 
-func getPublicKeyAndSignature(pmap map[string]interface{}, pubResolver resolver.PublicKeyResolver) ([]byte, []byte, error) {
+func getPublicKeyAndSignature(s suite.SignatureSuite, pmap map[string]interface{}, pubResolver resolver.PublicKeyResolver) ([]byte, []byte, error) {
 	p := proof.NewProofFromMap(pmap)
-	pid, err := p.PublicKeyId()
+	pubKeyValue, err := getPublicKey(p, pubResolver)
 	if err != nil {
 		return nil, nil, err
 	}
-	pbk, err := pubResolver.Resolve(pid)
-	if err != nil {
-		return nil, nil, fmt.Errorf("cannot resolve public key %s: %w", pid, err)
-	}
-	pubKeyValue := pbk.Value
-	if p.SignatureRepresentation == proof.SignatureJWS {
-		pubKeyValue = pbk.Jwk
-	}
 	// get verify value
+	if dec, ok := s.(suite.ProofValueEncoder); ok && p.SignatureRepresentation == proof.SignatureProofValue {
+		signature, err := dec.DecodeProofValue(p.ProofValue)
+		return pubKeyValue, signature, err
+	}
 	signature, err := p.GetProofVerifyValue()
 
 	return pubKeyValue, signature, err
 
 }
+
+// getPublicKey resolves the public key of proof's verification method
+func getPublicKey(p *proof.Proof, pubResolver resolver.PublicKeyResolver) ([]byte, error) {
+	pid, err := p.PublicKeyId()
+	if err != nil {
+		return nil, err
+	}
+	pbk, err := pubResolver.Resolve(pid)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve public key %s: %w", pid, err)
+	}
+	if pbk == nil {
+		return nil, fmt.Errorf("cannot resolve public key %s", pid)
+	}
+	if p.SignatureRepresentation == proof.SignatureJWS {
+		if pbk.Jwk == nil {
+			return nil, fmt.Errorf("public key %s has no jwk", pid)
+		}
+		return json.Marshal(pbk.Jwk)
+	}
+	return pbk.Value, nil
+}
@@ -0,0 +1,220 @@
+package credential
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/suutaku/go-vc/pkg/common"
+)
+
+// Contexts is the @context of a credential, entries are context URLs or inline context objects
+type Contexts []interface{}
+
+// URLs returns the context URLs, inline contexts are skipped
+func (c Contexts) URLs() []string {
+	ret := make([]string, 0, len(c))
+	for _, v := range c {
+		if url, ok := v.(string); ok {
+			ret = append(ret, url)
+		}
+	}
+	return ret
+}
+
+// UnmarshalJSON reads a context URL, an inline context or an array of them
+func (c *Contexts) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch ctx := v.(type) {
+	case []interface{}:
+		*c = ctx
+	case string, map[string]interface{}:
+		*c = Contexts{ctx}
+	case nil:
+		*c = nil
+	default:
+		return fmt.Errorf("invalid @context %s", data)
+	}
+	for _, entry := range *c {
+		switch entry.(type) {
+		case string, map[string]interface{}:
+		default:
+			return fmt.Errorf("invalid @context entry %v", entry)
+		}
+	}
+	return nil
+}
+
+// LanguageValue is a value object of a natural language string
+// https://www.w3.org/TR/vc-data-model-2.0/#language-and-base-direction
+type LanguageValue struct {
+	Value     string `json:"@value"`
+	Language  string `json:"@language,omitempty"`
+	Direction string `json:"@direction,omitempty"`
+}
+
+// LangString is a natural language property like name or description, with one value per language.
+// A single value without language is a plain JSON string.
+type LangString []LanguageValue
+
+// NewLangString creates a LangString of a plain string
+func NewLangString(value string) LangString {
+	return LangString{{Value: value}}
+}
+
+// String returns the first value
+func (ls LangString) String() string {
+	if len(ls) == 0 {
+		return ""
+	}
+	return ls[0].Value
+}
+
+// Lang returns the value of language, the first value when no value has that language
+func (ls LangString) Lang(language string) string {
+	for _, v := range ls {
+		if v.Language == language {
+			return v.Value
+		}
+	}
+	return ls.String()
+}
+
+func (ls LangString) MarshalJSON() ([]byte, error) {
+	switch {
+	case len(ls) == 1 && ls[0].Language == "" && ls[0].Direction == "":
+		return json.Marshal(ls[0].Value)
+	case len(ls) == 1:
+		return json.Marshal(ls[0])
+	}
+	return json.Marshal([]LanguageValue(ls))
+}
+
+func (ls *LangString) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch value := v.(type) {
+	case nil:
+		*ls = nil
+		return nil
+	case string:
+		*ls = LangString{{Value: value}}
+		return nil
+	case map[string]interface{}:
+		lv := LanguageValue{}
+		if err := json.Unmarshal(data, &lv); err != nil {
+			return err
+		}
+		*ls = LangString{lv}
+		return nil
+	}
+	values := make([]LanguageValue, 0)
+	var plain []interface{}
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return err
+	}
+	for _, p := range plain {
+		b, _ := json.Marshal(p)
+		value := LangString{}
+		if err := value.UnmarshalJSON(b); err != nil {
+			return err
+		}
+		values = append(values, value...)
+	}
+	*ls = values
+	return nil
+}
+
+// Issuer is the issuer of a credential, an URL or an object with an id
+// https://www.w3.org/TR/vc-data-model-2.0/#issuer
+type Issuer struct {
+	ID          string      `json:"id,omitempty"`
+	Name        LangString  `json:"name,omitempty"`
+	Description LangString  `json:"description,omitempty"`
+	Image       interface{} `json:"image,omitempty"`
+	// All unmapped fields are put here.
+	CustomFields map[string]interface{} `json:"-"`
+	// object marshals an issuer having only an id as an object
+	object bool
+}
+
+// NewIssuer creates an issuer of id, nil when id is empty
+func NewIssuer(id string) *Issuer {
+	if id == "" {
+		return nil
+	}
+	return &Issuer{ID: id}
+}
+
+func (is *Issuer) MarshalJSON() ([]byte, error) {
+	if !is.object && is.Name == nil && is.Description == nil && is.Image == nil && len(is.CustomFields) == 0 {
+		return json.Marshal(is.ID)
+	}
+	type Alias Issuer
+	return common.MarshalWithCustomFields((*Alias)(is), is.CustomFields)
+}
+
+func (is *Issuer) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*is = Issuer{ID: id}
+		return nil
+	}
+	type Alias Issuer
+	*is = Issuer{CustomFields: make(map[string]interface{}), object: true}
+	if err := common.UnmarshalWithCustomFields(data, (*Alias)(is), is.CustomFields); err != nil {
+		return fmt.Errorf("invalid issuer: %w", err)
+	}
+	return nil
+}
+
+// RelatedResource is a resource referenced by a credential with the digest of its content
+// https://www.w3.org/TR/vc-data-model-2.0/#integrity-of-related-resources
+type RelatedResource struct {
+	ID              string `json:"id"`
+	DigestSRI       string `json:"digestSRI,omitempty"`
+	DigestMultibase string `json:"digestMultibase,omitempty"`
+	MediaType       string `json:"mediaType,omitempty"`
+	// All unmapped fields are put here.
+	CustomFields map[string]interface{} `json:"-"`
+}
+
+func (r *RelatedResource) MarshalJSON() ([]byte, error) {
+	type Alias RelatedResource
+	return common.MarshalWithCustomFields((*Alias)(r), r.CustomFields)
+}
+
+func (r *RelatedResource) UnmarshalJSON(data []byte) error {
+	type Alias RelatedResource
+	*r = RelatedResource{CustomFields: make(map[string]interface{})}
+	return common.UnmarshalWithCustomFields(data, (*Alias)(r), r.CustomFields)
+}
+
+// RelatedResources is the relatedResource of a credential, one object or an array
+type RelatedResources []RelatedResource
+
+func (rr *RelatedResources) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if _, ok := v.(map[string]interface{}); ok {
+		var single RelatedResource
+		if err := json.Unmarshal(data, &single); err != nil {
+			return fmt.Errorf("invalid relatedResource: %w", err)
+		}
+		*rr = RelatedResources{single}
+		return nil
+	}
+	var ret []RelatedResource
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return errors.New("relatedResource is not object or array of objects")
+	}
+	*rr = ret
+	return nil
+}
@@ -0,0 +1,292 @@
+package credential
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/suutaku/go-vc/pkg/common"
+)
+
+// DataModel is a version of the Verifiable Credentials Data Model
+type DataModel string
+
+const (
+	DataModelV1 DataModel = "1.1"
+	DataModelV2 DataModel = "2.0"
+
+	// credentialsV1Context is the base context of VC 1.1 credentials
+	credentialsV1Context = "https://www.w3.org/2018/credentials/v1"
+)
+
+// FieldError is a data model error of the field at Path, a JSON pointer
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+func (fe *FieldError) Error() string {
+	return fe.Path + ": " + fe.Message
+}
+
+// ValidationErrors lists the data model errors of a credential
+type ValidationErrors []*FieldError
+
+func (ve ValidationErrors) Error() string {
+	msgs := make([]string, len(ve))
+	for i, fe := range ve {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (ve *ValidationErrors) add(path, format string, args ...interface{}) {
+	*ve = append(*ve, &FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// DataModel returns the data model version of the credential given by its first context
+func (cred *Credential) DataModel() DataModel {
+	if len(cred.Context) > 0 && cred.Context[0] == common.VC2JsonLDContext {
+		return DataModelV2
+	}
+	return DataModelV1
+}
+
+// Validate checks the credential against the rules of data model version, the version of the
+// credential is used when version is empty. It returns nil when the credential is well-formed.
+func (cred *Credential) Validate(version DataModel) ValidationErrors {
+	if version == "" {
+		version = cred.DataModel()
+	}
+	var ret ValidationErrors
+	cred.validateContext(version, &ret)
+	if cred.Id != "" && !isURL(cred.Id) {
+		ret.add("/id", "%q is not a URL", cred.Id)
+	}
+	if !hasType(cred.Type, common.DefaultVCJsonLDContextTypeVC) {
+		ret.add("/type", "must include %s", common.DefaultVCJsonLDContextTypeVC)
+	}
+	switch {
+	case cred.Issuer == nil || cred.Issuer.ID == "":
+		ret.add("/issuer", "is required")
+	case !isURL(cred.Issuer.ID):
+		ret.add("/issuer", "%q is not a URL", cred.Issuer.ID)
+	}
+	cred.validateSubject(&ret)
+	cred.validateDates(version, &ret)
+	// status entries, proofs and schemas may be typed values, check their JSON form
+	doc := cred.ToMap()
+	validateStatus(doc["credentialStatus"], &ret)
+	validateSchema(doc["credentialSchema"], &ret)
+	validateProof(doc["proof"], &ret)
+	if len(ret) == 0 {
+		return nil
+	}
+	return ret
+}
+
+func (cred *Credential) validateContext(version DataModel, ret *ValidationErrors) {
+	if len(cred.Context) == 0 {
+		ret.add("/@context", "is required")
+		return
+	}
+	switch version {
+	case DataModelV2:
+		if cred.Context[0] != common.VC2JsonLDContext {
+			ret.add("/@context/0", "must be %s", common.VC2JsonLDContext)
+		}
+	case DataModelV1:
+		if cred.Context[0] != credentialsV1Context && cred.Context[0] != common.DefaultVCJsonLDContext {
+			ret.add("/@context/0", "must be %s", credentialsV1Context)
+		}
+	default:
+		ret.add("/@context", "unsupported data model %s", version)
+	}
+}
+
+func (cred *Credential) validateSubject(ret *ValidationErrors) {
+	if cred.Subject == nil {
+		ret.add("/credentialSubject", "is required")
+		return
+	}
+	subjects := cred.Subjects()
+	if _, ok := cred.Subject.(map[string]interface{}); !ok && len(subjects) == 0 {
+		ret.add("/credentialSubject", "must be an object or an array of objects")
+		return
+	}
+	for i, subject := range subjects {
+		path := "/credentialSubject"
+		if _, ok := cred.Subject.(map[string]interface{}); !ok {
+			path += "/" + strconv.Itoa(i)
+		}
+		if len(subject) == 0 {
+			ret.add(path, "must have at least one claim")
+		}
+		if id, ok := subject["id"]; ok {
+			if s, _ := id.(string); !isURL(s) {
+				ret.add(path+"/id", "%v is not a URL", id)
+			}
+		}
+	}
+}
+
+func (cred *Credential) validateDates(version DataModel, ret *ValidationErrors) {
+	if version == DataModelV1 && cred.IssuanceDate == nil {
+		ret.add("/issuanceDate", "is required")
+	}
+	if version == DataModelV2 {
+		if cred.IssuanceDate != nil {
+			ret.add("/issuanceDate", "is not defined by data model %s, use validFrom", version)
+		}
+		if cred.ExpirationDate != nil {
+			ret.add("/expirationDate", "is not defined by data model %s, use validUntil", version)
+		}
+	}
+	valid := true
+	for _, d := range cred.dates() {
+		switch {
+		case d.value == nil:
+		case d.value.Err() != nil:
+			ret.add("/"+d.name, "invalid dateTime %q", d.value.Raw())
+			valid = false
+		case version == DataModelV2 && (d.value == cred.ValidFrom || d.value == cred.ValidUntil) && !d.value.HasTimeZone():
+			// validFrom and validUntil are xsd:dateTimeStamp in VC 2.0
+			ret.add("/"+d.name, "%q has no time zone, a dateTimeStamp is required", d.value.Raw())
+		}
+	}
+	from, until := cred.ValidityPeriod()
+	if valid && from != nil && until != nil && until.Before(*from) {
+		path := "/validUntil"
+		if cred.ValidUntil == nil {
+			path = "/expirationDate"
+		}
+		ret.add(path, "is before the start of the validity period")
+	}
+}
+
+func validateStatus(raw interface{}, ret *ValidationErrors) {
+	entries, err := GetStatusEntries(raw)
+	if err != nil {
+		ret.add("/credentialStatus", "%v", err)
+		return
+	}
+	for i, entry := range entries {
+		path := "/credentialStatus"
+		if _, ok := raw.([]interface{}); ok {
+			path += "/" + strconv.Itoa(i)
+		}
+		if _, ok := entry["type"]; !ok {
+			ret.add(path+"/type", "is required")
+		}
+		validateURL(entry, "id", path, false, ret)
+		if _, ok := entry["statusListIndex"]; !ok {
+			continue
+		}
+		// StatusList2021Entry and BitstringStatusListEntry
+		if idx, _ := entry["statusListIndex"].(string); !isIndex(idx) {
+			ret.add(path+"/statusListIndex", "must be a non-negative integer string")
+		}
+		if s, _ := entry["statusPurpose"].(string); s == "" {
+			ret.add(path+"/statusPurpose", "is required")
+		}
+		validateURL(entry, "statusListCredential", path, true, ret)
+	}
+}
+
+func validateSchema(raw interface{}, ret *ValidationErrors) {
+	schemas, err := GetSchemaEntries(raw)
+	if err != nil {
+		ret.add("/credentialSchema", "%v", err)
+		return
+	}
+	for i, schema := range schemas {
+		path := "/credentialSchema"
+		if _, ok := raw.([]interface{}); ok {
+			path += "/" + strconv.Itoa(i)
+		}
+		validateURL(schema, "id", path, true, ret)
+		if _, ok := schema["type"]; !ok {
+			ret.add(path+"/type", "is required")
+		}
+	}
+}
+
+func validateProof(raw interface{}, ret *ValidationErrors) {
+	if raw == nil {
+		return
+	}
+	proofs, err := GetProofs(raw)
+	if err != nil {
+		ret.add("/proof", "%v", err)
+		return
+	}
+	for i, p := range proofs {
+		path := "/proof"
+		if _, ok := raw.([]interface{}); ok {
+			path += "/" + strconv.Itoa(i)
+		}
+		proofType, _ := p["type"].(string)
+		if proofType == "" {
+			ret.add(path+"/type", "is required")
+		}
+		if s, _ := p["proofPurpose"].(string); s == "" {
+			ret.add(path+"/proofPurpose", "is required")
+		}
+		if _, ok := p["verificationMethod"]; ok || p["creator"] == nil {
+			validateURL(p, "verificationMethod", path, true, ret)
+		}
+		if proofType == "DataIntegrityProof" {
+			if s, _ := p["cryptosuite"].(string); s == "" {
+				ret.add(path+"/cryptosuite", "is required")
+			}
+		}
+		if p["proofValue"] == nil && p["jws"] == nil {
+			ret.add(path+"/proofValue", "is required")
+		}
+	}
+}
+
+// validateURL checks the URL property key of obj
+func validateURL(obj map[string]interface{}, key, path string, required bool, ret *ValidationErrors) {
+	v, ok := obj[key]
+	if !ok {
+		if required {
+			ret.add(path+"/"+key, "is required")
+		}
+		return
+	}
+	if s, _ := v.(string); !isURL(s) {
+		ret.add(path+"/"+key, "%v is not a URL", v)
+	}
+}
+
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && (u.Opaque != "" || u.Host != "" || u.Path != "")
+}
+
+func isIndex(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
+
+func hasType(t interface{}, name string) bool {
+	switch v := t.(type) {
+	case string:
+		return v == name
+	case []string:
+		for _, s := range v {
+			if s == name {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, s := range v {
+			if s == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -19,12 +19,31 @@ func (cred *Credential) VerifyProof(ss map[string]suite.SignatureSuite, pubResol
 	}
 	for _, pm := range proofs {
 		p := proof.NewProofFromMap(pm)
-		s := ss[p.Type]
-		messages, err := CreateVerifyData(s, cred.ToMap(), p, opts...)
+		s, ok := ss[p.SuiteName()]
+		if !ok || s == nil {
+			return fmt.Errorf("unsupported proof type %s", p.SuiteName())
+		}
+		if dv, ok := s.(suite.DocumentVerifier); ok {
+			pubKeyValue, err := getPublicKey(p, pubResolver)
+			if err != nil {
+				return err
+			}
+			if err := dv.VerifyDocument(GetCopyWithoutProof(cred.ToMap()), p.ToMap(), pubKeyValue, opts...); err != nil {
+				return err
+			}
+			continue
+		}
+		pubKeyValue, signature, err := getPublicKeyAndSignature(s, p.ToMap(), pubResolver)
 		if err != nil {
 			return err
 		}
-		pubKeyValue, signature, err := getPublicKeyAndSignature(p.ToMap(), pubResolver)
+		if binder, ok := s.(suite.PublicKeyBinder); ok {
+			s, err = binder.BindPublicKey(pubKeyValue)
+			if err != nil {
+				return err
+			}
+		}
+		messages, err := CreateVerifyData(s, cred.ToMap(), p, opts...)
 		if err != nil {
 			return err
 		}
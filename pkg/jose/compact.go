@@ -0,0 +1,53 @@
+package jose
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SignCompact creates a compact serialized JWS of header and payload
+func SignCompact(header, payload interface{}, signer Signer) (string, error) {
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encode(headerBytes) + "." + encode(payloadBytes)
+	sig, err := signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + encode(sig), nil
+}
+
+// ParseCompact decodes header and payload of a compact serialized JWS without verifying it,
+// and returns the signing input and signature
+func ParseCompact(token string, header, payload interface{}) (string, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", nil, fmt.Errorf("invalid compact jws")
+	}
+	if err := decodeJSON(parts[0], header); err != nil {
+		return "", nil, fmt.Errorf("invalid jws header: %w", err)
+	}
+	if err := decodeJSON(parts[1], payload); err != nil {
+		return "", nil, fmt.Errorf("invalid jws payload: %w", err)
+	}
+	sig, err := decode(parts[2])
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid jws signature: %w", err)
+	}
+	return parts[0] + "." + parts[1], sig, nil
+}
+
+func decodeJSON(s string, v interface{}) error {
+	b, err := decode(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
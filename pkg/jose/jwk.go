@@ -0,0 +1,192 @@
+// Package jose implements JSON Web Keys (RFC 7517) and the JSON Web Signature (RFC 7515)
+// algorithms used by the JWS based signature suites and JWT credentials.
+package jose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+const (
+	KeyTypeEC  = "EC"
+	KeyTypeOKP = "OKP"
+	KeyTypeRSA = "RSA"
+
+	CurveP256      = "P-256"
+	CurveP384      = "P-384"
+	CurveSecp256k1 = "secp256k1"
+	CurveEd25519   = "Ed25519"
+)
+
+// JWK is a public JSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+}
+
+// ParseJWK parses a JSON encoded JWK
+func ParseJWK(b []byte) (*JWK, error) {
+	ret := &JWK{}
+	if err := json.Unmarshal(b, ret); err != nil {
+		return nil, fmt.Errorf("invalid jwk: %w", err)
+	}
+	if ret.Kty == "" {
+		return nil, fmt.Errorf("invalid jwk: kty is missing")
+	}
+	return ret, nil
+}
+
+// NewJWK creates JWK of a public key
+func NewJWK(pub crypto.PublicKey) (*JWK, error) {
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		var crv string
+		switch k.Curve {
+		case elliptic.P256():
+			crv = CurveP256
+		case elliptic.P384():
+			crv = CurveP384
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return &JWK{
+			Kty: KeyTypeEC,
+			Crv: crv,
+			X:   encode(k.X.FillBytes(make([]byte, size))),
+			Y:   encode(k.Y.FillBytes(make([]byte, size))),
+		}, nil
+	case *secp256k1.PublicKey:
+		b := k.SerializeUncompressed()
+		return &JWK{
+			Kty: KeyTypeEC,
+			Crv: CurveSecp256k1,
+			X:   encode(b[1:33]),
+			Y:   encode(b[33:]),
+		}, nil
+	case ed25519.PublicKey:
+		return &JWK{
+			Kty: KeyTypeOKP,
+			Crv: CurveEd25519,
+			X:   encode(k),
+		}, nil
+	case *rsa.PublicKey:
+		return &JWK{
+			Kty: KeyTypeRSA,
+			N:   encode(k.N.Bytes()),
+			E:   encode(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type %T", pub)
+}
+
+// ParseSEC1PublicKey parses a SEC1 compressed or uncompressed P-256/P-384 public key
+func ParseSEC1PublicKey(b []byte) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch len(b) {
+	case 33, 65:
+		curve = elliptic.P256()
+	case 49, 97:
+		curve = elliptic.P384()
+	default:
+		return nil, fmt.Errorf("unsupported ecdsa public key size %d", len(b))
+	}
+	var x, y *big.Int
+	if b[0] == 0x04 {
+		x, y = elliptic.Unmarshal(curve, b)
+	} else {
+		x, y = elliptic.UnmarshalCompressed(curve, b)
+	}
+	if x == nil {
+		return nil, fmt.Errorf("invalid ecdsa public key")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// PublicKey returns the crypto public key of the JWK, one of *ecdsa.PublicKey,
+// *secp256k1.PublicKey, ed25519.PublicKey or *rsa.PublicKey
+func (jwk *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case KeyTypeEC:
+		x, err := decode(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk x: %w", err)
+		}
+		y, err := decode(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk y: %w", err)
+		}
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case CurveP256:
+			curve = elliptic.P256()
+		case CurveP384:
+			curve = elliptic.P384()
+		case CurveSecp256k1:
+			if len(x) != 32 || len(y) != 32 {
+				return nil, fmt.Errorf("invalid secp256k1 jwk")
+			}
+			return secp256k1.ParsePubKey(append(append([]byte{0x04}, x...), y...))
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Crv)
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, fmt.Errorf("invalid jwk: point not on curve %s", jwk.Crv)
+		}
+		return pub, nil
+	case KeyTypeOKP:
+		if jwk.Crv != CurveEd25519 {
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Crv)
+		}
+		x, err := decode(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 jwk")
+		}
+		return ed25519.PublicKey(x), nil
+	case KeyTypeRSA:
+		n, err := decode(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk n: %w", err)
+		}
+		e, err := decode(jwk.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("invalid jwk e")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", jwk.Kty)
+}
+
+// Equal reports whether both JWKs describe the same key
+func (jwk *JWK) Equal(other *JWK) bool {
+	if jwk == nil || other == nil {
+		return jwk == other
+	}
+	return jwk.Kty == other.Kty && jwk.Crv == other.Crv && jwk.X == other.X &&
+		jwk.Y == other.Y && jwk.N == other.N && jwk.E == other.E
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
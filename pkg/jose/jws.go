@@ -0,0 +1,161 @@
+package jose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secp256k1ecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// JWS algorithms (RFC 7518, RFC 8037 and RFC 8812)
+const (
+	AlgES256  = "ES256"
+	AlgES384  = "ES384"
+	AlgES256K = "ES256K"
+	AlgEdDSA  = "EdDSA"
+	AlgPS256  = "PS256"
+)
+
+// Signer creates JWS signatures
+type Signer interface {
+	// Alg returns the JWS alg header value
+	Alg() string
+	// Sign signs the JWS signing input
+	Sign(data []byte) ([]byte, error)
+}
+
+type keySigner struct {
+	alg  string
+	priv crypto.PrivateKey
+}
+
+// NewSigner returns a Signer of the private key, which is one of *ecdsa.PrivateKey (P-256 or P-384),
+// *secp256k1.PrivateKey, ed25519.PrivateKey or *rsa.PrivateKey
+func NewSigner(priv crypto.PrivateKey) (Signer, error) {
+	switch k := priv.(type) {
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return &keySigner{AlgES256, k}, nil
+		case elliptic.P384():
+			return &keySigner{AlgES384, k}, nil
+		}
+		return nil, fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+	case *secp256k1.PrivateKey:
+		return &keySigner{AlgES256K, k}, nil
+	case ed25519.PrivateKey:
+		return &keySigner{AlgEdDSA, k}, nil
+	case *rsa.PrivateKey:
+		return &keySigner{AlgPS256, k}, nil
+	}
+	return nil, fmt.Errorf("unsupported private key type %T", priv)
+}
+
+func (ks *keySigner) Alg() string {
+	return ks.alg
+}
+
+func (ks *keySigner) Sign(data []byte) ([]byte, error) {
+	switch k := ks.priv.(type) {
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest(ks.alg, data))
+		if err != nil {
+			return nil, err
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...), nil
+	case *secp256k1.PrivateKey:
+		// compact signature is recovery code || r || s
+		return secp256k1ecdsa.SignCompact(k, digest(ks.alg, data), false)[1:], nil
+	case ed25519.PrivateKey:
+		return ed25519.Sign(k, data), nil
+	case *rsa.PrivateKey:
+		return rsa.SignPSS(rand.Reader, k, crypto.SHA256, digest(ks.alg, data),
+			&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	}
+	return nil, fmt.Errorf("unsupported private key type %T", ks.priv)
+}
+
+// Verify verifies the JWS signature of data with the alg and public key
+func Verify(alg string, jwk *JWK, data, signature []byte) error {
+	if jwk == nil {
+		return fmt.Errorf("jwk was empty")
+	}
+	if jwk.Alg != "" && jwk.Alg != alg {
+		return fmt.Errorf("jwk is restricted to alg %s", jwk.Alg)
+	}
+	pub, err := jwk.PublicKey()
+	if err != nil {
+		return err
+	}
+	if err := CheckKeyAlg(alg, pub); err != nil {
+		return err
+	}
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf("invalid signature size")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest(alg, data), r, s) {
+			return fmt.Errorf("invalid signature")
+		}
+	case *secp256k1.PublicKey:
+		var r, s secp256k1.ModNScalar
+		if len(signature) != 64 || r.SetByteSlice(signature[:32]) || s.SetByteSlice(signature[32:]) {
+			return fmt.Errorf("invalid signature size")
+		}
+		if !secp256k1ecdsa.NewSignature(&r, &s).Verify(digest(alg, data), k) {
+			return fmt.Errorf("invalid signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, data, signature) {
+			return fmt.Errorf("invalid signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPSS(k, crypto.SHA256, digest(alg, data), signature,
+			&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}); err != nil {
+			return fmt.Errorf("invalid signature: %w", err)
+		}
+	}
+	return nil
+}
+
+// CheckKeyAlg returns an error if the public key can not be used with the alg
+func CheckKeyAlg(alg string, pub crypto.PublicKey) error {
+	ok := false
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		ok = (alg == AlgES256 && k.Curve == elliptic.P256()) || (alg == AlgES384 && k.Curve == elliptic.P384())
+	case *secp256k1.PublicKey:
+		ok = alg == AlgES256K
+	case ed25519.PublicKey:
+		ok = alg == AlgEdDSA
+	case *rsa.PublicKey:
+		ok = alg == AlgPS256
+	}
+	if !ok {
+		return fmt.Errorf("alg %s not match key type %T", alg, pub)
+	}
+	return nil
+}
+
+func digest(alg string, data []byte) []byte {
+	if alg == AlgES384 {
+		h := sha512.Sum384(data)
+		return h[:]
+	}
+	h := sha256.Sum256(data)
+	return h[:]
+}
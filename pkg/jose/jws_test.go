@@ -0,0 +1,84 @@
+package jose
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	k1, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	_, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaPriv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys := []struct {
+		alg  string
+		priv crypto.PrivateKey
+		pub  crypto.PublicKey
+	}{
+		{AlgES256, p256, &p256.PublicKey},
+		{AlgES256K, k1, k1.PubKey()},
+		{AlgEdDSA, edPriv, edPriv.Public()},
+		{AlgPS256, rsaPriv, &rsaPriv.PublicKey},
+	}
+	data := []byte("eyJhbGciOiJFUzI1NiJ9.payload")
+	for _, k := range keys {
+		t.Run(k.alg, func(t *testing.T) {
+			signer, err := NewSigner(k.priv)
+			require.NoError(t, err)
+			assert.Equal(t, k.alg, signer.Alg())
+			sig, err := signer.Sign(data)
+			require.NoError(t, err)
+
+			jwk, err := NewJWK(k.pub)
+			require.NoError(t, err)
+			b, err := json.Marshal(jwk)
+			require.NoError(t, err)
+			parsed, err := ParseJWK(b)
+			require.NoError(t, err)
+			assert.True(t, jwk.Equal(parsed))
+
+			assert.NoError(t, Verify(k.alg, parsed, data, sig))
+			assert.Error(t, Verify(k.alg, parsed, []byte("tampered"), sig))
+		})
+	}
+
+	jwk, err := NewJWK(&p256.PublicKey)
+	require.NoError(t, err)
+	sig, err := (&keySigner{AlgES256, p256}).Sign(data)
+	require.NoError(t, err)
+	assert.Error(t, Verify(AlgEdDSA, jwk, data, sig), "alg must match key type")
+}
+
+func TestParseSEC1PublicKey(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384()} {
+		priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+		require.NoError(t, err)
+		for _, b := range [][]byte{
+			elliptic.Marshal(curve, priv.X, priv.Y),
+			elliptic.MarshalCompressed(curve, priv.X, priv.Y),
+		} {
+			pub, err := ParseSEC1PublicKey(b)
+			require.NoError(t, err)
+			assert.True(t, priv.PublicKey.Equal(pub))
+		}
+	}
+	_, err := ParseSEC1PublicKey(make([]byte, 32))
+	assert.Error(t, err)
+	_, err = ParseSEC1PublicKey(make([]byte, 33))
+	assert.Error(t, err)
+}
@@ -0,0 +1,261 @@
+package jwtvc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/suutaku/go-vc/pkg/credential"
+	"github.com/suutaku/go-vc/pkg/presentation"
+)
+
+const credentialsV2Context = "https://www.w3.org/ns/credentials/v2"
+
+// Audience is the aud claim, a single audience is encoded as a string
+type Audience []string
+
+func (aud Audience) MarshalJSON() ([]byte, error) {
+	if len(aud) == 1 {
+		return json.Marshal(aud[0])
+	}
+	return json.Marshal([]string(aud))
+}
+
+func (aud *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*aud = Audience{single}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return fmt.Errorf("invalid aud claim: %w", err)
+	}
+	*aud = multi
+	return nil
+}
+
+// Contains reports whether audience is one of the aud claim values
+func (aud Audience) Contains(audience string) bool {
+	for _, v := range aud {
+		if v == audience {
+			return true
+		}
+	}
+	return false
+}
+
+// Claims are the JWT claims of a credential (vc) or presentation (vp)
+type Claims struct {
+	Issuer    string                 `json:"iss,omitempty"`
+	Subject   string                 `json:"sub,omitempty"`
+	Audience  Audience               `json:"aud,omitempty"`
+	NotBefore int64                  `json:"nbf,omitempty"`
+	IssuedAt  int64                  `json:"iat,omitempty"`
+	Expiry    int64                  `json:"exp,omitempty"`
+	ID        string                 `json:"jti,omitempty"`
+	Nonce     string                 `json:"nonce,omitempty"`
+	VC        map[string]interface{} `json:"vc,omitempty"`
+	VP        map[string]interface{} `json:"vp,omitempty"`
+}
+
+// CredentialToClaims converts credential to JWT claims, issuer, id, credentialSubject.id and
+// validity dates are moved to iss, jti, sub, nbf and exp claims.
+func CredentialToClaims(cred *credential.Credential) (*Claims, error) {
+	vc := cred.ToMapWithoutProof()
+	ret := &Claims{VC: vc}
+	switch issuer := vc["issuer"].(type) {
+	case string:
+		ret.Issuer = issuer
+		delete(vc, "issuer")
+	case map[string]interface{}:
+		ret.Issuer, _ = issuer["id"].(string)
+	}
+	if id, ok := vc["id"].(string); ok {
+		ret.ID = id
+		delete(vc, "id")
+	}
+	if subject, ok := vc["credentialSubject"].(map[string]interface{}); ok {
+		if id, ok := subject["id"].(string); ok {
+			ret.Subject = id
+			delete(subject, "id")
+		}
+	}
+	var err error
+	if ret.NotBefore, err = popTime(vc, "validFrom", "issuanceDate"); err != nil {
+		return nil, err
+	}
+	if ret.Expiry, err = popTime(vc, "validUntil", "expirationDate"); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ToCredential converts claims back to the credential
+func (c *Claims) ToCredential() (*credential.Credential, error) {
+	if c.VC == nil {
+		return nil, fmt.Errorf("jwt has no vc claim")
+	}
+	vc := copyMap(c.VC)
+	if c.Issuer != "" {
+		if issuer, ok := vc["issuer"].(map[string]interface{}); ok {
+			issuer["id"] = c.Issuer
+		} else {
+			vc["issuer"] = c.Issuer
+		}
+	}
+	if c.ID != "" {
+		vc["id"] = c.ID
+	}
+	if c.Subject != "" {
+		if subject, ok := vc["credentialSubject"].(map[string]interface{}); ok {
+			subject["id"] = c.Subject
+		}
+	}
+	validFrom, validUntil := "issuanceDate", "expirationDate"
+	if isV2(vc["@context"]) {
+		validFrom, validUntil = "validFrom", "validUntil"
+	}
+	if c.NotBefore != 0 {
+		vc[validFrom] = unixTime(c.NotBefore).Format(time.RFC3339)
+	}
+	if c.Expiry != 0 {
+		vc[validUntil] = unixTime(c.Expiry).Format(time.RFC3339)
+	}
+	ret := credential.NewCredential()
+	if err := ret.FromMap(vc); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// PresentationToClaims converts presentation to JWT claims, holder and id are moved to iss and jti claims.
+// credentialJWTs are JWT encoded credentials added to the presentation's verifiableCredential.
+func PresentationToClaims(pres *presentation.Presentation, audience, nonce string, credentialJWTs ...string) (*Claims, error) {
+	vp := pres.ToMap()
+	delete(vp, "proof")
+	ret := &Claims{VP: vp, Nonce: nonce}
+	if audience != "" {
+		ret.Audience = Audience{audience}
+	}
+	if holder, ok := vp["holder"].(string); ok {
+		ret.Issuer = holder
+		delete(vp, "holder")
+	}
+	if id, ok := vp["id"].(string); ok {
+		ret.ID = id
+		delete(vp, "id")
+	}
+	if len(credentialJWTs) > 0 {
+		creds, _ := vp["verifiableCredential"].([]interface{})
+		for _, jwt := range credentialJWTs {
+			creds = append(creds, jwt)
+		}
+		vp["verifiableCredential"] = creds
+	}
+	return ret, nil
+}
+
+// ToPresentation converts claims back to the presentation, JWT encoded credentials
+// are left out and returned by CredentialJWTs
+func (c *Claims) ToPresentation() (*presentation.Presentation, error) {
+	if c.VP == nil {
+		return nil, fmt.Errorf("jwt has no vp claim")
+	}
+	vp := copyMap(c.VP)
+	if c.Issuer != "" {
+		vp["holder"] = c.Issuer
+	}
+	if c.ID != "" {
+		vp["id"] = c.ID
+	}
+	if creds, ok := vp["verifiableCredential"].([]interface{}); ok {
+		embedded := make([]interface{}, 0, len(creds))
+		for _, v := range creds {
+			if _, ok := v.(string); !ok {
+				embedded = append(embedded, v)
+			}
+		}
+		vp["verifiableCredential"] = embedded
+	}
+	b, err := json.Marshal(vp)
+	if err != nil {
+		return nil, err
+	}
+	ret := &presentation.Presentation{}
+	if err := ret.FromBytes(b); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// CredentialJWTs returns JWT encoded credentials of the vp claim
+func (c *Claims) CredentialJWTs() []string {
+	var ret []string
+	creds, _ := c.VP["verifiableCredential"].([]interface{})
+	for _, v := range creds {
+		if jwt, ok := v.(string); ok {
+			ret = append(ret, jwt)
+		}
+	}
+	return ret
+}
+
+// Validate checks exp/nbf claims and the expected aud/nonce
+func (c *Claims) Validate(options ...VerifyOption) error {
+	opts := prepareOpts(options)
+	now := opts.clock()
+	if c.Expiry != 0 && now.After(unixTime(c.Expiry).Add(opts.leeway)) {
+		return fmt.Errorf("jwt expired at %s", unixTime(c.Expiry).Format(time.RFC3339))
+	}
+	if c.NotBefore != 0 && now.Before(unixTime(c.NotBefore).Add(-opts.leeway)) {
+		return fmt.Errorf("jwt not valid before %s", unixTime(c.NotBefore).Format(time.RFC3339))
+	}
+	if opts.audience != "" && !c.Audience.Contains(opts.audience) {
+		return fmt.Errorf("jwt audience not match %s", opts.audience)
+	}
+	if opts.nonce != "" && c.Nonce != opts.nonce {
+		return fmt.Errorf("jwt nonce not match")
+	}
+	return nil
+}
+
+// popTime removes the first present date property and returns it as unix time
+func popTime(m map[string]interface{}, keys ...string) (int64, error) {
+	for _, k := range keys {
+		v, ok := m[k].(string)
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s: %w", k, err)
+		}
+		delete(m, k)
+		return t.Unix(), nil
+	}
+	return 0, nil
+}
+
+func isV2(context interface{}) bool {
+	switch c := context.(type) {
+	case string:
+		return c == credentialsV2Context
+	case []interface{}:
+		return len(c) > 0 && c[0] == credentialsV2Context
+	}
+	return false
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	b, _ := json.Marshal(m)
+	ret := make(map[string]interface{})
+	json.Unmarshal(b, &ret)
+	return ret
+}
+
+// issuerDID returns the DID of a verification method id
+func issuerDID(keyID string) string {
+	return strings.SplitN(keyID, "#", 2)[0]
+}
@@ -0,0 +1,56 @@
+package jwtvc
+
+import (
+	"fmt"
+
+	"github.com/suutaku/go-vc/pkg/credential"
+	"github.com/suutaku/go-vc/pkg/jose"
+	"github.com/suutaku/go-vc/pkg/presentation"
+	"github.com/suutaku/go-vc/pkg/resolver"
+)
+
+// SignCredential encodes credential as a JWT signed by signer, kid is the issuer's verification method
+func SignCredential(cred *credential.Credential, signer jose.Signer, kid string) (string, error) {
+	claims, err := CredentialToClaims(cred)
+	if err != nil {
+		return "", err
+	}
+	return Sign(claims, signer, kid)
+}
+
+// VerifyCredential verifies a JWT credential and returns the decoded credential
+func VerifyCredential(token string, pubResolver resolver.PublicKeyResolver, opts ...VerifyOption) (*credential.Credential, error) {
+	t, err := Verify(token, pubResolver, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return t.Claims.ToCredential()
+}
+
+// SignPresentation encodes presentation as a JWT signed by signer for audience with nonce,
+// kid is the holder's verification method. credentialJWTs are JWT credentials to present.
+func SignPresentation(pres *presentation.Presentation, signer jose.Signer, kid, audience, nonce string, credentialJWTs ...string) (string, error) {
+	claims, err := PresentationToClaims(pres, audience, nonce, credentialJWTs...)
+	if err != nil {
+		return "", err
+	}
+	return Sign(claims, signer, kid)
+}
+
+// VerifyPresentation verifies a JWT presentation and the JWT credentials it contains, and returns the
+// decoded presentation. Use WithAudience and WithNonce to check the presentation is bound to the
+// verifier's request, embedded credentials are checked with the same clock and leeway.
+func VerifyPresentation(token string, pubResolver resolver.PublicKeyResolver, opts ...VerifyOption) (*presentation.Presentation, error) {
+	t, err := Verify(token, pubResolver, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// aud and nonce bind the presentation only
+	credOpts := append(opts[:len(opts):len(opts)], WithAudience(""), WithNonce(""))
+	for i, jwt := range t.Claims.CredentialJWTs() {
+		if _, err := VerifyCredential(jwt, pubResolver, credOpts...); err != nil {
+			return nil, fmt.Errorf("invalid credential %d of presentation: %w", i, err)
+		}
+	}
+	return t.Claims.ToPresentation()
+}
@@ -0,0 +1,118 @@
+// Package jwtvc encodes credentials and presentations as JWTs
+// (https://www.w3.org/TR/vc-data-model/#json-web-token) and verifies them.
+package jwtvc
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/suutaku/go-vc/pkg/jose"
+	"github.com/suutaku/go-vc/pkg/resolver"
+)
+
+const jwtType = "JWT"
+
+// Header is the JOSE header of a JWT
+type Header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// Token is a parsed JWT
+type Token struct {
+	Header       *Header
+	Claims       *Claims
+	signingInput string
+	signature    []byte
+}
+
+// Sign creates a compact JWS of claims, kid is the verification method of the signing key
+func Sign(claims *Claims, signer jose.Signer, kid string) (string, error) {
+	return jose.SignCompact(&Header{Alg: signer.Alg(), Typ: jwtType, Kid: kid}, claims, signer)
+}
+
+// Parse parses a compact JWS JWT without verifying it
+func Parse(token string) (*Token, error) {
+	ret := &Token{Header: &Header{}, Claims: &Claims{}}
+	signingInput, sig, err := jose.ParseCompact(token, ret.Header, ret.Claims)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt: %w", err)
+	}
+	ret.signingInput = signingInput
+	ret.signature = sig
+	return ret, nil
+}
+
+// Verify parses token and verifies its signature with the key resolved from kid
+// (or iss when kid is missing), then checks exp/nbf and the expected aud/nonce.
+func Verify(token string, pubResolver resolver.PublicKeyResolver, opts ...VerifyOption) (*Token, error) {
+	t, err := Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	jwk, err := ResolveKey(pubResolver, t.Header.Kid, t.Claims.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	if err := jose.Verify(t.Header.Alg, jwk, []byte(t.signingInput), t.signature); err != nil {
+		return nil, err
+	}
+	if err := t.Claims.Validate(opts...); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ResolveKey resolves the JWK of verification method kid (relative to iss), or iss when kid is missing.
+// The verification method must belong to iss.
+func ResolveKey(pubResolver resolver.PublicKeyResolver, kid, iss string) (*jose.JWK, error) {
+	keyID := verificationMethod(kid, iss)
+	if keyID == "" {
+		return nil, fmt.Errorf("jwt has neither kid nor iss")
+	}
+	if iss != "" && issuerDID(keyID) != iss {
+		return nil, fmt.Errorf("jwt kid %s not controlled by issuer %s", kid, iss)
+	}
+	pbk, err := pubResolver.Resolve(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve public key %s: %w", keyID, err)
+	}
+	if pbk == nil {
+		return nil, fmt.Errorf("cannot resolve public key %s", keyID)
+	}
+	return jwkOf(pbk)
+}
+
+// verificationMethod returns the absolute verification method id of kid
+func verificationMethod(kid, iss string) string {
+	if kid == "" {
+		return iss
+	}
+	if strings.HasPrefix(kid, "#") {
+		return iss + kid
+	}
+	return kid
+}
+
+// jwkOf returns JWK of the public key, raw Ed25519 and SEC1 encoded
+// P-256/P-384 keys are converted when no JWK was resolved
+func jwkOf(pbk *resolver.PublicKey) (*jose.JWK, error) {
+	if pbk.Jwk != nil {
+		return pbk.Jwk, nil
+	}
+	if len(pbk.Value) == ed25519.PublicKeySize {
+		return jose.NewJWK(ed25519.PublicKey(pbk.Value))
+	}
+	pub, err := jose.ParseSEC1PublicKey(pbk.Value)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported public key of type %s", pbk.Type)
+	}
+	return jose.NewJWK(pub)
+}
+
+func unixTime(t int64) time.Time {
+	return time.Unix(t, 0).UTC()
+}
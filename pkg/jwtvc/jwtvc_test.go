@@ -0,0 +1,123 @@
+package jwtvc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suutaku/go-vc/pkg/credential"
+	"github.com/suutaku/go-vc/pkg/jose"
+	"github.com/suutaku/go-vc/pkg/presentation"
+	"github.com/suutaku/go-vc/pkg/resolver"
+	"github.com/suutaku/go-vc/test"
+)
+
+const (
+	credentialDocPath = "vc-json-doc-jws.json"
+	issuerKid         = "did:example:489398593#key-1"
+	holderKid         = "did:example:b34ca6cd37bbf23#key-1"
+)
+
+func newTestSigner(t *testing.T) (jose.Signer, resolver.PublicKeyResolver) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer, err := jose.NewSigner(priv)
+	require.NoError(t, err)
+	jwk, err := jose.NewJWK(&priv.PublicKey)
+	require.NoError(t, err)
+	return signer, resolver.NewTestPublicKeyResolver(&resolver.PublicKey{Type: "JsonWebKey2020", Jwk: jwk}, nil)
+}
+
+func TestCredentialJWT(t *testing.T) {
+	credBytes, err := test.GetTestResource(credentialDocPath)
+	require.NoError(t, err)
+	cred := credential.NewCredential()
+	assert.NoError(t, cred.FromBytes(credBytes))
+	signer, pubResolver := newTestSigner(t)
+
+	token, err := SignCredential(cred, signer, issuerKid)
+	require.NoError(t, err)
+	parsed, err := Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, "did:example:489398593", parsed.Claims.Issuer)
+	assert.Equal(t, "did:example:b34ca6cd37bbf23", parsed.Claims.Subject)
+	assert.Equal(t, "https://issuer.oidp.uscis.gov/credentials/83627465", parsed.Claims.ID)
+	assert.Nil(t, parsed.Claims.VC["issuer"])
+
+	clock := WithClock(func() time.Time { return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) })
+	decoded, err := VerifyCredential(token, pubResolver, clock)
+	require.NoError(t, err)
+	assert.Equal(t, cred.ToMap(), decoded.ToMap())
+
+	// expired
+	_, err = VerifyCredential(token, pubResolver, WithClock(func() time.Time { return time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC) }))
+	assert.Error(t, err)
+	// not yet valid
+	_, err = VerifyCredential(token, pubResolver, WithClock(func() time.Time { return time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC) }))
+	assert.Error(t, err)
+	// tampered claims
+	parts := strings.Split(token, ".")
+	parsed.Claims.Subject = "did:example:attacker"
+	tamperedBytes, err := json.Marshal(parsed.Claims)
+	require.NoError(t, err)
+	_, err = VerifyCredential(parts[0]+"."+base64.RawURLEncoding.EncodeToString(tamperedBytes)+"."+parts[2], pubResolver, clock)
+	assert.Error(t, err)
+	other, err := SignCredential(cred, signer, "did:example:other#key-1")
+	require.NoError(t, err)
+	// kid not controlled by issuer
+	_, err = VerifyCredential(other, pubResolver, clock)
+	assert.Error(t, err)
+	// wrong key
+	_, otherResolver := newTestSigner(t)
+	_, err = VerifyCredential(token, otherResolver, clock)
+	assert.Error(t, err)
+}
+
+func TestPresentationJWT(t *testing.T) {
+	credBytes, err := test.GetTestResource(credentialDocPath)
+	require.NoError(t, err)
+	cred := credential.NewCredential()
+	assert.NoError(t, cred.FromBytes(credBytes))
+	signer, pubResolver := newTestSigner(t)
+	credJWT, err := SignCredential(cred, signer, issuerKid)
+	require.NoError(t, err)
+
+	pres := presentation.NewPresentation()
+	pres.Holder = "did:example:b34ca6cd37bbf23"
+	pres.Credential = append(pres.Credential, *cred)
+	token, err := SignPresentation(pres, signer, holderKid, "https://verifier.example", "n-0S6_WzA2Mj", credJWT)
+	require.NoError(t, err)
+
+	clock := WithClock(func() time.Time { return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) })
+	decoded, err := VerifyPresentation(token, pubResolver, clock, WithAudience("https://verifier.example"), WithNonce("n-0S6_WzA2Mj"))
+	require.NoError(t, err)
+	assert.Equal(t, pres.Holder, decoded.Holder)
+	assert.Len(t, decoded.Credential, 1)
+
+	parsed, err := Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, []string{credJWT}, parsed.Claims.CredentialJWTs())
+
+	_, err = VerifyPresentation(token, pubResolver, clock, WithAudience("https://other.example"))
+	assert.Error(t, err)
+	_, err = VerifyPresentation(token, pubResolver, clock, WithNonce("replayed"))
+	assert.Error(t, err)
+
+	// embedded credentials are verified
+	_, err = VerifyPresentation(token, pubResolver, WithClock(func() time.Time { return time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC) }))
+	assert.ErrorContains(t, err, "invalid credential 0 of presentation")
+	otherSigner, _ := newTestSigner(t)
+	forged, err := SignCredential(cred, otherSigner, issuerKid)
+	require.NoError(t, err)
+	token, err = SignPresentation(pres, signer, holderKid, "", "", forged)
+	require.NoError(t, err)
+	_, err = VerifyPresentation(token, pubResolver, clock)
+	assert.ErrorContains(t, err, "invalid credential 0 of presentation")
+}
@@ -0,0 +1,52 @@
+package jwtvc
+
+import "time"
+
+// verifyOpts holds options for JWT verification.
+type verifyOpts struct {
+	clock    func() time.Time
+	leeway   time.Duration
+	audience string
+	nonce    string
+}
+
+// VerifyOption are the options for JWT verification.
+type VerifyOption func(opts *verifyOpts)
+
+// WithClock option sets the clock used to check exp and nbf claims.
+func WithClock(clock func() time.Time) VerifyOption {
+	return func(opts *verifyOpts) {
+		opts.clock = clock
+	}
+}
+
+// WithLeeway option tolerates clock skew when checking exp and nbf claims.
+func WithLeeway(leeway time.Duration) VerifyOption {
+	return func(opts *verifyOpts) {
+		opts.leeway = leeway
+	}
+}
+
+// WithAudience option requires aud claim to contain audience.
+func WithAudience(audience string) VerifyOption {
+	return func(opts *verifyOpts) {
+		opts.audience = audience
+	}
+}
+
+// WithNonce option requires nonce claim to equal nonce.
+func WithNonce(nonce string) VerifyOption {
+	return func(opts *verifyOpts) {
+		opts.nonce = nonce
+	}
+}
+
+func prepareOpts(opts []VerifyOption) *verifyOpts {
+	ret := &verifyOpts{
+		clock: time.Now,
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
@@ -0,0 +1,111 @@
+package ldcontext
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/sha256" // register SHA-256 for crypto.Hash
+	_ "crypto/sha512" // register SHA-384 and SHA-512 for crypto.Hash
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/suutaku/go-vc/internal/tools"
+)
+
+// sriAlgs maps Subresource Integrity hash names to hashes
+var sriAlgs = map[string]crypto.Hash{
+	"sha256": crypto.SHA256,
+	"sha384": crypto.SHA384,
+	"sha512": crypto.SHA512,
+}
+
+// multihashAlgs maps multihash codes to hashes
+var multihashAlgs = map[byte]crypto.Hash{
+	0x12: crypto.SHA256,
+	0x20: crypto.SHA384,
+	0x13: crypto.SHA512,
+}
+
+// digest is an expected digest of a context document
+type digest struct {
+	hash crypto.Hash
+	sum  []byte
+}
+
+// parseDigest parses a hex encoded SHA-256 digest, a Subresource Integrity value (sha256-..., sha384-...
+// or sha512-...) or a multibase encoded multihash (digestMultibase)
+func parseDigest(value string) (*digest, error) {
+	if alg, b64, ok := strings.Cut(value, "-"); ok && sriAlgs[alg] != 0 {
+		hash := sriAlgs[alg]
+		sum, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil || len(sum) != hash.Size() {
+			return nil, fmt.Errorf("invalid digest %s", value)
+		}
+		return &digest{hash, sum}, nil
+	}
+	if sum, err := hex.DecodeString(value); err == nil && len(sum) == crypto.SHA256.Size() {
+		return &digest{crypto.SHA256, sum}, nil
+	}
+	mh, err := tools.DecodeMultibase(value)
+	if err != nil || len(mh) < 2 {
+		return nil, fmt.Errorf("invalid digest %s", value)
+	}
+	hash, ok := multihashAlgs[mh[0]]
+	if !ok || int(mh[1]) != hash.Size() || len(mh) != 2+hash.Size() {
+		return nil, fmt.Errorf("unsupported multihash digest %s", value)
+	}
+	return &digest{hash, mh[2:]}, nil
+}
+
+func (d *digest) match(raw []byte) bool {
+	h := d.hash.New()
+	h.Write(raw)
+	return bytes.Equal(h.Sum(nil), d.sum)
+}
+
+// SRIDigest returns the Subresource Integrity SHA-256 digest of raw, as used by digestSRI
+func SRIDigest(raw []byte) string {
+	h := crypto.SHA256.New()
+	h.Write(raw)
+	return "sha256-" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// RelatedResourceDigests returns digests of the relatedResource property of a credential,
+// keyed by resource id
+func RelatedResourceDigests(doc map[string]interface{}) (map[string]string, error) {
+	var resources []interface{}
+	switch r := doc["relatedResource"].(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		resources = r
+	case map[string]interface{}:
+		resources = []interface{}{r}
+	default:
+		return nil, fmt.Errorf("invalid relatedResource")
+	}
+	ret := make(map[string]string, len(resources))
+	for _, v := range resources {
+		r, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid relatedResource")
+		}
+		id, _ := r["id"].(string)
+		if id == "" {
+			return nil, fmt.Errorf("relatedResource without id")
+		}
+		value, _ := r["digestSRI"].(string)
+		if value == "" {
+			value, _ = r["digestMultibase"].(string)
+		}
+		if value == "" {
+			return nil, fmt.Errorf("relatedResource %s has neither digestSRI nor digestMultibase", id)
+		}
+		if _, ok := ret[id]; ok {
+			return nil, fmt.Errorf("duplicated relatedResource %s", id)
+		}
+		ret[id] = value
+	}
+	return ret, nil
+}
@@ -0,0 +1,440 @@
+package ldcontext
+
+import (
+	"bytes"
+	"container/list"
+	"embed"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/piprate/json-gold/ld"
+)
+
+//go:embed contexts/*.jsonld
+var embedFS embed.FS
+
+const (
+	// maxContextBytes limits the size of a context document fetched from network
+	maxContextBytes = 1 << 20
+	// defaultFetchTimeout bounds network fetches of the default HTTP client
+	defaultFetchTimeout = 30 * time.Second
+	// defaultFetchCacheSize is the default number of cached documents fetched from network
+	defaultFetchCacheSize = 64
+)
+
+// embedded maps well-known context URLs to their embedded copies. The ssis.cotnetwork.com contexts of
+// package common are not embedded, register them with WithContext to use them offline.
+var embedded = map[string]string{
+	"https://www.w3.org/2018/credentials/v1":            "credentials_v1.jsonld",
+	"https://www.w3.org/ns/credentials/v2":              "credentials_v2.jsonld",
+	"https://w3id.org/security/v1":                      "security_v1.jsonld",
+	"https://w3id.org/security/v2":                      "security_v2.jsonld",
+	"https://w3id.org/security/v3-unstable":             "security_v3_unstable.jsonld",
+	"https://w3id.org/security/data-integrity/v1":       "data_integrity_v1.jsonld",
+	"https://w3id.org/security/data-integrity/v2":       "data_integrity_v2.jsonld",
+	"https://w3id.org/security/bbs/v1":                  "bbs_v1.jsonld",
+	"https://w3id.org/security/suites/bls12381-2020/v1": "bbs_v1.jsonld",
+	"https://w3id.org/vc/status-list/2021/v1":           "status_list_2021_v1.jsonld",
+	"https://w3id.org/security/suites/ed25519-2018/v1":  "ed25519_2018_v1.jsonld",
+	"https://w3id.org/security/suites/ed25519-2020/v1":  "ed25519_2020_v1.jsonld",
+	"https://w3id.org/security/suites/jws-2020/v1":      "jws2020_v1.jsonld",
+	"https://w3id.org/security/jws/v1":                  "jws2020_v1.jsonld",
+	"https://www.w3.org/ns/did/v1":                      "did_v1.jsonld",
+}
+
+// EmbeddedContexts returns URLs of the embedded contexts
+func EmbeddedContexts() []string {
+	ret := make([]string, 0, len(embedded))
+	for u := range embedded {
+		ret = append(ret, u)
+	}
+	return ret
+}
+
+// DocumentLoader is a JSON-LD document loader which serves the embedded well-known contexts and
+// contexts added with AddContext, other documents are fetched from network unless disabled.
+// The most recently used fetched documents are cached.
+// Contexts pinned to a digest fail to load when their content does not match.
+// It is safe for concurrent use.
+type DocumentLoader struct {
+	mu         sync.RWMutex
+	contexts   map[string]*contextDocument
+	fetched    map[string]*list.Element
+	lru        *list.List
+	pins       map[string]*digest
+	offline    bool
+	httpClient *http.Client
+	cacheSize  int
+}
+
+// contextDocument is a loaded context with the bytes it was parsed from
+type contextDocument struct {
+	url string
+	doc *ld.RemoteDocument
+	raw []byte
+}
+
+// NewDocumentLoader creates a DocumentLoader with the embedded contexts
+func NewDocumentLoader(opts ...LoaderOpts) (*DocumentLoader, error) {
+	options := prepareLoaderOpts(opts)
+	ret := &DocumentLoader{
+		contexts:   make(map[string]*contextDocument, len(embedded)+len(options.contexts)),
+		fetched:    make(map[string]*list.Element),
+		lru:        list.New(),
+		pins:       make(map[string]*digest, len(options.pins)),
+		offline:    options.offline,
+		httpClient: options.httpClient,
+		cacheSize:  options.fetchCacheSize,
+	}
+	if ret.httpClient == nil {
+		ret.httpClient = &http.Client{Timeout: defaultFetchTimeout}
+	}
+	for u, name := range embedded {
+		b, err := embedFS.ReadFile("contexts/" + name)
+		if err != nil {
+			return nil, err
+		}
+		if err := ret.AddContext(u, b); err != nil {
+			return nil, err
+		}
+	}
+	for u, b := range options.contexts {
+		if err := ret.AddContext(u, b); err != nil {
+			return nil, err
+		}
+	}
+	for u, path := range options.contextFiles {
+		if err := ret.AddContextFile(u, path); err != nil {
+			return nil, err
+		}
+	}
+	for u, value := range options.pins {
+		if err := ret.PinContext(u, value); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+var (
+	defaultLoader     *DocumentLoader
+	defaultLoaderOnce sync.Once
+)
+
+// DefaultDocumentLoader returns the shared DocumentLoader with the embedded contexts, documents
+// fetched from network are cached and the fetch timeout is 30 seconds
+func DefaultDocumentLoader() *DocumentLoader {
+	defaultLoaderOnce.Do(func() {
+		var err error
+		defaultLoader, err = NewDocumentLoader()
+		if err != nil {
+			panic(fmt.Sprintf("load embedded JSON-LD contexts: %v", err))
+		}
+	})
+	return defaultLoader
+}
+
+// AddContext registers the JSON-LD context document of url
+func (l *DocumentLoader) AddContext(url string, b []byte) error {
+	ctx, err := parseContext(url, b)
+	if err != nil {
+		return err
+	}
+	if m, ok := ctx.doc.Document.(map[string]interface{}); !ok || m["@context"] == nil {
+		return fmt.Errorf("invalid JSON-LD context %s: missing @context", url)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.contexts[url] = ctx
+	return nil
+}
+
+// AddContextFile registers the JSON-LD context document of url from a file
+func (l *DocumentLoader) AddContextFile(url, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return l.AddContext(url, b)
+}
+
+// PinContext pins the context document of url to a digest, which is a hex encoded SHA-256 digest,
+// a Subresource Integrity value (digestSRI) or a multibase encoded multihash (digestMultibase)
+func (l *DocumentLoader) PinContext(url, value string) error {
+	d, err := parseDigest(value)
+	if err != nil {
+		return fmt.Errorf("pin %s: %w", url, err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pins[url] = d
+	return nil
+}
+
+// ContextDigest loads the context document of url and returns its SHA-256 Subresource Integrity digest
+func (l *DocumentLoader) ContextDigest(url string) (string, error) {
+	ctx, err := l.load(url)
+	if err != nil {
+		return "", err
+	}
+	return SRIDigest(ctx.raw), nil
+}
+
+// WithRelatedResources returns a loader which additionally requires contexts listed in the relatedResource
+// property of doc to match their digestSRI or digestMultibase
+func (l *DocumentLoader) WithRelatedResources(doc map[string]interface{}) (ld.DocumentLoader, error) {
+	values, err := RelatedResourceDigests(doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return l, nil
+	}
+	pins := make(map[string]*digest, len(values))
+	for u, value := range values {
+		if pins[u], err = parseDigest(value); err != nil {
+			return nil, fmt.Errorf("relatedResource %s: %w", u, err)
+		}
+	}
+	return &pinnedLoader{l, pins}, nil
+}
+
+// RelatedResourceLoader returns a loader which requires contexts listed in the relatedResource property of
+// doc to match their digests. Digests are checked by a DocumentLoader, other loaders do not expose the
+// bytes of documents so loading a pinned context with them fails.
+func RelatedResourceLoader(loader ld.DocumentLoader, doc map[string]interface{}) (ld.DocumentLoader, error) {
+	if l, ok := loader.(*DocumentLoader); ok {
+		return l.WithRelatedResources(doc)
+	}
+	values, err := RelatedResourceDigests(doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return loader, nil
+	}
+	return &uncheckedLoader{loader, values}, nil
+}
+
+// LoadDocument returns the registered document of u, or fetches it from network when allowed
+func (l *DocumentLoader) LoadDocument(u string) (*ld.RemoteDocument, error) {
+	return l.loadChecked(u, nil)
+}
+
+// loadChecked loads the document of u and checks its pin and the extra digest
+func (l *DocumentLoader) loadChecked(u string, extra *digest) (*ld.RemoteDocument, error) {
+	ctx, err := l.load(u)
+	if err != nil {
+		return nil, err
+	}
+	l.mu.RLock()
+	pin := l.pins[u]
+	l.mu.RUnlock()
+	for _, d := range []*digest{pin, extra} {
+		if d != nil && !d.match(ctx.raw) {
+			return nil, ld.NewJsonLdError(ld.LoadingDocumentFailed,
+				fmt.Sprintf("context %s does not match its pinned digest", u))
+		}
+	}
+	return ctx.doc, nil
+}
+
+func (l *DocumentLoader) load(u string) (*contextDocument, error) {
+	l.mu.RLock()
+	ctx, ok := l.contexts[u]
+	if !ok {
+		ctx, ok = l.contexts[strings.TrimSuffix(u, "/")]
+	}
+	l.mu.RUnlock()
+	if ok {
+		return ctx, nil
+	}
+	if ctx, ok := l.cached(u); ok {
+		return ctx, nil
+	}
+	if l.offline {
+		return nil, ld.NewJsonLdError(ld.LoadingDocumentFailed,
+			fmt.Sprintf("network fetch of %s is disabled", u))
+	}
+	ctx, err := l.fetch(u)
+	if err != nil {
+		return nil, err
+	}
+	l.store(u, ctx)
+	return ctx, nil
+}
+
+// cached returns the fetched document of u if it is still cached
+func (l *DocumentLoader) cached(u string) (*contextDocument, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	elem, ok := l.fetched[u]
+	if !ok {
+		return nil, false
+	}
+	l.lru.MoveToFront(elem)
+	return elem.Value.(*contextDocument), true
+}
+
+// store caches the fetched document of u, evicting the least recently used documents
+func (l *DocumentLoader) store(u string, ctx *contextDocument) {
+	if l.cacheSize <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if elem, ok := l.fetched[u]; ok {
+		elem.Value = ctx
+		l.lru.MoveToFront(elem)
+		return
+	}
+	ctx.url = u
+	l.fetched[u] = l.lru.PushFront(ctx)
+	for l.lru.Len() > l.cacheSize {
+		oldest := l.lru.Back()
+		l.lru.Remove(oldest)
+		delete(l.fetched, oldest.Value.(*contextDocument).url)
+	}
+}
+
+func (l *DocumentLoader) fetch(u string) (*contextDocument, error) {
+	req, err := http.NewRequest(http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, ld.NewJsonLdError(ld.LoadingDocumentFailed, err)
+	}
+	req.Header.Set("Accept", "application/ld+json, application/json;q=0.9, */*;q=0.1")
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, ld.NewJsonLdError(ld.LoadingDocumentFailed, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, ld.NewJsonLdError(ld.LoadingDocumentFailed,
+			fmt.Sprintf("fetch %s: bad response status code %d", u, resp.StatusCode))
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxContextBytes+1))
+	if err != nil {
+		return nil, ld.NewJsonLdError(ld.LoadingDocumentFailed, err)
+	}
+	if len(b) > maxContextBytes {
+		return nil, ld.NewJsonLdError(ld.LoadingDocumentFailed,
+			fmt.Sprintf("fetch %s: document exceeds %d bytes", u, maxContextBytes))
+	}
+	ctx, err := parseContext(resp.Request.URL.String(), b)
+	if err != nil {
+		return nil, ld.NewJsonLdError(ld.LoadingDocumentFailed, err)
+	}
+	return ctx, nil
+}
+
+func parseContext(url string, b []byte) (*contextDocument, error) {
+	doc, err := ld.DocumentFromReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON-LD context %s: %w", url, err)
+	}
+	return &contextDocument{
+		doc: &ld.RemoteDocument{DocumentURL: url, Document: doc},
+		raw: b,
+	}, nil
+}
+
+// pinnedLoader checks extra digests on top of the DocumentLoader pins
+type pinnedLoader struct {
+	loader *DocumentLoader
+	extra  map[string]*digest
+}
+
+func (pl *pinnedLoader) LoadDocument(u string) (*ld.RemoteDocument, error) {
+	return pl.loader.loadChecked(u, pl.extra[u])
+}
+
+// uncheckedLoader refuses to load pinned documents with a loader which can't check their digest
+type uncheckedLoader struct {
+	loader ld.DocumentLoader
+	pinned map[string]string
+}
+
+func (ul *uncheckedLoader) LoadDocument(u string) (*ld.RemoteDocument, error) {
+	if _, ok := ul.pinned[u]; ok {
+		return nil, ld.NewJsonLdError(ld.LoadingDocumentFailed,
+			fmt.Sprintf("digest of context %s cannot be checked by a %T document loader", u, ul.loader))
+	}
+	return ul.loader.LoadDocument(u)
+}
+
+var _ ld.DocumentLoader = (*DocumentLoader)(nil)
+
+// loaderOpts holds options for DocumentLoader.
+type loaderOpts struct {
+	offline        bool
+	pins           map[string]string
+	httpClient     *http.Client
+	fetchCacheSize int
+	contexts       map[string][]byte
+	contextFiles   map[string]string
+}
+
+// LoaderOpts are the options for DocumentLoader.
+type LoaderOpts func(opts *loaderOpts)
+
+// WithoutNetwork option refuses to fetch documents which are not registered.
+func WithoutNetwork() LoaderOpts {
+	return func(opts *loaderOpts) {
+		opts.offline = true
+	}
+}
+
+// WithHTTPClient option sets the HTTP client used to fetch documents which are not registered,
+// a client with a 30 seconds timeout by default.
+func WithHTTPClient(client *http.Client) LoaderOpts {
+	return func(opts *loaderOpts) {
+		opts.httpClient = client
+	}
+}
+
+// WithFetchCacheSize option sets the number of cached documents fetched from network, 64 by default,
+// 0 disables the cache.
+func WithFetchCacheSize(size int) LoaderOpts {
+	return func(opts *loaderOpts) {
+		opts.fetchCacheSize = size
+	}
+}
+
+// WithContext option registers an extra context document of url.
+func WithContext(url string, b []byte) LoaderOpts {
+	return func(opts *loaderOpts) {
+		opts.contexts[url] = b
+	}
+}
+
+// WithContextFile option registers an extra context document of url from a file.
+func WithContextFile(url, path string) LoaderOpts {
+	return func(opts *loaderOpts) {
+		opts.contextFiles[url] = path
+	}
+}
+
+// WithPinnedContext option pins the context document of url to a digest, see DocumentLoader.PinContext.
+func WithPinnedContext(url, digest string) LoaderOpts {
+	return func(opts *loaderOpts) {
+		opts.pins[url] = digest
+	}
+}
+
+func prepareLoaderOpts(opts []LoaderOpts) *loaderOpts {
+	ret := &loaderOpts{
+		contexts:       make(map[string][]byte),
+		contextFiles:   make(map[string]string),
+		pins:           make(map[string]string),
+		fetchCacheSize: defaultFetchCacheSize,
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
@@ -0,0 +1,185 @@
+package ldcontext
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/piprate/json-gold/ld"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suutaku/go-vc/test"
+)
+
+const citizenshipContext = "https://w3id.org/citizenship/v1"
+
+func normalize(loader ld.DocumentLoader, doc map[string]interface{}) (string, error) {
+	opts := ld.NewJsonLdOptions("")
+	opts.ProcessingMode = ld.JsonLd_1_1
+	opts.Algorithm = "URDNA2015"
+	opts.Format = "application/n-quads"
+	opts.DocumentLoader = loader
+	view, err := ld.NewJsonLdProcessor().Normalize(doc, opts)
+	if err != nil {
+		return "", err
+	}
+	return view.(string), nil
+}
+
+func TestEmbeddedContexts(t *testing.T) {
+	loader, err := NewDocumentLoader(WithoutNetwork())
+	require.NoError(t, err)
+	for _, u := range EmbeddedContexts() {
+		_, err := normalize(loader, map[string]interface{}{
+			"@context": u,
+			"@id":      "urn:uuid:9b2ef2f2-7e07-4c2b-9a6f-1c2c2a1c8f0e",
+		})
+		assert.NoError(t, err, u)
+	}
+
+	_, err = normalize(loader, map[string]interface{}{
+		"@context":          []interface{}{"https://www.w3.org/ns/credentials/v2"},
+		"type":              []interface{}{"VerifiableCredential"},
+		"issuer":            "did:example:489398593",
+		"validFrom":         "2019-12-03T12:19:52Z",
+		"credentialSubject": map[string]interface{}{"id": "did:example:b34ca6cd37bbf23"},
+	})
+	assert.NoError(t, err)
+}
+
+func TestOfflineLoader(t *testing.T) {
+	credBytes, err := test.GetTestResource("vc-json-doc-ed25519.json")
+	require.NoError(t, err)
+	ctxBytes, err := test.GetTestResource("citizenship-v1.jsonld")
+	require.NoError(t, err)
+	doc := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal(credBytes, &doc))
+
+	// citizenship context is neither embedded nor registered
+	loader, err := NewDocumentLoader(WithoutNetwork())
+	require.NoError(t, err)
+	_, err = normalize(loader, doc)
+	assert.Error(t, err)
+
+	loader, err = NewDocumentLoader(WithoutNetwork(), WithContext(citizenshipContext, ctxBytes))
+	require.NoError(t, err)
+	expected, err := normalize(loader, doc)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "citizenship.jsonld")
+	assert.NoError(t, os.WriteFile(path, ctxBytes, 0600))
+	loader, err = NewDocumentLoader(WithoutNetwork(), WithContextFile(citizenshipContext, path))
+	require.NoError(t, err)
+	actual, err := normalize(loader, doc)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	assert.Error(t, loader.AddContext("https://example.com/invalid", []byte(`{"name": "no context"}`)))
+}
+
+func TestPinnedContext(t *testing.T) {
+	credentialsV1, err := embedFS.ReadFile("contexts/credentials_v1.jsonld")
+	require.NoError(t, err)
+	sum := sha256.Sum256(credentialsV1)
+	doc := map[string]interface{}{
+		"@context": "https://www.w3.org/2018/credentials/v1",
+		"type":     "VerifiableCredential",
+		"issuer":   "did:example:489398593",
+	}
+
+	for _, pin := range []string{hex.EncodeToString(sum[:]), SRIDigest(credentialsV1), "u" + base64.RawURLEncoding.EncodeToString(append([]byte{0x12, 0x20}, sum[:]...))} {
+		loader, err := NewDocumentLoader(WithoutNetwork(), WithPinnedContext("https://www.w3.org/2018/credentials/v1", pin))
+		require.NoError(t, err, pin)
+		_, err = normalize(loader, doc)
+		assert.NoError(t, err, pin)
+	}
+
+	wrong := sha256.Sum256([]byte("changed"))
+	loader, err := NewDocumentLoader(WithoutNetwork(), WithPinnedContext("https://www.w3.org/2018/credentials/v1", hex.EncodeToString(wrong[:])))
+	require.NoError(t, err)
+	_, err = normalize(loader, doc)
+	assert.Error(t, err)
+
+	_, err = NewDocumentLoader(WithPinnedContext("https://www.w3.org/2018/credentials/v1", "md5-invalid"))
+	assert.Error(t, err)
+}
+
+func TestRelatedResourceDigests(t *testing.T) {
+	loader, err := NewDocumentLoader(WithoutNetwork())
+	require.NoError(t, err)
+	digest, err := loader.ContextDigest("https://www.w3.org/ns/credentials/v2")
+	require.NoError(t, err)
+	doc := map[string]interface{}{
+		"@context": "https://www.w3.org/ns/credentials/v2",
+		"type":     "VerifiableCredential",
+		"issuer":   "did:example:489398593",
+		"relatedResource": []interface{}{
+			map[string]interface{}{"id": "https://www.w3.org/ns/credentials/v2", "digestSRI": digest},
+		},
+	}
+	pinned, err := loader.WithRelatedResources(doc)
+	require.NoError(t, err)
+	_, err = normalize(pinned, doc)
+	assert.NoError(t, err)
+
+	doc["relatedResource"] = []interface{}{
+		map[string]interface{}{"id": "https://www.w3.org/ns/credentials/v2", "digestSRI": SRIDigest([]byte("changed"))},
+	}
+	pinned, err = loader.WithRelatedResources(doc)
+	require.NoError(t, err)
+	_, err = normalize(pinned, doc)
+	assert.Error(t, err)
+
+	doc["relatedResource"] = []interface{}{map[string]interface{}{"id": "https://example.com/image.png"}}
+	_, err = loader.WithRelatedResources(doc)
+	assert.Error(t, err)
+}
+
+func TestFetchedContexts(t *testing.T) {
+	ctxBytes, err := test.GetTestResource("citizenship-v1.jsonld")
+	require.NoError(t, err)
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/large" {
+			w.Write(bytes.Repeat([]byte(" "), maxContextBytes+1))
+			return
+		}
+		w.Write(ctxBytes)
+	}))
+	defer ts.Close()
+
+	loader, err := NewDocumentLoader(WithHTTPClient(ts.Client()), WithFetchCacheSize(2))
+	require.NoError(t, err)
+	load := func(path string) {
+		_, err := loader.LoadDocument(ts.URL + path)
+		assert.NoError(t, err, path)
+	}
+	// the least recently used fetched document is evicted
+	load("/a")
+	load("/b")
+	load("/a")
+	assert.Equal(t, 2, requests)
+	load("/c")
+	load("/a")
+	assert.Equal(t, 3, requests)
+	load("/b")
+	assert.Equal(t, 4, requests)
+	assert.Len(t, loader.fetched, 2)
+
+	_, err = loader.LoadDocument(ts.URL + "/large")
+	assert.ErrorContains(t, err, "exceeds")
+
+	loader, err = NewDocumentLoader(WithHTTPClient(ts.Client()), WithFetchCacheSize(0))
+	require.NoError(t, err)
+	load("/a")
+	load("/a")
+	assert.Equal(t, 7, requests)
+}
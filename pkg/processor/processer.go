@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/piprate/json-gold/ld"
 	"github.com/sirupsen/logrus"
+	"github.com/suutaku/go-vc/pkg/ldcontext"
 )
 
 const (
@@ -102,11 +103,12 @@ func (p *Processor) GetCanonicalDocument(doc map[string]interface{}, opts ...Pro
 	ldOptions.Algorithm = p.algorithm
 	ldOptions.Format = format
 	ldOptions.ProduceGeneralizedRdf = true
-	if procOptions.documentLoader != nil {
-		ldOptions.DocumentLoader = procOptions.documentLoader
-	} else {
-		ldOptions.DocumentLoader = ld.NewCachingDocumentLoader(ld.NewDefaultDocumentLoader(nil))
+
+	loader, err := procOptions.loaderFor(doc)
+	if err != nil {
+		return nil, err
 	}
+	ldOptions.DocumentLoader = loader
 
 	if len(procOptions.externalContexts) > 0 {
 		doc["@context"] = AppendExternalContexts(doc["@context"], procOptions.externalContexts...)
@@ -159,11 +161,7 @@ func (p *Processor) Compact(input, context map[string]interface{},
 	ldOptions.ProcessingMode = ld.JsonLd_1_1
 	ldOptions.Format = format
 	ldOptions.ProduceGeneralizedRdf = true
-	if procOptions.documentLoader != nil {
-		ldOptions.DocumentLoader = procOptions.documentLoader
-	} else {
-		ldOptions.DocumentLoader = ld.NewCachingDocumentLoader(ld.NewDefaultDocumentLoader(nil))
-	}
+	ldOptions.DocumentLoader = procOptions.loader()
 
 	if context == nil {
 		inputContext := input["@context"]
@@ -189,11 +187,7 @@ func (p *Processor) Frame(inputDoc map[string]interface{}, frameDoc map[string]i
 	ldOptions.Format = format
 	ldOptions.ProduceGeneralizedRdf = true
 	ldOptions.OmitGraph = true
-	if procOptions.documentLoader != nil {
-		ldOptions.DocumentLoader = procOptions.documentLoader
-	} else {
-		ldOptions.DocumentLoader = ld.NewCachingDocumentLoader(ld.NewDefaultDocumentLoader(nil))
-	}
+	ldOptions.DocumentLoader = procOptions.loader()
 
 	proc := ld.NewJsonLdProcessor()
 
@@ -428,11 +422,7 @@ func fromRDF(docStatements []string, context interface{},
 	ldOptions.ProcessingMode = ld.JsonLd_1_1
 	ldOptions.Format = format
 	ldOptions.ProduceGeneralizedRdf = true
-	if procOptions.documentLoader != nil {
-		ldOptions.DocumentLoader = procOptions.documentLoader
-	} else {
-		ldOptions.DocumentLoader = ld.NewCachingDocumentLoader(ld.NewDefaultDocumentLoader(nil))
-	}
+	ldOptions.DocumentLoader = procOptions.loader()
 
 	doc := strings.Join(docStatements, "\n")
 	proc := ld.NewJsonLdProcessor()
@@ -450,6 +440,21 @@ func fromRDF(docStatements []string, context interface{},
 	return transformedDocMap, nil
 }
 
+// loader returns the custom document loader, or the shared loader with embedded contexts.
+func (opts *processorOpts) loader() ld.DocumentLoader {
+	if opts.documentLoader != nil {
+		return opts.documentLoader
+	}
+
+	return ldcontext.DefaultDocumentLoader()
+}
+
+// loaderFor returns the document loader for doc. Contexts listed in the relatedResource property of doc
+// must match their digests, see ldcontext.RelatedResourceLoader.
+func (opts *processorOpts) loaderFor(doc map[string]interface{}) (ld.DocumentLoader, error) {
+	return ldcontext.RelatedResourceLoader(opts.loader(), doc)
+}
+
 // prepareOpts prepare processorOpts from given CanonicalizationOpts arguments.
 func prepareOpts(opts []ProcessorOpts) *processorOpts {
 	procOpts := &processorOpts{}
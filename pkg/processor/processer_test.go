@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/piprate/json-gold/ld"
 	"github.com/stretchr/testify/require"
+	"github.com/suutaku/go-vc/pkg/ldcontext"
 	"github.com/suutaku/go-vc/test"
 )
 
@@ -26,3 +28,60 @@ func TestProcessor(t *testing.T) {
 	require.NoError(t, err)
 	t.Logf("%s\n", resStr)
 }
+
+func TestRelatedResourceMismatch(t *testing.T) {
+	loader, err := ldcontext.NewDocumentLoader(ldcontext.WithoutNetwork())
+	require.NoError(t, err)
+	doc := map[string]interface{}{
+		"@context": "https://www.w3.org/ns/credentials/v2",
+		"type":     "VerifiableCredential",
+		"issuer":   "did:example:489398593",
+		"relatedResource": []interface{}{
+			map[string]interface{}{
+				"id":        "https://www.w3.org/ns/credentials/v2",
+				"digestSRI": ldcontext.SRIDigest([]byte("changed")),
+			},
+		},
+	}
+	_, err = Default().GetCanonicalDocument(doc, WithDocumentLoader(loader))
+	require.Error(t, err)
+
+	digest, err := loader.ContextDigest("https://www.w3.org/ns/credentials/v2")
+	require.NoError(t, err)
+	doc["relatedResource"].([]interface{})[0].(map[string]interface{})["digestSRI"] = digest
+	_, err = Default().GetCanonicalDocument(doc, WithDocumentLoader(loader))
+	require.NoError(t, err)
+}
+
+func TestRelatedResourceCustomLoader(t *testing.T) {
+	loader, err := ldcontext.NewDocumentLoader(ldcontext.WithoutNetwork())
+	require.NoError(t, err)
+	digest, err := loader.ContextDigest("https://www.w3.org/ns/credentials/v2")
+	require.NoError(t, err)
+	doc := map[string]interface{}{
+		"@context": "https://www.w3.org/ns/credentials/v2",
+		"type":     "VerifiableCredential",
+		"issuer":   "did:example:489398593",
+		"relatedResource": []interface{}{
+			map[string]interface{}{"id": "https://example.com/logo.png", "digestSRI": digest},
+		},
+	}
+	// other loaders can't check digests, only pinned contexts fail to load
+	custom := customLoader{loader}
+	_, err = Default().GetCanonicalDocument(doc, WithDocumentLoader(custom))
+	require.NoError(t, err)
+	doc["relatedResource"] = []interface{}{
+		map[string]interface{}{"id": "https://www.w3.org/ns/credentials/v2", "digestSRI": digest},
+	}
+	_, err = Default().GetCanonicalDocument(doc, WithDocumentLoader(custom))
+	require.Error(t, err)
+}
+
+// customLoader hides the ldcontext.DocumentLoader it delegates to
+type customLoader struct {
+	loader ld.DocumentLoader
+}
+
+func (cl customLoader) LoadDocument(u string) (*ld.RemoteDocument, error) {
+	return cl.loader.LoadDocument(u)
+}
@@ -0,0 +1,159 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/piprate/json-gold/ld"
+)
+
+const (
+	skolemPrefix      = "urn:bnid:"
+	skolemLabelPrefix = "_:sk"
+)
+
+var skolemIRI = regexp.MustCompile(`<` + skolemPrefix + `(_:[^>]+)>`)
+
+// SkolemizeCompact replaces every blank node of doc with a skolem IRI (urn:bnid:_:...),
+// so that nodes keep a stable identity when parts of the document are selected later.
+// It returns the skolemized document expanded and compacted with the context of doc.
+func (p *Processor) SkolemizeCompact(doc map[string]interface{},
+	opts ...ProcessorOpts) ([]interface{}, map[string]interface{}, error) {
+	procOptions := prepareOpts(opts)
+	ldOptions := p.ldOptions(procOptions)
+	loader, err := procOptions.loaderFor(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	ldOptions.DocumentLoader = loader
+	proc := ld.NewJsonLdProcessor()
+
+	expanded, err := proc.Expand(doc, ldOptions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to expand JSON-LD document: %w", err)
+	}
+	counter := 0
+	skolemizeArray(expanded, &counter)
+
+	compacted, err := proc.Compact(expanded, map[string]interface{}{"@context": doc["@context"]}, ldOptions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compact JSON-LD document: %w", err)
+	}
+	return expanded, compacted, nil
+}
+
+// ToDeskolemizedNQuads returns N-Quads of doc (expanded or compacted) with skolem IRIs
+// turned back into blank nodes.
+func (p *Processor) ToDeskolemizedNQuads(doc interface{}, opts ...ProcessorOpts) ([]string, error) {
+	ldOptions := p.ldOptions(prepareOpts(opts))
+	ldOptions.Format = format
+
+	view, err := ld.NewJsonLdProcessor().ToRDF(doc, ldOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert JSON-LD document to RDF: %w", err)
+	}
+	nquads, ok := view.(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to convert JSON-LD document to RDF, invalid view")
+	}
+	lines := splitMessageIntoLines(nquads)
+	for i := range lines {
+		lines[i] = skolemIRI.ReplaceAllString(lines[i], "$1") + "\n"
+	}
+	return lines, nil
+}
+
+// CanonicalizeNQuads canonicalizes nquads and returns the sorted canonical statements
+// together with the map from input blank node labels to canonical labels (both without "_:").
+func (p *Processor) CanonicalizeNQuads(nquads []string) ([]string, map[string]string, error) {
+	dataset, err := ld.ParseNQuads(strings.Join(nquads, ""))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse N-Quads: %w", err)
+	}
+	// blank nodes are relabelled in place, so remember the input labels first
+	type labelled struct {
+		node  *ld.BlankNode
+		label string
+	}
+	var blankNodes []labelled
+	for graphName, quads := range dataset.Graphs {
+		for _, quad := range quads {
+			for _, node := range []ld.Node{quad.Subject, quad.Object} {
+				if bn, ok := node.(*ld.BlankNode); ok {
+					blankNodes = append(blankNodes, labelled{bn, bn.Attribute})
+				}
+			}
+			if strings.HasPrefix(graphName, "_:") {
+				quad.Graph = ld.NewBlankNode(graphName)
+				blankNodes = append(blankNodes, labelled{quad.Graph.(*ld.BlankNode), graphName})
+			}
+		}
+	}
+
+	ldOptions := ld.NewJsonLdOptions("")
+	ldOptions.Format = format
+	na := ld.NewNormalisationAlgorithm(p.algorithm)
+	view, err := na.Main(dataset, ldOptions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to normalize N-Quads: %w", err)
+	}
+	canonical, ok := view.(string)
+	if !ok {
+		return nil, nil, fmt.Errorf("failed to normalize N-Quads, invalid view")
+	}
+
+	labelMap := make(map[string]string, len(blankNodes))
+	for _, bn := range blankNodes {
+		labelMap[strings.TrimPrefix(bn.label, "_:")] = strings.TrimPrefix(bn.node.Attribute, "_:")
+	}
+	lines := splitMessageIntoLines(canonical)
+	for i := range lines {
+		lines[i] += "\n"
+	}
+	sort.Strings(lines)
+	return lines, labelMap, nil
+}
+
+func (p *Processor) ldOptions(procOptions *processorOpts) *ld.JsonLdOptions {
+	ldOptions := ld.NewJsonLdOptions("")
+	ldOptions.ProcessingMode = ld.JsonLd_1_1
+	ldOptions.Algorithm = p.algorithm
+	ldOptions.ProduceGeneralizedRdf = true
+	ldOptions.DocumentLoader = procOptions.loader()
+	return ldOptions
+}
+
+func skolemizeArray(a []interface{}, counter *int) {
+	for _, v := range a {
+		if m, ok := v.(map[string]interface{}); ok {
+			skolemizeNode(m, counter)
+		}
+	}
+}
+
+func skolemizeNode(m map[string]interface{}, counter *int) {
+	if _, ok := m["@value"]; ok {
+		return
+	}
+	if list, ok := m["@list"].([]interface{}); ok {
+		skolemizeArray(list, counter)
+		return
+	}
+	id, ok := m["@id"].(string)
+	if !ok {
+		m["@id"] = fmt.Sprintf("%s%s%d", skolemPrefix, skolemLabelPrefix, *counter)
+		*counter++
+	} else if strings.HasPrefix(id, "_:") {
+		m["@id"] = skolemPrefix + id
+	}
+	for k, v := range m {
+		if k == "@id" || k == "@type" || k == "@index" {
+			continue
+		}
+		if values, ok := v.([]interface{}); ok {
+			skolemizeArray(values, counter)
+		}
+	}
+}
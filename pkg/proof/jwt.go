@@ -50,6 +50,42 @@ func (jb *Jwt) Parse(jwt string) error {
 	return nil
 }
 
+// ValidateHeader checks the header is a valid RFC 7797 header of an unencoded
+// detached payload ("b64" false and marked critical) and returns its alg.
+func (jb *Jwt) ValidateHeader() (string, error) {
+	headerBytes, err := base64.RawURLEncoding.DecodeString(jb.header)
+	if err != nil {
+		return "", fmt.Errorf("invalid jws header encoding: %w", err)
+	}
+	header := make(map[string]interface{})
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return "", fmt.Errorf("invalid jws header: %w", err)
+	}
+	alg, ok := header["alg"].(string)
+	if !ok || alg == "" || alg == "none" {
+		return "", fmt.Errorf("invalid jws header alg")
+	}
+	if b64, ok := header["b64"].(bool); !ok || b64 {
+		return "", fmt.Errorf("jws header b64 must be false")
+	}
+	crit, ok := header["crit"].([]interface{})
+	if !ok {
+		return "", fmt.Errorf("jws header crit is missing")
+	}
+	hasB64 := false
+	for _, c := range crit {
+		// b64 is the only extension understood
+		if c != "b64" {
+			return "", fmt.Errorf("unsupported critical jws header %v", c)
+		}
+		hasB64 = true
+	}
+	if !hasB64 {
+		return "", fmt.Errorf("jws header b64 must be critical")
+	}
+	return alg, nil
+}
+
 func (jb *Jwt) Header() string {
 	return jb.header
 }
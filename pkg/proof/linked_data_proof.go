@@ -13,6 +13,8 @@ type LinkedDataProofContext struct {
 	Purpose                 string               // optional
 	// CapabilityChain must be an array. Each element is either a string or an object.
 	CapabilityChain []interface{}
+	// MandatoryPointers are JSON pointers of statements always disclosed by selective disclosure suites
+	MandatoryPointers []string
 }
 
 func (lpc *LinkedDataProofContext) ToContext() *Context {
@@ -25,6 +27,7 @@ func (lpc *LinkedDataProofContext) ToContext() *Context {
 		Domain:                  lpc.Domain,
 		Purpose:                 lpc.Purpose,
 		CapabilityChain:         lpc.CapabilityChain,
+		MandatoryPointers:       lpc.MandatoryPointers,
 	}
 }
 
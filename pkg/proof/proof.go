@@ -15,6 +15,7 @@ const (
 	SecurityContext        = "https://w3id.org/security/v2"
 	SecurityContextJWK2020 = "https://w3id.org/security/jws/v1"
 	BbsBlsSignature2020    = "BbsBlsSignature2020"
+	DataIntegrityProof     = "DataIntegrityProof"
 	defaultProofPurpose    = "assertionMethod"
 )
 
@@ -35,6 +36,7 @@ type Context struct {
 	Challenge               string               // optional
 	Purpose                 string               // optional
 	CapabilityChain         []interface{}        // optional
+	MandatoryPointers       []string             // optional
 }
 
 func (context *Context) Validate() error {
@@ -53,6 +55,7 @@ func (context *Context) Validate() error {
 type Proof struct {
 	Context                 interface{}          `json:"@context,omitempty"`
 	Type                    string               `json:"type,omitempty"`
+	Cryptosuite             string               `json:"cryptosuite,omitempty"`
 	Created                 *common.FormatedTime `json:"created,omitempty"`
 	Creator                 string               `json:"creator,omitempty"`
 	VerificationMethod      string               `json:"verificationMethod,omitempty"`
@@ -84,6 +87,9 @@ func NewProofFromMap(p map[string]interface{}) *Proof {
 		logrus.Error(err)
 		return nil
 	}
+	if ret.JWS != "" {
+		ret.SignatureRepresentation = SignatureJWS
+	}
 	return ret
 }
 
@@ -118,6 +124,15 @@ func (pf *Proof) ToBytes() []byte {
 	return b
 }
 
+// SuiteName returns the name of signature suite which created the proof.
+// For DataIntegrityProof it's the cryptosuite, otherwise it's the proof type.
+func (p *Proof) SuiteName() string {
+	if p.Type == DataIntegrityProof && p.Cryptosuite != "" {
+		return p.Cryptosuite
+	}
+	return p.Type
+}
+
 func (p *Proof) PublicKeyId() (string, error) {
 	if p.VerificationMethod != "" {
 		return p.VerificationMethod, nil
@@ -150,7 +165,9 @@ func (p *Proof) GetProofVerifyValue() ([]byte, error) {
 		return decodeBase64(p.ProofValue)
 	} else if p.SignatureRepresentation == 1 {
 		jwtb := NewJwt()
-		jwtb.Parse(p.JWS)
+		if err := jwtb.Parse(p.JWS); err != nil {
+			return nil, err
+		}
 		sig := jwtb.Signature()
 		return base64.RawURLEncoding.DecodeString(sig)
 	}
@@ -0,0 +1,223 @@
+package resolver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/suutaku/go-vc/pkg/jose"
+)
+
+// DID document contexts
+const (
+	DIDContext      = "https://www.w3.org/ns/did/v1"
+	MultikeyContext = "https://w3id.org/security/multikey/v1"
+)
+
+// verification relationships
+// https://www.w3.org/TR/did-core/#verification-relationships
+const (
+	Authentication       = "authentication"
+	AssertionMethod      = "assertionMethod"
+	KeyAgreement         = "keyAgreement"
+	CapabilityInvocation = "capabilityInvocation"
+	CapabilityDelegation = "capabilityDelegation"
+)
+
+// Document is a DID document
+// https://www.w3.org/TR/did-core/#core-properties
+type Document struct {
+	Context              interface{}           `json:"@context,omitempty"`
+	ID                   string                `json:"id"`
+	AlsoKnownAs          []string              `json:"alsoKnownAs,omitempty"`
+	Controller           interface{}           `json:"controller,omitempty"`
+	VerificationMethod   []*VerificationMethod `json:"verificationMethod,omitempty"`
+	Authentication       []*VerificationRef    `json:"authentication,omitempty"`
+	AssertionMethod      []*VerificationRef    `json:"assertionMethod,omitempty"`
+	KeyAgreement         []*VerificationRef    `json:"keyAgreement,omitempty"`
+	CapabilityInvocation []*VerificationRef    `json:"capabilityInvocation,omitempty"`
+	CapabilityDelegation []*VerificationRef    `json:"capabilityDelegation,omitempty"`
+	Service              []*Service            `json:"service,omitempty"`
+}
+
+// VerificationMethod is a public key of a DID document
+type VerificationMethod struct {
+	ID                 string    `json:"id"`
+	Type               string    `json:"type"`
+	Controller         string    `json:"controller"`
+	PublicKeyMultibase string    `json:"publicKeyMultibase,omitempty"`
+	PublicKeyBase58    string    `json:"publicKeyBase58,omitempty"`
+	PublicKeyJwk       *jose.JWK `json:"publicKeyJwk,omitempty"`
+}
+
+// VerificationRef is an entry of a verification relationship, a reference to a verification method
+// or an embedded one
+type VerificationRef struct {
+	ID       string
+	Embedded *VerificationMethod
+}
+
+func (ref *VerificationRef) MarshalJSON() ([]byte, error) {
+	if ref.Embedded != nil {
+		return json.Marshal(ref.Embedded)
+	}
+	return json.Marshal(ref.ID)
+}
+
+func (ref *VerificationRef) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &ref.ID); err == nil {
+		return nil
+	}
+	vm := &VerificationMethod{}
+	if err := json.Unmarshal(b, vm); err != nil {
+		return fmt.Errorf("verification relationship entry is neither a reference nor a method: %w", err)
+	}
+	ref.ID = vm.ID
+	ref.Embedded = vm
+	return nil
+}
+
+// Service is a service endpoint of a DID document
+type Service struct {
+	ID              string      `json:"id"`
+	Type            interface{} `json:"type"`
+	ServiceEndpoint interface{} `json:"serviceEndpoint"`
+}
+
+// ParseDocument parses a JSON DID document, empty publicKeyJwk objects are ignored
+func ParseDocument(b []byte) (*Document, error) {
+	doc := &Document{}
+	if err := json.Unmarshal(b, doc); err != nil {
+		return nil, fmt.Errorf("invalid DID document: %w", err)
+	}
+	if doc.ID == "" {
+		return nil, errors.New("invalid DID document: id is missing")
+	}
+	for _, vm := range doc.methods() {
+		if vm.PublicKeyJwk != nil && vm.PublicKeyJwk.Kty == "" {
+			vm.PublicKeyJwk = nil
+		}
+	}
+	return doc, nil
+}
+
+// Relationship returns the entries of verification relationship name
+func (doc *Document) Relationship(name string) ([]*VerificationRef, error) {
+	switch name {
+	case Authentication:
+		return doc.Authentication, nil
+	case AssertionMethod:
+		return doc.AssertionMethod, nil
+	case KeyAgreement:
+		return doc.KeyAgreement, nil
+	case CapabilityInvocation:
+		return doc.CapabilityInvocation, nil
+	case CapabilityDelegation:
+		return doc.CapabilityDelegation, nil
+	}
+	return nil, fmt.Errorf("unknown verification relationship %s", name)
+}
+
+// VerificationMethodByID returns verification method id, declared or embedded in a relationship.
+// id is a DID URL or a fragment relative to the document, e.g. #key-1.
+func (doc *Document) VerificationMethodByID(id string) (*VerificationMethod, error) {
+	id = doc.absoluteURL(id)
+	for _, vm := range doc.methods() {
+		if doc.absoluteURL(vm.ID) == id {
+			return vm, nil
+		}
+	}
+	return nil, fmt.Errorf("verification method %s not found in %s", id, doc.ID)
+}
+
+// VerificationMethodFor returns verification method id if it is authorised for verification relationship
+// name, e.g. the assertionMethod proof purpose
+func (doc *Document) VerificationMethodFor(id, name string) (*VerificationMethod, error) {
+	refs, err := doc.Relationship(name)
+	if err != nil {
+		return nil, err
+	}
+	id = doc.absoluteURL(id)
+	for _, ref := range refs {
+		if doc.absoluteURL(ref.ID) != id {
+			continue
+		}
+		if ref.Embedded != nil {
+			return ref.Embedded, nil
+		}
+		return doc.VerificationMethodByID(id)
+	}
+	return nil, fmt.Errorf("verification method %s is not authorised for %s by %s", id, name, doc.ID)
+}
+
+// PublicKey returns the public key of verification method id
+func (doc *Document) PublicKey(id string) (*PublicKey, error) {
+	vm, err := doc.VerificationMethodByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return vm.PublicKey()
+}
+
+// methods returns the declared and the embedded verification methods
+func (doc *Document) methods() []*VerificationMethod {
+	ret := append([]*VerificationMethod{}, doc.VerificationMethod...)
+	for _, refs := range [][]*VerificationRef{doc.Authentication, doc.AssertionMethod, doc.KeyAgreement,
+		doc.CapabilityInvocation, doc.CapabilityDelegation} {
+		for _, ref := range refs {
+			if ref.Embedded != nil {
+				ret = append(ret, ref.Embedded)
+			}
+		}
+	}
+	return ret
+}
+
+// absoluteURL resolves a relative DID URL, e.g. #key-1, against the document id
+func (doc *Document) absoluteURL(id string) string {
+	if strings.HasPrefix(id, "#") {
+		return doc.ID + id
+	}
+	return id
+}
+
+// PublicKey converts the key material of the verification method to PublicKey
+func (vm *VerificationMethod) PublicKey() (*PublicKey, error) {
+	switch {
+	case vm.PublicKeyJwk != nil:
+		return publicKeyFromJose(vm.Type, vm.PublicKeyJwk)
+	case vm.PublicKeyMultibase != "":
+		return publicKeyFromMultikey(vm.Type, vm.PublicKeyMultibase)
+	case vm.PublicKeyBase58 != "":
+		value := base58.Decode(vm.PublicKeyBase58)
+		if len(value) == 0 {
+			return nil, fmt.Errorf("invalid publicKeyBase58 of %s", vm.ID)
+		}
+		return &PublicKey{Type: vm.Type, Value: value}, nil
+	}
+	return nil, fmt.Errorf("verification method %s has no public key", vm.ID)
+}
+
+// publicKeyFromJose converts a JWK to PublicKey, Value is the raw key, SEC1 compressed for elliptic curves
+func publicKeyFromJose(keyType string, jwk *jose.JWK) (*PublicKey, error) {
+	if jwk.Crv == "BLS12381_G2" || jwk.Crv == "X25519" {
+		value, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(jwk.X, "="))
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk: %w", err)
+		}
+		return &PublicKey{Type: keyType, Value: value, Jwk: jwk}, nil
+	}
+	pub, err := jwk.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	_, value, err := multicodecKey(pub)
+	if err != nil {
+		// RSA keys are only used with JWS
+		return &PublicKey{Type: keyType, Jwk: jwk}, nil
+	}
+	return &PublicKey{Type: keyType, Value: value, Jwk: jwk}, nil
+}
@@ -0,0 +1,107 @@
+package resolver
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/ComputingOfThings/dids/pkg/dids"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerificationRelationships(t *testing.T) {
+	keys := make([]ed25519.PublicKey, 3)
+	mks := make([]string, 3)
+	for i := range keys {
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		keys[i] = pub
+		mks[i], err = MultikeyOf(pub)
+		assert.NoError(t, err)
+	}
+	did := "did:example:123"
+	raw := `{
+		"id": "` + did + `",
+		"verificationMethod": [
+			{"id": "#key-1", "type": "Multikey", "controller": "` + did + `", "publicKeyMultibase": "` + mks[0] + `"},
+			{"id": "` + did + `#key-2", "type": "Multikey", "controller": "` + did + `", "publicKeyMultibase": "` + mks[1] + `",
+			 "publicKeyJwk": {}}
+		],
+		"assertionMethod": ["#key-1"],
+		"authentication": [
+			"` + did + `#key-2",
+			{"id": "#auth", "type": "Multikey", "controller": "` + did + `", "publicKeyMultibase": "` + mks[2] + `"}
+		]
+	}`
+	doc, err := ParseDocument([]byte(raw))
+	require.NoError(t, err)
+
+	// full DID URLs and relative fragments, declared and embedded methods
+	for id, pub := range map[string]ed25519.PublicKey{
+		did + "#key-1": keys[0], "#key-1": keys[0],
+		"#key-2": keys[1], did + "#auth": keys[2], "#auth": keys[2],
+	} {
+		key, err := doc.PublicKey(id)
+		require.NoError(t, err, id)
+		assert.Equal(t, []byte(pub), key.Value, id)
+	}
+	_, err = doc.PublicKey("#key-3")
+	assert.Error(t, err)
+
+	for id, relationship := range map[string]string{
+		did + "#key-1": AssertionMethod, "#key-2": Authentication, did + "#auth": Authentication,
+	} {
+		vm, err := doc.VerificationMethodFor(id, relationship)
+		require.NoError(t, err, id)
+		assert.Equal(t, "Multikey", vm.Type)
+	}
+	_, err = doc.VerificationMethodFor(did+"#key-1", Authentication)
+	assert.ErrorContains(t, err, "not authorised for authentication")
+	_, err = doc.VerificationMethodFor("#auth", AssertionMethod)
+	assert.Error(t, err)
+	_, err = doc.VerificationMethodFor("#key-1", "publish")
+	assert.ErrorContains(t, err, "unknown verification relationship")
+
+	// proof purposes checked with resolved documents
+	keyDID, err := NewKeyDID(keys[0])
+	require.NoError(t, err)
+	check := RelationshipCheckerOf(NewKeyDIDResolver())
+	assert.NoError(t, check(keyDID, AssertionMethod))
+	assert.Error(t, check(keyDID, KeyAgreement))
+}
+
+func TestLocalResolver(t *testing.T) {
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	did := "did:example:123"
+	doc := &dids.DIDDocument{
+		ID: did,
+		VerificationMethod: []dids.VerificationMethod{
+			{ID: did + "#key-1", Type: "Bls12381G2Key2020", Controller: did, PublicKeyBase58: base58.Encode([]byte("g2"))},
+			{ID: did + "#key-2", Type: "Ed25519VerificationKey2018", Controller: did, PublicKeyBase58: base58.Encode(edPub)},
+		},
+	}
+	res := NewLocalResolver(doc)
+	// the method of the id is resolved, not the first one
+	for _, id := range []string{did + "#key-2", "#key-2"} {
+		pub, err := res.Resolve(id)
+		require.NoError(t, err)
+		assert.Equal(t, "Ed25519VerificationKey2018", pub.Type)
+		assert.Equal(t, []byte(edPub), pub.Value)
+	}
+	_, err = res.Resolve(did)
+	assert.Error(t, err)
+	_, err = res.Resolve(did + "#key-3")
+	assert.Error(t, err)
+
+	result, err := res.ResolveDID(did)
+	require.NoError(t, err)
+	b, err := json.Marshal(result.Document)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "publicKeyJwk")
+	_, err = res.ResolveDID("did:example:456")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -1,25 +1,16 @@
 package resolver
 
 import (
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
-
-	"github.com/ComputingOfThings/dids/pkg/dids"
-	"github.com/btcsuite/btcutil/base58"
-)
-
-const (
-	ldKeyType = "JsonWebKey2020"
-	typeG1    = "Bls12381G1Key2020"
-	typeG2    = "Bls12381G2Key2020"
 )
 
+// HTTPResolver resolves public keys with the /did/{did} endpoint of a DID service, use UniversalResolver
+// for full DID resolution results
 type HTTPResolver struct {
 	base string
 }
@@ -30,47 +21,39 @@ func NewHTTPResolver(url string) *HTTPResolver {
 	}
 }
 
+// Resolve returns the public key of verification method id, the fragment may be omitted when the
+// document has a single verification method
 func (res *HTTPResolver) Resolve(id string) (*PublicKey, error) {
-	sp := strings.Split(id, "#")
+	did, _, _ := strings.Cut(id, "#")
+	result, err := res.ResolveDID(did)
+	if err != nil {
+		return nil, err
+	}
+	return resultPublicKey(result, id)
+}
+
+// ResolveDID returns the resolution result of the DID document returned by the DID service
+func (res *HTTPResolver) ResolveDID(did string) (*ResolutionResult, error) {
 	url, err := url.Parse(res.base)
 	if err != nil {
 		return nil, err
 	}
-	url.Path = path.Join(url.Path, "did", sp[0])
+	url.Path = path.Join(url.Path, "did", did)
 	resp, err := http.Get(url.String())
-	if err != nil || resp.StatusCode != 200 {
-		msg, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("%v %s", err, msg)
+	if err != nil {
+		return nil, err
 	}
-	did := &dids.DIDDocument{}
-	err = json.NewDecoder(resp.Body).Decode(did)
-	if err != nil || len(did.VerificationMethod) == 0 {
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentBytes))
+	if err != nil {
 		return nil, err
 	}
-	ret := &PublicKey{Type: did.VerificationMethod[0].Type}
-
-	for _, v := range did.VerificationMethod {
-		if v.ID != id {
-			continue
-		}
-		switch ret.Type {
-		case ldKeyType:
-			if v.PublicKeyJWK.Crv != "BLS12381_G2" || v.PublicKeyJWK.Kty != "EC" {
-				return nil, fmt.Errorf("invalid jwk")
-			}
-			ret.Jwk, err = base64.URLEncoding.DecodeString(v.PublicKeyJWK.X)
-			if err != nil {
-				return nil, err
-			}
-		case typeG2:
-			ret.Value = base58.Decode(v.PublicKeyBase58)
-			if ret.Value == nil {
-				return nil, err
-			}
-		case typeG1:
-			return nil, err
-		}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s", resp.Status, b)
 	}
-
-	return ret, nil
+	doc, err := ParseDocument(b)
+	if err != nil {
+		return nil, err
+	}
+	return documentResult(doc), nil
 }
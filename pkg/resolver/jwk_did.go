@@ -0,0 +1,95 @@
+package resolver
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/suutaku/go-vc/pkg/jose"
+)
+
+const (
+	jwkDIDPrefix = "did:jwk:"
+	jwkKeyType   = "JsonWebKey2020"
+)
+
+// JWKDIDResolver resolves did:jwk DIDs offline, the DID document is derived from the JWK encoded in the DID
+// https://github.com/quartzjer/did-jwk/blob/main/spec.md
+type JWKDIDResolver struct{}
+
+func NewJWKDIDResolver() *JWKDIDResolver {
+	return &JWKDIDResolver{}
+}
+
+// NewJWKDID returns the did:jwk DID of a public JWK
+func NewJWKDID(jwk *jose.JWK) (string, error) {
+	b, err := json.Marshal(jwk)
+	if err != nil {
+		return "", err
+	}
+	return jwkDIDPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ResolveDocument returns the DID document of a did:jwk DID
+func (res *JWKDIDResolver) ResolveDocument(did string) (*Document, error) {
+	if !strings.HasPrefix(did, jwkDIDPrefix) {
+		return nil, fmt.Errorf("%w: %s is not a did:jwk DID", ErrMethodNotSupported, did)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(did, jwkDIDPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid did:jwk %s: %v", ErrInvalidDID, did, err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, fmt.Errorf("%w: invalid did:jwk %s: %v", ErrInvalidDID, did, err)
+	}
+	if _, ok := fields["d"]; ok {
+		return nil, fmt.Errorf("%w: invalid did:jwk %s: private key", ErrInvalidDID, did)
+	}
+	jwk, err := jose.ParseJWK(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid did:jwk %s: %v", ErrInvalidDID, did, err)
+	}
+	vm := &VerificationMethod{
+		ID:           did + "#0",
+		Type:         jwkKeyType,
+		Controller:   did,
+		PublicKeyJwk: jwk,
+	}
+	if _, err := vm.PublicKey(); err != nil {
+		return nil, fmt.Errorf("%w: invalid did:jwk %s: %v", ErrInvalidDID, did, err)
+	}
+	doc := &Document{
+		Context:            []interface{}{DIDContext, "https://w3id.org/security/suites/jws-2020/v1"},
+		ID:                 did,
+		VerificationMethod: []*VerificationMethod{vm},
+	}
+	ref := []*VerificationRef{{ID: vm.ID}}
+	// signature keys are not used for key agreement and encryption keys are not used to sign
+	if jwk.Use != "sig" {
+		doc.KeyAgreement = ref
+	}
+	if jwk.Use != "enc" {
+		doc.Authentication = ref
+		doc.AssertionMethod = ref
+		doc.CapabilityInvocation = ref
+		doc.CapabilityDelegation = ref
+	}
+	return doc, nil
+}
+
+// ResolveDID returns the resolution result of a did:jwk DID
+func (res *JWKDIDResolver) ResolveDID(did string) (*ResolutionResult, error) {
+	doc, err := res.ResolveDocument(did)
+	if err != nil {
+		return nil, err
+	}
+	return documentResult(doc), nil
+}
+
+// Resolve returns the public key of verification method id, the fragment may be omitted
+func (res *JWKDIDResolver) Resolve(id string) (*PublicKey, error) {
+	return resolvePublicKey(context.Background(), res, id)
+}
@@ -0,0 +1,51 @@
+package resolver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suutaku/go-vc/pkg/jose"
+)
+
+func TestJWKDIDResolver(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwk, err := jose.NewJWK(&p256.PublicKey)
+	require.NoError(t, err)
+	did, err := NewJWKDID(jwk)
+	require.NoError(t, err)
+
+	res := NewJWKDIDResolver()
+	doc, err := res.ResolveDocument(did)
+	require.NoError(t, err)
+	assert.Equal(t, did+"#0", doc.VerificationMethod[0].ID)
+	assert.Equal(t, "JsonWebKey2020", doc.VerificationMethod[0].Type)
+	assert.Len(t, doc.AssertionMethod, 1)
+	assert.Len(t, doc.KeyAgreement, 1)
+	pub, err := res.Resolve(did + "#0")
+	require.NoError(t, err)
+	assert.True(t, jwk.Equal(pub.Jwk))
+	assert.Equal(t, elliptic.MarshalCompressed(elliptic.P256(), p256.X, p256.Y), pub.Value)
+
+	// key use restricts verification relationships
+	jwk.Use = "enc"
+	did, err = NewJWKDID(jwk)
+	require.NoError(t, err)
+	doc, err = res.ResolveDocument(did)
+	require.NoError(t, err)
+	assert.Empty(t, doc.AssertionMethod)
+	assert.Len(t, doc.KeyAgreement, 1)
+
+	// private keys are rejected
+	b, _ := json.Marshal(map[string]string{"kty": "OKP", "crv": "Ed25519", "x": jwk.X, "d": jwk.X})
+	_, err = res.Resolve("did:jwk:" + base64.RawURLEncoding.EncodeToString(b))
+	assert.ErrorContains(t, err, "private key")
+	_, err = res.Resolve("did:jwk:not-base64!")
+	assert.Error(t, err)
+}
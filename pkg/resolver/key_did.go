@@ -0,0 +1,69 @@
+package resolver
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"strings"
+)
+
+const keyDIDPrefix = "did:key:"
+
+// KeyDIDResolver resolves did:key DIDs offline, the DID document is derived from the key encoded in the DID
+// https://w3c-ccg.github.io/did-method-key/
+type KeyDIDResolver struct{}
+
+func NewKeyDIDResolver() *KeyDIDResolver {
+	return &KeyDIDResolver{}
+}
+
+// NewKeyDID returns the did:key DID of a public key, see MultikeyOf for supported keys
+func NewKeyDID(pub crypto.PublicKey) (string, error) {
+	mk, err := MultikeyOf(pub)
+	if err != nil {
+		return "", err
+	}
+	return keyDIDPrefix + mk, nil
+}
+
+// ResolveDocument returns the DID document of a did:key DID
+func (res *KeyDIDResolver) ResolveDocument(did string) (*Document, error) {
+	if !strings.HasPrefix(did, keyDIDPrefix) {
+		return nil, fmt.Errorf("%w: %s is not a did:key DID", ErrMethodNotSupported, did)
+	}
+	mk := strings.TrimPrefix(did, keyDIDPrefix)
+	vm := &VerificationMethod{
+		ID:                 did + "#" + mk,
+		Type:               multikeyType,
+		Controller:         did,
+		PublicKeyMultibase: mk,
+	}
+	// the key must be valid
+	if _, err := vm.PublicKey(); err != nil {
+		return nil, fmt.Errorf("%w: invalid did:key %s: %v", ErrInvalidDID, did, err)
+	}
+	ref := []*VerificationRef{{ID: vm.ID}}
+	return &Document{
+		Context:              []interface{}{DIDContext, MultikeyContext},
+		ID:                   did,
+		VerificationMethod:   []*VerificationMethod{vm},
+		Authentication:       ref,
+		AssertionMethod:      ref,
+		CapabilityInvocation: ref,
+		CapabilityDelegation: ref,
+	}, nil
+}
+
+// ResolveDID returns the resolution result of a did:key DID
+func (res *KeyDIDResolver) ResolveDID(did string) (*ResolutionResult, error) {
+	doc, err := res.ResolveDocument(did)
+	if err != nil {
+		return nil, err
+	}
+	return documentResult(doc), nil
+}
+
+// Resolve returns the public key of verification method id, the fragment may be omitted
+func (res *KeyDIDResolver) Resolve(id string) (*PublicKey, error) {
+	return resolvePublicKey(context.Background(), res, id)
+}
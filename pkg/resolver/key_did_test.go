@@ -0,0 +1,86 @@
+package resolver
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suutaku/go-bbs/pkg/bbs"
+	"github.com/suutaku/go-vc/test"
+)
+
+func TestKeyDIDResolver(t *testing.T) {
+	// https://w3c-ccg.github.io/did-method-key/#example-a-simple-ed25519-did-key-value
+	did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+	res := NewKeyDIDResolver()
+	doc, err := res.ResolveDocument(did)
+	require.NoError(t, err)
+	vmID := did + "#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+	assert.Equal(t, vmID, doc.VerificationMethod[0].ID)
+	assert.Equal(t, "Multikey", doc.VerificationMethod[0].Type)
+	assert.Equal(t, vmID, doc.AssertionMethod[0].ID)
+	b, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"authentication":["`+vmID+`"]`)
+
+	pub, err := res.Resolve(vmID)
+	require.NoError(t, err)
+	assert.Len(t, pub.Value, ed25519.PublicKeySize)
+	assert.Equal(t, "OKP", pub.Jwk.Kty)
+	noFragment, err := res.Resolve(did)
+	require.NoError(t, err)
+	assert.True(t, pub.Equal(noFragment))
+
+	_, err = res.Resolve(did + "#other")
+	assert.ErrorContains(t, err, "not found")
+	_, err = res.Resolve("did:web:example.com")
+	assert.Error(t, err)
+	_, err = res.Resolve("did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnn")
+	assert.ErrorContains(t, err, "invalid did:key")
+}
+
+func TestKeyDIDKeyTypes(t *testing.T) {
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	k1, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	blsKeyStr, err := test.GetTestResource("issuer-private-key.txt")
+	require.NoError(t, err)
+	blsKeyBytes, err := hex.DecodeString(string(blsKeyStr))
+	require.NoError(t, err)
+	blsPriv, err := bbs.UnmarshalPrivateKey(blsKeyBytes)
+	require.NoError(t, err)
+	blsPub, err := blsPriv.PublicKey().Marshal()
+	require.NoError(t, err)
+
+	for _, c := range []struct {
+		pub    crypto.PublicKey
+		prefix string
+		value  []byte
+		jwk    bool
+	}{
+		{edPub, "did:key:z6Mk", edPub, true},
+		{&p256.PublicKey, "did:key:zDn", elliptic.MarshalCompressed(elliptic.P256(), p256.X, p256.Y), true},
+		{k1.PubKey(), "did:key:zQ3s", k1.PubKey().SerializeCompressed(), true},
+		{blsPriv.PublicKey(), "did:key:zUC7", blsPub, false},
+	} {
+		did, err := NewKeyDID(c.pub)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(did, c.prefix), did)
+		pub, err := NewKeyDIDResolver().Resolve(did + "#" + strings.TrimPrefix(did, "did:key:"))
+		require.NoError(t, err, did)
+		assert.Equal(t, c.value, pub.Value, did)
+		assert.Equal(t, c.jwk, pub.Jwk != nil, did)
+	}
+}
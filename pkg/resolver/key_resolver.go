@@ -3,12 +3,14 @@ package resolver
 import (
 	"bytes"
 	"crypto"
+
+	"github.com/suutaku/go-vc/pkg/jose"
 )
 
 type PublicKey struct {
 	Type  string
 	Value []byte
-	Jwk   []byte
+	Jwk   *jose.JWK
 }
 
 func (pbk *PublicKey) Equal(x crypto.PublicKey) bool {
@@ -20,7 +22,7 @@ func (pbk *PublicKey) Equal(x crypto.PublicKey) bool {
 	if pbkc.Type != pbk.Type {
 		return false
 	}
-	return bytes.Equal(pbkc.Value, pbk.Value) && bytes.Equal(pbkc.Jwk, pbk.Jwk)
+	return bytes.Equal(pbkc.Value, pbk.Value) && pbkc.Jwk.Equal(pbk.Jwk)
 
 }
 
@@ -1,13 +1,13 @@
 package resolver
 
 import (
-	"encoding/base64"
+	"encoding/json"
 	"fmt"
 
 	"github.com/ComputingOfThings/dids/pkg/dids"
-	"github.com/btcsuite/btcutil/base58"
 )
 
+// LocalResolver resolves verification methods of a DID document held in memory
 type LocalResolver struct {
 	didDoc *dids.DIDDocument
 }
@@ -18,26 +18,33 @@ func NewLocalResolver(didDoc *dids.DIDDocument) *LocalResolver {
 	}
 }
 
+// Resolve returns the public key of verification method url, a DID URL or a fragment relative to the
+// document, the fragment may be omitted when the document has a single verification method
 func (res *LocalResolver) Resolve(url string) (*PublicKey, error) {
+	doc, err := res.Document()
+	if err != nil {
+		return nil, err
+	}
+	return resultPublicKey(documentResult(doc), url)
+}
+
+// ResolveDID returns the resolution result of the DID of the document
+func (res *LocalResolver) ResolveDID(did string) (*ResolutionResult, error) {
+	doc, err := res.Document()
+	if err != nil {
+		return nil, err
+	}
+	if did != doc.ID {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, did)
+	}
+	return documentResult(doc), nil
+}
 
-	ret := &PublicKey{Type: res.didDoc.VerificationMethod[0].Type}
-	var err error
-	switch ret.Type {
-	case ldKeyType:
-		if res.didDoc.VerificationMethod[0].PublicKeyJWK.Crv != "BLS12381_G2" || res.didDoc.VerificationMethod[0].PublicKeyJWK.Kty != "EC" {
-			return nil, fmt.Errorf("invalid jwk")
-		}
-		ret.Jwk, err = base64.URLEncoding.DecodeString(res.didDoc.VerificationMethod[0].PublicKeyJWK.X)
-		if err != nil {
-			return nil, err
-		}
-	case typeG2:
-		ret.Value = base58.Decode(res.didDoc.VerificationMethod[0].PublicKeyBase58)
-		if ret.Value == nil {
-			return nil, err
-		}
-	case typeG1:
+// Document returns the DID document
+func (res *LocalResolver) Document() (*Document, error) {
+	b, err := json.Marshal(res.didDoc)
+	if err != nil {
 		return nil, err
 	}
-	return ret, nil
+	return ParseDocument(b)
 }
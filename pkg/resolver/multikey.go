@@ -0,0 +1,130 @@
+package resolver
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/suutaku/go-bbs/pkg/bbs"
+	"github.com/suutaku/go-vc/pkg/jose"
+)
+
+// multicodec codes of public keys
+// https://github.com/multiformats/multicodec/blob/master/table.csv
+const (
+	CodecSecp256k1Pub uint64 = 0xe7
+	CodecBls12381G2   uint64 = 0xeb
+	CodecX25519Pub    uint64 = 0xec
+	CodecEd25519Pub   uint64 = 0xed
+	CodecP256Pub      uint64 = 0x1200
+	CodecP384Pub      uint64 = 0x1201
+)
+
+// multikeyType is the verification method type of multibase encoded keys
+const multikeyType = "Multikey"
+
+// DecodeMultikey decodes a base58-btc multibase, multicodec prefixed public key
+func DecodeMultikey(multibase string) (uint64, []byte, error) {
+	if len(multibase) < 2 || multibase[0] != 'z' {
+		return 0, nil, errors.New("multikey must be base58-btc multibase encoded")
+	}
+	b := base58.Decode(multibase[1:])
+	codec, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, nil, errors.New("invalid multicodec prefix")
+	}
+	return codec, b[n:], nil
+}
+
+// EncodeMultikey encodes a raw public key with its multicodec prefix as base58-btc multibase
+func EncodeMultikey(codec uint64, key []byte) string {
+	b := binary.AppendUvarint(nil, codec)
+	return "z" + base58.Encode(append(b, key...))
+}
+
+// MultikeyOf returns the multikey encoding of an ed25519.PublicKey, *ecdsa.PublicKey (P-256 or P-384),
+// *secp256k1.PublicKey or *bbs.PublicKey (BLS12-381 G2)
+func MultikeyOf(pub crypto.PublicKey) (string, error) {
+	codec, key, err := multicodecKey(pub)
+	if err != nil {
+		return "", err
+	}
+	return EncodeMultikey(codec, key), nil
+}
+
+// multicodecKey returns the multicodec and raw bytes of a public key, SEC1 compressed for elliptic curves
+func multicodecKey(pub crypto.PublicKey) (uint64, []byte, error) {
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		return CodecEd25519Pub, k, nil
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return CodecP256Pub, elliptic.MarshalCompressed(k.Curve, k.X, k.Y), nil
+		case elliptic.P384():
+			return CodecP384Pub, elliptic.MarshalCompressed(k.Curve, k.X, k.Y), nil
+		}
+		return 0, nil, fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+	case *secp256k1.PublicKey:
+		return CodecSecp256k1Pub, k.SerializeCompressed(), nil
+	case *bbs.PublicKey:
+		b, err := k.Marshal()
+		return CodecBls12381G2, b, err
+	}
+	return 0, nil, fmt.Errorf("unsupported public key type %T", pub)
+}
+
+// publicKeyFromMultikey converts a multikey to PublicKey. Value is the raw key, SEC1 compressed for
+// elliptic curve keys, Jwk is set for keys usable with JWS.
+func publicKeyFromMultikey(keyType, multibase string) (*PublicKey, error) {
+	codec, key, err := DecodeMultikey(multibase)
+	if err != nil {
+		return nil, err
+	}
+	var pub crypto.PublicKey
+	switch codec {
+	case CodecEd25519Pub:
+		if len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 public key size %d", len(key))
+		}
+		pub = ed25519.PublicKey(key)
+	case CodecP256Pub, CodecP384Pub:
+		curve := elliptic.P256()
+		if codec == CodecP384Pub {
+			curve = elliptic.P384()
+		}
+		x, y := elliptic.UnmarshalCompressed(curve, key)
+		if x == nil {
+			return nil, fmt.Errorf("invalid %s public key", curve.Params().Name)
+		}
+		pub = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+	case CodecSecp256k1Pub:
+		if pub, err = secp256k1.ParsePubKey(key); err != nil {
+			return nil, fmt.Errorf("invalid secp256k1 public key: %w", err)
+		}
+	case CodecBls12381G2:
+		if _, err := bbs.UnmarshalPublicKey(key); err != nil {
+			return nil, fmt.Errorf("invalid BLS12-381 G2 public key: %w", err)
+		}
+		return &PublicKey{Type: keyType, Value: key}, nil
+	case CodecX25519Pub:
+		// key agreement only
+		if len(key) != 32 {
+			return nil, fmt.Errorf("invalid X25519 public key size %d", len(key))
+		}
+		return &PublicKey{Type: keyType, Value: key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported multicodec 0x%x", codec)
+	}
+	jwk, err := jose.NewJWK(pub)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicKey{Type: keyType, Value: key, Jwk: jwk}, nil
+}
@@ -0,0 +1,192 @@
+package resolver
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const peerDIDPrefix = "did:peer:"
+
+// peer DID numalgo 2 element purposes
+var peerPurposes = map[byte]string{
+	'A': AssertionMethod,
+	'E': KeyAgreement,
+	'V': Authentication,
+	'I': CapabilityInvocation,
+	'D': CapabilityDelegation,
+}
+
+// abbreviations of numalgo 2 service keys
+var peerServiceAbbreviations = map[string]string{
+	"t": "type",
+	"s": "serviceEndpoint",
+	"r": "routingKeys",
+	"a": "accept",
+}
+
+// PeerDIDResolver resolves did:peer DIDs of numalgo 0 (inception key) and 2 (multiple inception keys
+// and services) offline
+// https://identity.foundation/peer-did-method-spec/
+type PeerDIDResolver struct{}
+
+func NewPeerDIDResolver() *PeerDIDResolver {
+	return &PeerDIDResolver{}
+}
+
+// ResolveDocument returns the DID document of a did:peer DID
+func (res *PeerDIDResolver) ResolveDocument(did string) (*Document, error) {
+	if !strings.HasPrefix(did, peerDIDPrefix) || len(did) < len(peerDIDPrefix)+2 {
+		return nil, fmt.Errorf("%w: %s is not a did:peer DID", ErrMethodNotSupported, did)
+	}
+	numalgo, id := did[len(peerDIDPrefix)], did[len(peerDIDPrefix)+1:]
+	switch numalgo {
+	case '0':
+		return peerDocument0(did, id)
+	case '2':
+		return peerDocument2(did, id)
+	}
+	return nil, fmt.Errorf("%w: unsupported did:peer numalgo %c", ErrInvalidDID, numalgo)
+}
+
+// ResolveDID returns the resolution result of a did:peer DID
+func (res *PeerDIDResolver) ResolveDID(did string) (*ResolutionResult, error) {
+	doc, err := res.ResolveDocument(did)
+	if err != nil {
+		return nil, err
+	}
+	return documentResult(doc), nil
+}
+
+// Resolve returns the public key of verification method id
+func (res *PeerDIDResolver) Resolve(id string) (*PublicKey, error) {
+	return resolvePublicKey(context.Background(), res, id)
+}
+
+// peerDocument0 returns the document of an inception key, derived as for did:key
+func peerDocument0(did, mk string) (*Document, error) {
+	doc, err := NewKeyDIDResolver().ResolveDocument(keyDIDPrefix + mk)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid did:peer %s: %v", ErrInvalidDID, did, err)
+	}
+	vm := doc.VerificationMethod[0]
+	vm.ID = did + "#" + mk
+	vm.Controller = did
+	ref := []*VerificationRef{{ID: vm.ID}}
+	doc.ID = did
+	doc.Authentication = ref
+	doc.AssertionMethod = ref
+	doc.CapabilityInvocation = ref
+	doc.CapabilityDelegation = ref
+	return doc, nil
+}
+
+// peerDocument2 returns the document of the purpose prefixed keys and services of elements
+func peerDocument2(did, elements string) (*Document, error) {
+	doc := &Document{
+		Context: []interface{}{DIDContext, MultikeyContext},
+		ID:      did,
+	}
+	for _, element := range strings.Split(strings.TrimPrefix(elements, "."), ".") {
+		if len(element) < 2 {
+			return nil, fmt.Errorf("%w: invalid did:peer %s: empty element", ErrInvalidDID, did)
+		}
+		purpose, value := element[0], element[1:]
+		if purpose == 'S' {
+			service, err := peerService(value, did, len(doc.Service))
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid did:peer %s: %v", ErrInvalidDID, did, err)
+			}
+			doc.Service = append(doc.Service, service)
+			continue
+		}
+		relationship, ok := peerPurposes[purpose]
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid did:peer %s: unknown purpose %c", ErrInvalidDID, did, purpose)
+		}
+		vm := &VerificationMethod{
+			ID:                 did + "#key-" + strconv.Itoa(len(doc.VerificationMethod)+1),
+			Type:               multikeyType,
+			Controller:         did,
+			PublicKeyMultibase: value,
+		}
+		if _, err := vm.PublicKey(); err != nil {
+			return nil, fmt.Errorf("%w: invalid did:peer %s: %v", ErrInvalidDID, did, err)
+		}
+		doc.VerificationMethod = append(doc.VerificationMethod, vm)
+		ref := &VerificationRef{ID: vm.ID}
+		switch relationship {
+		case AssertionMethod:
+			doc.AssertionMethod = append(doc.AssertionMethod, ref)
+		case KeyAgreement:
+			doc.KeyAgreement = append(doc.KeyAgreement, ref)
+		case Authentication:
+			doc.Authentication = append(doc.Authentication, ref)
+		case CapabilityInvocation:
+			doc.CapabilityInvocation = append(doc.CapabilityInvocation, ref)
+		case CapabilityDelegation:
+			doc.CapabilityDelegation = append(doc.CapabilityDelegation, ref)
+		}
+	}
+	return doc, nil
+}
+
+// peerService decodes an abbreviated base64url encoded service, the index-th of the DID
+func peerService(encoded, did string, index int) (*Service, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid service: %w", err)
+	}
+	var abbreviated map[string]interface{}
+	if err := json.Unmarshal(b, &abbreviated); err != nil {
+		return nil, fmt.Errorf("invalid service: %w", err)
+	}
+	expanded := expandPeerService(abbreviated).(map[string]interface{})
+	service := &Service{
+		Type:            expanded["type"],
+		ServiceEndpoint: expanded["serviceEndpoint"],
+	}
+	if service.Type == nil || service.ServiceEndpoint == nil {
+		return nil, fmt.Errorf("service type and endpoint are required")
+	}
+	switch id, _ := expanded["id"].(string); {
+	case strings.HasPrefix(id, "#"):
+		service.ID = did + id
+	case id != "":
+		service.ID = id
+	case index == 0:
+		service.ID = did + "#service"
+	default:
+		service.ID = did + "#service-" + strconv.Itoa(index)
+	}
+	return service, nil
+}
+
+// expandPeerService replaces the abbreviated keys and values of v
+func expandPeerService(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(t))
+		for k, value := range t {
+			if full, ok := peerServiceAbbreviations[k]; ok {
+				k = full
+			}
+			ret[k] = expandPeerService(value)
+		}
+		return ret
+	case []interface{}:
+		ret := make([]interface{}, len(t))
+		for i := range t {
+			ret[i] = expandPeerService(t[i])
+		}
+		return ret
+	case string:
+		if t == "dm" {
+			return "DIDCommMessaging"
+		}
+	}
+	return v
+}
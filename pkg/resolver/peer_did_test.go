@@ -0,0 +1,67 @@
+package resolver
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerDIDResolver(t *testing.T) {
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	mk, err := MultikeyOf(edPub)
+	require.NoError(t, err)
+	res := NewPeerDIDResolver()
+
+	// numalgo 0
+	did0 := "did:peer:0" + mk
+	doc, err := res.ResolveDocument(did0)
+	require.NoError(t, err)
+	assert.Equal(t, did0, doc.ID)
+	assert.Equal(t, did0+"#"+mk, doc.AssertionMethod[0].ID)
+	pub, err := res.Resolve(did0 + "#" + mk)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(edPub), pub.Value)
+
+	// numalgo 2
+	x25519 := make([]byte, 32)
+	_, err = rand.Read(x25519)
+	assert.NoError(t, err)
+	service := base64.RawURLEncoding.EncodeToString([]byte(
+		`{"t":"dm","s":{"uri":"https://example.com/didcomm","a":["didcomm/v2"],"r":["did:example:123#key-1"]}}`))
+	did2 := "did:peer:2.Ez" + strings.TrimPrefix(EncodeMultikey(CodecX25519Pub, x25519), "z") + ".V" + mk + ".A" + mk + ".S" + service
+	doc, err = res.ResolveDocument(did2)
+	require.NoError(t, err)
+	assert.Len(t, doc.VerificationMethod, 3)
+	assert.Equal(t, did2+"#key-1", doc.KeyAgreement[0].ID)
+	assert.Equal(t, did2+"#key-2", doc.Authentication[0].ID)
+	assert.Equal(t, did2+"#key-3", doc.AssertionMethod[0].ID)
+	assert.Equal(t, did2+"#service", doc.Service[0].ID)
+	assert.Equal(t, "DIDCommMessaging", doc.Service[0].Type)
+	assert.Equal(t, map[string]interface{}{
+		"uri":         "https://example.com/didcomm",
+		"accept":      []interface{}{"didcomm/v2"},
+		"routingKeys": []interface{}{"did:example:123#key-1"},
+	}, doc.Service[0].ServiceEndpoint)
+
+	pub, err = res.Resolve(did2 + "#key-3")
+	require.NoError(t, err)
+	assert.Equal(t, []byte(edPub), pub.Value)
+	assert.Equal(t, "OKP", pub.Jwk.Kty)
+	pub, err = res.Resolve(did2 + "#key-1")
+	require.NoError(t, err)
+	assert.Equal(t, x25519, pub.Value)
+	// several keys, the fragment is required
+	_, err = res.Resolve(did2)
+	assert.ErrorContains(t, err, "not found")
+
+	for _, did := range []string{"did:peer:1zQmZ", "did:peer:2.Xz6Mk", "did:peer:2.Vz6Mk", "did:peer:2.S!!", "did:key:" + mk} {
+		_, err = res.ResolveDocument(did)
+		assert.Error(t, err, did)
+	}
+}
@@ -0,0 +1,196 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DID resolution errors, their messages are the error codes of DID resolution metadata
+// https://www.w3.org/TR/did-core/#did-resolution-metadata
+var (
+	ErrInvalidDID         = errors.New("invalidDid")
+	ErrNotFound           = errors.New("notFound")
+	ErrMethodNotSupported = errors.New("methodNotSupported")
+	ErrDeactivated        = errors.New("deactivated")
+)
+
+// DIDResolver resolves DIDs to their DID document and metadata
+type DIDResolver interface {
+	ResolveDID(did string) (*ResolutionResult, error)
+}
+
+// ContextDIDResolver is a DIDResolver honouring the cancellation and deadline of ctx
+type ContextDIDResolver interface {
+	DIDResolver
+	ResolveDIDContext(ctx context.Context, did string) (*ResolutionResult, error)
+}
+
+// ContextPublicKeyResolver is a PublicKeyResolver honouring the cancellation and deadline of ctx
+type ContextPublicKeyResolver interface {
+	PublicKeyResolver
+	ResolveContext(ctx context.Context, id string) (*PublicKey, error)
+}
+
+// ResolutionResult is the result of DID resolution
+// https://w3c-ccg.github.io/did-resolution/#did-resolution-result
+type ResolutionResult struct {
+	Context            interface{}         `json:"@context,omitempty"`
+	Document           *Document           `json:"didDocument"`
+	ResolutionMetadata *ResolutionMetadata `json:"didResolutionMetadata"`
+	DocumentMetadata   *DocumentMetadata   `json:"didDocumentMetadata"`
+}
+
+// ResolutionMetadata is the metadata of the resolution process
+type ResolutionMetadata struct {
+	ContentType string `json:"contentType,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// DocumentMetadata is the metadata of a DID document
+// https://www.w3.org/TR/did-core/#did-document-metadata
+type DocumentMetadata struct {
+	Created       string   `json:"created,omitempty"`
+	Updated       string   `json:"updated,omitempty"`
+	Deactivated   bool     `json:"deactivated,omitempty"`
+	NextUpdate    string   `json:"nextUpdate,omitempty"`
+	VersionID     string   `json:"versionId,omitempty"`
+	NextVersionID string   `json:"nextVersionId,omitempty"`
+	EquivalentID  []string `json:"equivalentId,omitempty"`
+	CanonicalID   string   `json:"canonicalId,omitempty"`
+}
+
+// documentResult returns the result of a resolved document without metadata
+func documentResult(doc *Document) *ResolutionResult {
+	return &ResolutionResult{
+		Document:           doc,
+		ResolutionMetadata: &ResolutionMetadata{ContentType: "application/did+ld+json"},
+		DocumentMetadata:   &DocumentMetadata{},
+	}
+}
+
+// KeyResolverOf returns a PublicKeyResolver resolving verification methods with r
+func KeyResolverOf(r DIDResolver) PublicKeyResolver {
+	return &didKeyResolver{r}
+}
+
+type didKeyResolver struct {
+	DIDResolver
+}
+
+func (res *didKeyResolver) Resolve(id string) (*PublicKey, error) {
+	return resolvePublicKey(context.Background(), res.DIDResolver, id)
+}
+
+func (res *didKeyResolver) ResolveContext(ctx context.Context, id string) (*PublicKey, error) {
+	return resolvePublicKey(ctx, res.DIDResolver, id)
+}
+
+// ResolveDIDContext resolves did with r within ctx, resolvers which are not a ContextDIDResolver are
+// abandoned when ctx is done
+func ResolveDIDContext(ctx context.Context, r DIDResolver, did string) (*ResolutionResult, error) {
+	if cr, ok := r.(ContextDIDResolver); ok {
+		return cr.ResolveDIDContext(ctx, did)
+	}
+	var result *ResolutionResult
+	err := runContext(ctx, func() (err error) {
+		result, err = r.ResolveDID(did)
+		return err
+	})
+	return result, err
+}
+
+// ResolvePublicKeyContext resolves verification method id with r within ctx, resolvers which are not a
+// ContextPublicKeyResolver are abandoned when ctx is done
+func ResolvePublicKeyContext(ctx context.Context, r PublicKeyResolver, id string) (*PublicKey, error) {
+	if cr, ok := r.(ContextPublicKeyResolver); ok {
+		return cr.ResolveContext(ctx, id)
+	}
+	var pub *PublicKey
+	err := runContext(ctx, func() (err error) {
+		pub, err = r.Resolve(id)
+		return err
+	})
+	return pub, err
+}
+
+// runContext runs f until it returns or ctx is done
+func runContext(ctx context.Context, f func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- f()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// resolvePublicKey returns the public key of verification method id of a DID resolved by r
+func resolvePublicKey(ctx context.Context, r DIDResolver, id string) (*PublicKey, error) {
+	did, _, _ := strings.Cut(id, "#")
+	result, err := ResolveDIDContext(ctx, r, did)
+	if err != nil {
+		return nil, err
+	}
+	return resultPublicKey(result, id)
+}
+
+// resultPublicKey returns the public key of verification method id of a resolution result, the fragment
+// may be omitted when the document has a single verification method
+func resultPublicKey(result *ResolutionResult, id string) (*PublicKey, error) {
+	doc, err := resultDocument(result, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, fragment, _ := strings.Cut(id, "#"); fragment == "" && len(doc.VerificationMethod) == 1 {
+		return doc.VerificationMethod[0].PublicKey()
+	}
+	return doc.PublicKey(id)
+}
+
+// resultDocument returns the DID document of a resolution result of the DID of id, unless deactivated
+func resultDocument(result *ResolutionResult, id string) (*Document, error) {
+	did, _, _ := strings.Cut(id, "#")
+	if result.DocumentMetadata != nil && result.DocumentMetadata.Deactivated {
+		return nil, fmt.Errorf("%w: %s", ErrDeactivated, did)
+	}
+	if result.Document == nil {
+		return nil, fmt.Errorf("%w: %s has no DID document", ErrNotFound, did)
+	}
+	return result.Document, nil
+}
+
+// RelationshipCheckerOf returns a check, resolving DIDs with r, that verification method vm is authorised
+// for verification relationship purpose by its DID document, e.g. a proof purpose of assertionMethod.
+// The fragment of vm may be omitted when the document has a single verification method.
+func RelationshipCheckerOf(r DIDResolver) func(vm, purpose string) error {
+	return func(vm, purpose string) error {
+		return CheckRelationshipContext(context.Background(), r, vm, purpose)
+	}
+}
+
+// CheckRelationshipContext checks within ctx that verification method vm is authorised for verification
+// relationship purpose by the DID document resolved with r
+func CheckRelationshipContext(ctx context.Context, r DIDResolver, vm, purpose string) error {
+	did, fragment, _ := strings.Cut(vm, "#")
+	result, err := ResolveDIDContext(ctx, r, did)
+	if err != nil {
+		return err
+	}
+	doc, err := resultDocument(result, vm)
+	if err != nil {
+		return err
+	}
+	if fragment == "" && len(doc.VerificationMethod) == 1 {
+		vm = doc.VerificationMethod[0].ID
+	}
+	_, err = doc.VerificationMethodFor(vm, purpose)
+	return err
+}
@@ -0,0 +1,247 @@
+package resolver
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RoutingResolver resolves DIDs with the resolver registered for their method. Resolution results are
+// cached, concurrent resolutions of the same DID share one lookup. It is safe for concurrent use.
+type RoutingResolver struct {
+	mu       sync.Mutex
+	methods  map[string]DIDResolver
+	cache    map[string]*list.Element
+	lru      *list.List
+	inflight map[string]*resolveCall
+	options  *routerOpts
+}
+
+type cachedResult struct {
+	did     string
+	result  *ResolutionResult
+	expires time.Time
+}
+
+// resolveCall is a lookup shared by concurrent resolutions of a DID
+type resolveCall struct {
+	done   chan struct{}
+	result *ResolutionResult
+	err    error
+}
+
+func NewRoutingResolver(opts ...RouterOption) *RoutingResolver {
+	options := prepareRouterOpts(opts)
+	methods := make(map[string]DIDResolver, len(options.methods))
+	for method, r := range options.methods {
+		methods[method] = r
+	}
+	return &RoutingResolver{
+		methods:  methods,
+		cache:    make(map[string]*list.Element),
+		lru:      list.New(),
+		inflight: make(map[string]*resolveCall),
+		options:  options,
+	}
+}
+
+// Register routes DIDs of method, e.g. "web" for did:web, to r
+func (res *RoutingResolver) Register(method string, r DIDResolver) {
+	res.mu.Lock()
+	defer res.mu.Unlock()
+	res.methods[method] = r
+}
+
+// ResolveDID resolves did with the resolver of its method
+func (res *RoutingResolver) ResolveDID(did string) (*ResolutionResult, error) {
+	return res.ResolveDIDContext(context.Background(), did)
+}
+
+// ResolveDIDContext resolves did with the resolver of its method within ctx. Cached results are shared
+// and must not be modified. A lookup abandoned by ctx keeps running for other callers and the cache,
+// bounded by the resolver timeout.
+func (res *RoutingResolver) ResolveDIDContext(ctx context.Context, did string) (*ResolutionResult, error) {
+	method, err := didMethod(did)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	res.mu.Lock()
+	if result, ok := res.cached(did); ok {
+		res.mu.Unlock()
+		return result, nil
+	}
+	call, ok := res.inflight[did]
+	if !ok {
+		r, found := res.methods[method]
+		if !found {
+			r = res.options.fallback
+		}
+		if r == nil {
+			res.mu.Unlock()
+			return nil, fmt.Errorf("%w: did:%s", ErrMethodNotSupported, method)
+		}
+		call = &resolveCall{done: make(chan struct{})}
+		res.inflight[did] = call
+		go res.lookup(r, did, call)
+	}
+	res.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.result, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Resolve returns the public key of verification method id
+func (res *RoutingResolver) Resolve(id string) (*PublicKey, error) {
+	return resolvePublicKey(context.Background(), res, id)
+}
+
+// ResolveContext returns the public key of verification method id within ctx
+func (res *RoutingResolver) ResolveContext(ctx context.Context, id string) (*PublicKey, error) {
+	return resolvePublicKey(ctx, res, id)
+}
+
+// lookup resolves did with r and caches its result
+func (res *RoutingResolver) lookup(r DIDResolver, did string, call *resolveCall) {
+	ctx := context.Background()
+	if res.options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, res.options.timeout)
+		defer cancel()
+	}
+	call.result, call.err = ResolveDIDContext(ctx, r, did)
+	if call.err == nil && call.result == nil {
+		call.err = fmt.Errorf("%w: %s", ErrNotFound, did)
+	}
+
+	res.mu.Lock()
+	delete(res.inflight, did)
+	if call.err == nil {
+		res.store(did, call.result)
+	}
+	res.mu.Unlock()
+	close(call.done)
+}
+
+// cached returns the unexpired cached result of did, res.mu must be held
+func (res *RoutingResolver) cached(did string) (*ResolutionResult, bool) {
+	elem, ok := res.cache[did]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cachedResult)
+	if !res.options.clock().Before(entry.expires) {
+		res.lru.Remove(elem)
+		delete(res.cache, did)
+		return nil, false
+	}
+	res.lru.MoveToFront(elem)
+	return entry.result, true
+}
+
+// store caches the result of did, evicting the least recently used results, res.mu must be held
+func (res *RoutingResolver) store(did string, result *ResolutionResult) {
+	if res.options.cacheTTL <= 0 || res.options.cacheSize <= 0 {
+		return
+	}
+	entry := &cachedResult{did: did, result: result, expires: res.options.clock().Add(res.options.cacheTTL)}
+	if elem, ok := res.cache[did]; ok {
+		elem.Value = entry
+		res.lru.MoveToFront(elem)
+		return
+	}
+	res.cache[did] = res.lru.PushFront(entry)
+	for res.lru.Len() > res.options.cacheSize {
+		oldest := res.lru.Back()
+		res.lru.Remove(oldest)
+		delete(res.cache, oldest.Value.(*cachedResult).did)
+	}
+}
+
+// didMethod returns the method name of did
+func didMethod(did string) (string, error) {
+	parts := strings.SplitN(did, ":", 3)
+	if len(parts) != 3 || parts[0] != "did" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("%w: %s", ErrInvalidDID, did)
+	}
+	return parts[1], nil
+}
+
+// routerOpts holds options for RoutingResolver.
+type routerOpts struct {
+	methods   map[string]DIDResolver
+	fallback  DIDResolver
+	cacheTTL  time.Duration
+	cacheSize int
+	timeout   time.Duration
+	clock     func() time.Time
+}
+
+// RouterOption are the options for RoutingResolver.
+type RouterOption func(opts *routerOpts)
+
+// WithMethod option routes DIDs of method, e.g. "key" for did:key, to r.
+func WithMethod(method string, r DIDResolver) RouterOption {
+	return func(opts *routerOpts) {
+		opts.methods[method] = r
+	}
+}
+
+// WithFallback option resolves DIDs of unregistered methods with r, e.g. a UniversalResolver.
+func WithFallback(r DIDResolver) RouterOption {
+	return func(opts *routerOpts) {
+		opts.fallback = r
+	}
+}
+
+// WithCacheTTL option sets how long results are cached, 5 minutes by default, 0 disables the cache.
+func WithCacheTTL(ttl time.Duration) RouterOption {
+	return func(opts *routerOpts) {
+		opts.cacheTTL = ttl
+	}
+}
+
+// WithCacheSize option sets the maximum number of cached results, 1024 by default.
+func WithCacheSize(size int) RouterOption {
+	return func(opts *routerOpts) {
+		opts.cacheSize = size
+	}
+}
+
+// WithTimeout option bounds each lookup, 30 seconds by default, 0 for no limit.
+func WithTimeout(timeout time.Duration) RouterOption {
+	return func(opts *routerOpts) {
+		opts.timeout = timeout
+	}
+}
+
+// WithClock option sets the current time of cache expiry checks.
+func WithClock(clock func() time.Time) RouterOption {
+	return func(opts *routerOpts) {
+		opts.clock = clock
+	}
+}
+
+func prepareRouterOpts(opts []RouterOption) *routerOpts {
+	ret := &routerOpts{
+		methods:   make(map[string]DIDResolver),
+		cacheTTL:  5 * time.Minute,
+		cacheSize: 1024,
+		timeout:   30 * time.Second,
+		clock:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
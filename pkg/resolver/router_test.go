@@ -0,0 +1,124 @@
+package resolver
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingResolver counts resolutions and blocks them until release is closed
+type countingResolver struct {
+	calls   int32
+	release chan struct{}
+}
+
+func (r *countingResolver) ResolveDID(did string) (*ResolutionResult, error) {
+	atomic.AddInt32(&r.calls, 1)
+	if r.release != nil {
+		<-r.release
+	}
+	return documentResult(&Document{ID: did}), nil
+}
+
+func TestRoutingResolver(t *testing.T) {
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keyDID, err := NewKeyDID(edPub)
+	require.NoError(t, err)
+
+	now := time.Now()
+	clock := func() time.Time { return now }
+	example := &countingResolver{}
+	res := NewRoutingResolver(
+		WithMethod("key", NewKeyDIDResolver()),
+		WithMethod("example", example),
+		WithCacheTTL(time.Minute),
+		WithCacheSize(2),
+		WithClock(clock),
+	)
+
+	pub, err := res.Resolve(keyDID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(edPub), pub.Value)
+	_, err = res.ResolveDID("did:web:example.com")
+	assert.ErrorIs(t, err, ErrMethodNotSupported)
+	_, err = res.ResolveDID("example.com")
+	assert.ErrorIs(t, err, ErrInvalidDID)
+	res.Register("web", &countingResolver{})
+	_, err = res.ResolveDID("did:web:example.com")
+	assert.NoError(t, err)
+
+	// results are cached until their ttl
+	for i := 0; i < 3; i++ {
+		result, err := res.ResolveDID("did:example:1")
+		require.NoError(t, err)
+		assert.Equal(t, "did:example:1", result.Document.ID)
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt32(&example.calls))
+	now = now.Add(time.Minute)
+	_, err = res.ResolveDID("did:example:1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&example.calls))
+
+	// the least recently used result is evicted
+	_, err = res.ResolveDID("did:example:2")
+	assert.NoError(t, err)
+	_, err = res.ResolveDID("did:example:1")
+	assert.NoError(t, err)
+	_, err = res.ResolveDID("did:example:3")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, atomic.LoadInt32(&example.calls))
+	_, err = res.ResolveDID("did:example:1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, atomic.LoadInt32(&example.calls))
+	_, err = res.ResolveDID("did:example:2")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, atomic.LoadInt32(&example.calls))
+}
+
+func TestRoutingResolverConcurrency(t *testing.T) {
+	example := &countingResolver{release: make(chan struct{})}
+	res := NewRoutingResolver(WithMethod("example", example))
+
+	// a lookup is abandoned at the deadline of the caller
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := res.ResolveDIDContext(ctx, "did:example:slow")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// concurrent resolutions share the pending lookup
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = res.ResolveDID("did:example:slow")
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(example.release)
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt32(&example.calls))
+
+	// lookups are bounded by the resolver timeout
+	blocked := &countingResolver{release: make(chan struct{})}
+	defer close(blocked.release)
+	res = NewRoutingResolver(WithFallback(blocked), WithTimeout(20*time.Millisecond))
+	_, err = res.ResolveDID("did:other:1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, err = ResolvePublicKeyContext(context.Background(), res, "did:other:1#key-1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	// failed lookups are not cached
+	assert.EqualValues(t, 2, atomic.LoadInt32(&blocked.calls))
+}
@@ -0,0 +1,151 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// resolutionResultType is the media type of DID resolution results
+const resolutionResultType = `application/ld+json;profile="https://w3id.org/did-resolution"`
+
+// UniversalResolver resolves DIDs of any method with a DIF Universal Resolver instance
+// https://github.com/decentralized-identity/universal-resolver
+type UniversalResolver struct {
+	base   string
+	client *http.Client
+}
+
+// NewUniversalResolver creates a resolver of the Universal Resolver at baseURL using client,
+// http.DefaultClient if nil
+func NewUniversalResolver(baseURL string, client *http.Client) *UniversalResolver {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &UniversalResolver{
+		base:   strings.TrimRight(baseURL, "/"),
+		client: client,
+	}
+}
+
+// ResolveDID resolves did with GET /1.0/identifiers/{did}. Errors of the resolution metadata are
+// returned as ErrInvalidDID, ErrNotFound or ErrMethodNotSupported, a deactivated DID is not an error.
+func (res *UniversalResolver) ResolveDID(did string) (*ResolutionResult, error) {
+	return res.ResolveDIDContext(context.Background(), did)
+}
+
+// ResolveDIDContext resolves did within ctx
+func (res *UniversalResolver) ResolveDIDContext(ctx context.Context, did string) (*ResolutionResult, error) {
+	if !strings.HasPrefix(did, "did:") || strings.ContainsAny(did, "#?") {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDID, did)
+	}
+	u := res.base + "/1.0/identifiers/" + url.PathEscape(did)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", resolutionResultType)
+	resp, err := res.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve %s: %w", did, err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentBytes))
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve %s: %w", did, err)
+	}
+	result, parseErr := parseResolutionResult(b)
+
+	var code string
+	if result != nil {
+		code = result.ResolutionMetadata.Error
+	}
+	if code == "" {
+		switch resp.StatusCode {
+		case http.StatusOK, http.StatusGone:
+		case http.StatusBadRequest:
+			code = ErrInvalidDID.Error()
+		case http.StatusNotFound:
+			code = ErrNotFound.Error()
+		case http.StatusNotImplemented:
+			code = ErrMethodNotSupported.Error()
+		default:
+			return nil, fmt.Errorf("cannot resolve %s: %s", did, resp.Status)
+		}
+	}
+	deactivated := resp.StatusCode == http.StatusGone || code == ErrDeactivated.Error()
+	if code != "" && !deactivated {
+		return nil, fmt.Errorf("%w: %s", resolutionError(code), did)
+	}
+	if deactivated && parseErr != nil {
+		result, parseErr = &ResolutionResult{
+			ResolutionMetadata: &ResolutionMetadata{},
+			DocumentMetadata:   &DocumentMetadata{},
+		}, nil
+	}
+	if parseErr != nil {
+		return nil, fmt.Errorf("cannot resolve %s: %w", did, parseErr)
+	}
+	if deactivated || result.DocumentMetadata.Deactivated {
+		result.DocumentMetadata.Deactivated = true
+		return result, nil
+	}
+	if result.Document == nil || result.Document.ID != did {
+		return nil, fmt.Errorf("%w: DID document of %s has another id", ErrInvalidDID, did)
+	}
+	return result, nil
+}
+
+// Resolve returns the public key of verification method id
+func (res *UniversalResolver) Resolve(id string) (*PublicKey, error) {
+	return resolvePublicKey(context.Background(), res, id)
+}
+
+// ResolveContext returns the public key of verification method id within ctx
+func (res *UniversalResolver) ResolveContext(ctx context.Context, id string) (*PublicKey, error) {
+	return resolvePublicKey(ctx, res, id)
+}
+
+// parseResolutionResult parses a DID resolution result, or a bare DID document returned by some drivers
+func parseResolutionResult(b []byte) (*ResolutionResult, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, fmt.Errorf("invalid DID resolution result: %w", err)
+	}
+	result := &ResolutionResult{}
+	_, hasDocument := fields["didDocument"]
+	_, hasMetadata := fields["didResolutionMetadata"]
+	if hasDocument || hasMetadata {
+		if err := json.Unmarshal(b, result); err != nil {
+			return nil, fmt.Errorf("invalid DID resolution result: %w", err)
+		}
+	} else {
+		doc, err := ParseDocument(b)
+		if err != nil {
+			return nil, err
+		}
+		result.Document = doc
+	}
+	if result.ResolutionMetadata == nil {
+		result.ResolutionMetadata = &ResolutionMetadata{}
+	}
+	if result.DocumentMetadata == nil {
+		result.DocumentMetadata = &DocumentMetadata{}
+	}
+	return result, nil
+}
+
+// resolutionError returns the error of a DID resolution metadata error code
+func resolutionError(code string) error {
+	for _, err := range []error{ErrInvalidDID, ErrNotFound, ErrMethodNotSupported, ErrDeactivated} {
+		if err.Error() == code {
+			return err
+		}
+	}
+	return errors.New(code)
+}
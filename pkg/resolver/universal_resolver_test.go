@@ -0,0 +1,120 @@
+package resolver
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniversalResolver(t *testing.T) {
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	mk, err := MultikeyOf(edPub)
+	require.NoError(t, err)
+	did := "did:example:123"
+	doc := &Document{
+		ID: did,
+		VerificationMethod: []*VerificationMethod{
+			{ID: did + "#key-1", Type: "Ed25519VerificationKey2020", Controller: did, PublicKeyMultibase: mk},
+		},
+	}
+	results := map[string]interface{}{
+		did: &ResolutionResult{
+			Context:            "https://w3id.org/did-resolution/v1",
+			Document:           doc,
+			ResolutionMetadata: &ResolutionMetadata{ContentType: "application/did+ld+json"},
+			DocumentMetadata:   &DocumentMetadata{Created: "2023-01-01T00:00:00Z"},
+		},
+		"did:example:bare": &Document{ID: "did:example:bare"},
+		"did:example:deactivated": &ResolutionResult{
+			Document:         &Document{ID: "did:example:deactivated"},
+			DocumentMetadata: &DocumentMetadata{Deactivated: true},
+		},
+		"did:example:other": &Document{ID: "did:example:123"},
+		"did:example:bad": &ResolutionResult{
+			ResolutionMetadata: &ResolutionMetadata{Error: "invalidDid"},
+		},
+	}
+	var accept string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		id := strings.TrimPrefix(r.URL.Path, "/1.0/identifiers/")
+		switch id {
+		case "did:example:gone":
+			w.WriteHeader(http.StatusGone)
+			return
+		case "did:unknown:123":
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		result, ok := results[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(result)
+	}))
+	defer ts.Close()
+
+	res := NewUniversalResolver(ts.URL+"/", ts.Client())
+	result, err := res.ResolveDID(did)
+	require.NoError(t, err)
+	assert.Contains(t, accept, "https://w3id.org/did-resolution")
+	assert.Equal(t, did+"#key-1", result.Document.VerificationMethod[0].ID)
+	assert.Equal(t, "2023-01-01T00:00:00Z", result.DocumentMetadata.Created)
+	assert.Equal(t, "application/did+ld+json", result.ResolutionMetadata.ContentType)
+	pub, err := res.Resolve(did + "#key-1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte(edPub), pub.Value)
+	pub, err = KeyResolverOf(res).Resolve(did)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(edPub), pub.Value)
+
+	result, err = res.ResolveDID("did:example:bare")
+	require.NoError(t, err)
+	assert.Equal(t, "did:example:bare", result.Document.ID)
+
+	// deactivated DIDs are resolved but their keys are not
+	for _, id := range []string{"did:example:deactivated", "did:example:gone"} {
+		result, err = res.ResolveDID(id)
+		require.NoError(t, err, id)
+		assert.True(t, result.DocumentMetadata.Deactivated, id)
+		_, err = res.Resolve(id + "#key-1")
+		assert.ErrorIs(t, err, ErrDeactivated, id)
+	}
+
+	_, err = res.ResolveDID("did:example:missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = res.ResolveDID("did:example:bad")
+	assert.ErrorIs(t, err, ErrInvalidDID)
+	_, err = res.ResolveDID("did:example:other")
+	assert.ErrorIs(t, err, ErrInvalidDID)
+	_, err = res.ResolveDID("did:unknown:123")
+	assert.ErrorIs(t, err, ErrMethodNotSupported)
+	_, err = res.ResolveDID("not-a-did")
+	assert.ErrorIs(t, err, ErrInvalidDID)
+}
+
+func TestOfflineResolversResolveDID(t *testing.T) {
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	did, err := NewKeyDID(edPub)
+	require.NoError(t, err)
+	for _, r := range []DIDResolver{NewKeyDIDResolver(), NewJWKDIDResolver(), NewPeerDIDResolver(), NewWebDIDResolver(nil)} {
+		_, err := r.ResolveDID("did:other:123")
+		assert.ErrorIs(t, err, ErrMethodNotSupported)
+	}
+	result, err := NewKeyDIDResolver().ResolveDID(did)
+	require.NoError(t, err)
+	assert.Equal(t, did, result.Document.ID)
+	assert.False(t, result.DocumentMetadata.Deactivated)
+	_, err = NewKeyDIDResolver().ResolveDID("did:key:zinvalid")
+	assert.ErrorIs(t, err, ErrInvalidDID)
+}
@@ -0,0 +1,120 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	webDIDPrefix = "did:web:"
+	// maxDocumentBytes limits the size of a fetched DID document
+	maxDocumentBytes = 1 << 20
+)
+
+// WebDIDResolver resolves did:web DIDs by fetching their DID document over HTTPS
+// https://w3c-ccg.github.io/did-method-web/
+type WebDIDResolver struct {
+	client *http.Client
+}
+
+// NewWebDIDResolver creates a did:web resolver using client, http.DefaultClient if nil
+func NewWebDIDResolver(client *http.Client) *WebDIDResolver {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebDIDResolver{client: client}
+}
+
+// WebDIDURL returns the URL of the DID document of a did:web DID
+func WebDIDURL(did string) (string, error) {
+	if !strings.HasPrefix(did, webDIDPrefix) {
+		return "", fmt.Errorf("%w: %s is not a did:web DID", ErrMethodNotSupported, did)
+	}
+	segments := strings.Split(strings.TrimPrefix(did, webDIDPrefix), ":")
+	for i, seg := range segments {
+		s, err := url.PathUnescape(seg)
+		if err != nil || s == "" {
+			return "", fmt.Errorf("%w: invalid did:web %s", ErrInvalidDID, did)
+		}
+		segments[i] = s
+	}
+	host := segments[0]
+	if strings.ContainsAny(host, "/?#@") {
+		return "", fmt.Errorf("%w: invalid did:web host %s", ErrInvalidDID, host)
+	}
+	path := "/.well-known"
+	if len(segments) > 1 {
+		path = "/" + strings.Join(segments[1:], "/")
+	}
+	u := &url.URL{Scheme: "https", Host: host, Path: path + "/did.json"}
+	return u.String(), nil
+}
+
+// ResolveDocument fetches the DID document of a did:web DID, its id must be the DID
+func (res *WebDIDResolver) ResolveDocument(did string) (*Document, error) {
+	return res.ResolveDocumentContext(context.Background(), did)
+}
+
+// ResolveDocumentContext fetches the DID document of a did:web DID within ctx
+func (res *WebDIDResolver) ResolveDocumentContext(ctx context.Context, did string) (*Document, error) {
+	u, err := WebDIDURL(did)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/did+json, application/json")
+	resp, err := res.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch DID document %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, fmt.Errorf("%w: DID document %s: %s", ErrNotFound, u, resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot fetch DID document %s: %s", u, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentBytes))
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch DID document %s: %w", u, err)
+	}
+	doc, err := ParseDocument(b)
+	if err != nil {
+		return nil, err
+	}
+	if doc.ID != did {
+		return nil, fmt.Errorf("%w: DID document id %s does not match %s", ErrInvalidDID, doc.ID, did)
+	}
+	return doc, nil
+}
+
+// ResolveDID returns the resolution result of a did:web DID
+func (res *WebDIDResolver) ResolveDID(did string) (*ResolutionResult, error) {
+	return res.ResolveDIDContext(context.Background(), did)
+}
+
+// ResolveDIDContext returns the resolution result of a did:web DID within ctx
+func (res *WebDIDResolver) ResolveDIDContext(ctx context.Context, did string) (*ResolutionResult, error) {
+	doc, err := res.ResolveDocumentContext(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+	return documentResult(doc), nil
+}
+
+// Resolve returns the public key of verification method id
+func (res *WebDIDResolver) Resolve(id string) (*PublicKey, error) {
+	return resolvePublicKey(context.Background(), res, id)
+}
+
+// ResolveContext returns the public key of verification method id within ctx
+func (res *WebDIDResolver) ResolveContext(ctx context.Context, id string) (*PublicKey, error) {
+	return resolvePublicKey(ctx, res, id)
+}
@@ -0,0 +1,98 @@
+package resolver
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suutaku/go-vc/pkg/jose"
+)
+
+func TestWebDIDURL(t *testing.T) {
+	for did, u := range map[string]string{
+		"did:web:w3c-ccg.github.io":                     "https://w3c-ccg.github.io/.well-known/did.json",
+		"did:web:w3c-ccg.github.io:user:alice":          "https://w3c-ccg.github.io/user/alice/did.json",
+		"did:web:example.com%3A3000:user:alice":         "https://example.com:3000/user/alice/did.json",
+		"did:web:example.com%3A3000":                    "https://example.com:3000/.well-known/did.json",
+		"did:web:example.com:path%20with%20space:alice": "https://example.com/path%20with%20space/alice/did.json",
+	} {
+		got, err := WebDIDURL(did)
+		require.NoError(t, err, did)
+		assert.Equal(t, u, got, did)
+	}
+	for _, did := range []string{"did:key:z6Mk", "did:web:", "did:web:example.com::alice", "did:web:user%40example.com"} {
+		_, err := WebDIDURL(did)
+		assert.Error(t, err, did)
+	}
+}
+
+func TestWebDIDResolver(t *testing.T) {
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwk, err := jose.NewJWK(&p256.PublicKey)
+	require.NoError(t, err)
+
+	docs := map[string]*Document{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		doc, ok := docs[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(doc)
+	}))
+	defer ts.Close()
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	did := "did:web:" + strings.ReplaceAll(u.Host, ":", "%3A")
+	aliceDID := did + ":users:alice"
+	mk, err := MultikeyOf(edPub)
+	require.NoError(t, err)
+	docs["/.well-known/did.json"] = &Document{
+		Context: []interface{}{DIDContext},
+		ID:      did,
+		VerificationMethod: []*VerificationMethod{
+			{ID: did + "#key-1", Type: "Ed25519VerificationKey2020", Controller: did, PublicKeyMultibase: mk},
+			{ID: did + "#key-2", Type: "JsonWebKey2020", Controller: did, PublicKeyJwk: jwk},
+		},
+		AssertionMethod: []*VerificationRef{{ID: did + "#key-1"}, {ID: did + "#key-2"}},
+	}
+	docs["/users/alice/did.json"] = &Document{ID: did}
+
+	res := NewWebDIDResolver(ts.Client())
+	assert.True(t, strings.HasPrefix(did, "did:web:127.0.0.1%3A"))
+	doc, err := res.ResolveDocument(did)
+	require.NoError(t, err)
+	assert.Len(t, doc.VerificationMethod, 2)
+
+	pub, err := res.Resolve(did + "#key-1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte(edPub), pub.Value)
+	assert.Equal(t, "Ed25519VerificationKey2020", pub.Type)
+	pub, err = res.Resolve(did + "#key-2")
+	require.NoError(t, err)
+	assert.True(t, jwk.Equal(pub.Jwk))
+	assert.Equal(t, elliptic.MarshalCompressed(elliptic.P256(), p256.X, p256.Y), pub.Value)
+
+	_, err = res.Resolve(did + "#key-3")
+	assert.ErrorContains(t, err, "not found")
+	// the document of alice has another id
+	_, err = res.Resolve(aliceDID + "#key-1")
+	assert.ErrorContains(t, err, "does not match")
+	_, err = res.Resolve(did + ":users:bob#key-1")
+	assert.ErrorContains(t, err, "404")
+	// the test server certificate is not trusted by default
+	_, err = NewWebDIDResolver(nil).Resolve(did + "#key-1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package schema
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// maxSchemaBytes limits the size of a fetched schema document
+const maxSchemaBytes = 4 << 20
+
+// Loader loads the documents of credentialSchema ids: JSON schemas, JsonSchemaCredentials and
+// the schemas they reference
+type Loader interface {
+	Load(url string) ([]byte, error)
+}
+
+// HTTPLoader fetches documents over HTTP(S)
+type HTTPLoader struct {
+	client *http.Client
+}
+
+// NewHTTPLoader creates a loader using client, http.DefaultClient if nil
+func NewHTTPLoader(client *http.Client) *HTTPLoader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPLoader{client: client}
+}
+
+func (l *HTTPLoader) Load(url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema url %s: %w", url, err)
+	}
+	req.Header.Set("Accept", "application/schema+json, application/vc+ld+json, application/json")
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch schema %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot fetch schema %s: %s", url, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxSchemaBytes))
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch schema %s: %w", url, err)
+	}
+	return b, nil
+}
+
+// MapLoader serves documents from memory, useful offline and in tests
+type MapLoader map[string][]byte
+
+func (l MapLoader) Load(url string) ([]byte, error) {
+	b, ok := l[url]
+	if !ok {
+		return nil, fmt.Errorf("schema %s not found", url)
+	}
+	return b, nil
+}
+
+// CachingLoader caches the most recently used documents returned by another loader. It is safe for
+// concurrent use.
+type CachingLoader struct {
+	mu     sync.Mutex
+	loader Loader
+	cache  *lruCache
+}
+
+// NewCachingLoader creates a loader caching up to size documents of loader
+func NewCachingLoader(loader Loader, size int) *CachingLoader {
+	return &CachingLoader{
+		loader: loader,
+		cache:  newLRUCache(size),
+	}
+}
+
+func (l *CachingLoader) Load(url string) ([]byte, error) {
+	l.mu.Lock()
+	b, ok := l.cache.get(url)
+	l.mu.Unlock()
+	if ok {
+		return b.([]byte), nil
+	}
+	doc, err := l.loader.Load(url)
+	if err != nil {
+		return nil, err
+	}
+	l.mu.Lock()
+	l.cache.add(url, doc)
+	l.mu.Unlock()
+	return doc, nil
+}
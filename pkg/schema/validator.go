@@ -0,0 +1,252 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/suutaku/go-vc/pkg/credential"
+)
+
+// credentialSchema types
+// https://www.w3.org/TR/vc-json-schema/
+const (
+	JsonSchema           = "JsonSchema"
+	JsonSchemaCredential = "JsonSchemaCredential"
+)
+
+// supported JSON schema drafts, 2020-12 is assumed when $schema is missing
+var drafts = map[string]bool{
+	"https://json-schema.org/draft/2020-12/schema": true,
+	"https://json-schema.org/draft-07/schema":      true,
+}
+
+// VerifyFunc verifies the proof of a JsonSchemaCredential
+type VerifyFunc func(cred *credential.Credential) error
+
+// Validator validates credentials against their credentialSchema entries. The most recently used
+// compiled schemas are cached by id, it is safe for concurrent use.
+type Validator struct {
+	mu      sync.Mutex
+	schemas *lruCache
+	options *validatorOpts
+}
+
+func NewValidator(opts ...ValidatorOption) *Validator {
+	options := prepareValidatorOpts(opts)
+	return &Validator{
+		schemas: newLRUCache(options.cacheSize),
+		options: options,
+	}
+}
+
+// Validate validates cred against every entry of its credentialSchema. Errors of the credential
+// fields are reported as credential.ValidationErrors.
+func (v *Validator) Validate(cred *credential.Credential) error {
+	entries, err := credential.GetSchemaEntries(cred.Schema)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(cred.ToMapWithoutProof())
+	if err != nil {
+		return err
+	}
+	instance, err := decodeJSON(b)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		id, _ := entry["id"].(string)
+		typ, _ := entry["type"].(string)
+		sch, err := v.schema(id, typ)
+		if err != nil {
+			return err
+		}
+		err = sch.Validate(instance)
+		var ve *jsonschema.ValidationError
+		if errors.As(err, &ve) {
+			return fmt.Errorf("credential does not match schema %s: %w", id, fieldErrors(ve))
+		}
+		if err != nil {
+			return fmt.Errorf("cannot validate schema %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// schema returns the compiled schema of a credentialSchema entry
+func (v *Validator) schema(id, typ string) (*jsonschema.Schema, error) {
+	if id == "" {
+		return nil, errors.New("credential schema id is required")
+	}
+	key := typ + " " + id
+	v.mu.Lock()
+	cached, ok := v.schemas.get(key)
+	v.mu.Unlock()
+	if ok {
+		return cached.(*jsonschema.Schema), nil
+	}
+
+	var doc []byte
+	var err error
+	switch typ {
+	case JsonSchema:
+		doc, err = v.options.loader.Load(id)
+	case JsonSchemaCredential:
+		doc, err = v.loadCredentialSchema(id)
+	default:
+		return nil, fmt.Errorf("unsupported credential schema type %q", typ)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sch, err := v.compile(id, doc)
+	if err != nil {
+		return nil, err
+	}
+	v.mu.Lock()
+	v.schemas.add(key, sch)
+	v.mu.Unlock()
+	return sch, nil
+}
+
+// loadCredentialSchema returns the JSON schema of the JsonSchemaCredential id
+func (v *Validator) loadCredentialSchema(id string) ([]byte, error) {
+	b, err := v.options.loader.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	cred := credential.NewCredential()
+	if err := json.Unmarshal(b, cred); err != nil {
+		return nil, fmt.Errorf("invalid schema credential %s: %w", id, err)
+	}
+	if !cred.HasType(JsonSchemaCredential) {
+		return nil, fmt.Errorf("schema credential %s is not a %s", id, JsonSchemaCredential)
+	}
+	if v.options.verify == nil {
+		return nil, fmt.Errorf("cannot verify schema credential %s: no schema verifier", id)
+	}
+	if err := v.options.verify(cred); err != nil {
+		return nil, fmt.Errorf("invalid schema credential %s: %w", id, err)
+	}
+	subjects := cred.Subjects()
+	if len(subjects) != 1 {
+		return nil, fmt.Errorf("schema credential %s must have one subject", id)
+	}
+	subject := subjects[0]
+	if subject["type"] != JsonSchema {
+		return nil, fmt.Errorf("schema credential %s subject is not a %s", id, JsonSchema)
+	}
+	if _, ok := subject["jsonSchema"].(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("schema credential %s has no jsonSchema", id)
+	}
+	return json.Marshal(subject["jsonSchema"])
+}
+
+func (v *Validator) compile(url string, doc []byte) (*jsonschema.Schema, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(doc, &m); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", url, err)
+	}
+	if draft, ok := m["$schema"].(string); ok && !drafts[normalizeDraft(draft)] {
+		return nil, fmt.Errorf("unsupported schema draft %s in %s", draft, url)
+	}
+	c := jsonschema.NewCompiler()
+	c.Draft = jsonschema.Draft2020
+	c.LoadURL = func(s string) (io.ReadCloser, error) {
+		b, err := v.options.loader.Load(s)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+	if err := c.AddResource(url, bytes.NewReader(doc)); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", url, err)
+	}
+	sch, err := c.Compile(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", url, err)
+	}
+	return sch, nil
+}
+
+// normalizeDraft drops the empty fragment and scheme differences of a $schema value
+func normalizeDraft(draft string) string {
+	draft = strings.TrimSuffix(draft, "#")
+	if strings.HasPrefix(draft, "http://") {
+		draft = "https://" + strings.TrimPrefix(draft, "http://")
+	}
+	return draft
+}
+
+// fieldErrors returns the leaf errors of ve located by JSON pointers in the credential
+func fieldErrors(ve *jsonschema.ValidationError) credential.ValidationErrors {
+	if len(ve.Causes) == 0 {
+		return credential.ValidationErrors{{Path: ve.InstanceLocation, Message: ve.Message}}
+	}
+	var ret credential.ValidationErrors
+	for _, cause := range ve.Causes {
+		ret = append(ret, fieldErrors(cause)...)
+	}
+	return ret
+}
+
+func decodeJSON(b []byte) (interface{}, error) {
+	var ret interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// validatorOpts holds options for Validator.
+type validatorOpts struct {
+	loader    Loader
+	verify    VerifyFunc
+	cacheSize int
+}
+
+// ValidatorOption are the options for Validator.
+type ValidatorOption func(opts *validatorOpts)
+
+// WithLoader option sets the loader of schema documents, an HTTP loader caching 256 documents by default.
+func WithLoader(loader Loader) ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.loader = loader
+	}
+}
+
+// WithSchemaVerifier option verifies the proof of JsonSchemaCredentials, they are rejected without verifier.
+func WithSchemaVerifier(verify VerifyFunc) ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.verify = verify
+	}
+}
+
+// WithCacheSize option sets the maximum number of cached compiled schemas, 256 by default.
+func WithCacheSize(size int) ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.cacheSize = size
+	}
+}
+
+func prepareValidatorOpts(opts []ValidatorOption) *validatorOpts {
+	ret := &validatorOpts{cacheSize: defaultCacheSize}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	if ret.loader == nil {
+		ret.loader = NewCachingLoader(NewHTTPLoader(nil), defaultCacheSize)
+	}
+	return ret
+}
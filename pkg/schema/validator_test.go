@@ -0,0 +1,141 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suutaku/go-vc/pkg/credential"
+	"github.com/suutaku/go-vc/test"
+)
+
+const (
+	residentSchemaURL   = "https://example.com/schemas/resident.json"
+	dateSchemaURL       = "https://example.com/schemas/date.json"
+	schemaCredentialURL = "https://example.com/credentials/resident-schema"
+)
+
+// countingLoader counts loads of every url
+type countingLoader struct {
+	loader Loader
+	count  map[string]int
+}
+
+func (l *countingLoader) Load(url string) ([]byte, error) {
+	l.count[url]++
+	return l.loader.Load(url)
+}
+
+func testLoader(t *testing.T) MapLoader {
+	ret := MapLoader{}
+	for url, name := range map[string]string{
+		residentSchemaURL:   "json-schema-resident.json",
+		dateSchemaURL:       "json-schema-date.json",
+		schemaCredentialURL: "json-schema-credential.json",
+	} {
+		b, err := test.GetTestResource(name)
+		require.NoError(t, err)
+		ret[url] = b
+	}
+	return ret
+}
+
+func testCredential(t *testing.T, schemas ...interface{}) *credential.Credential {
+	b, err := test.GetTestResource("vc-json-doc-ed25519.json")
+	require.NoError(t, err)
+	cred := credential.NewCredential()
+	assert.NoError(t, cred.FromBytes(b))
+	cred.Schema = schemas
+	return cred
+}
+
+func TestValidateJsonSchema(t *testing.T) {
+	loader := &countingLoader{loader: testLoader(t), count: map[string]int{}}
+	v := NewValidator(WithLoader(NewCachingLoader(loader, 8)))
+
+	cred := testCredential(t, map[string]interface{}{"id": residentSchemaURL, "type": JsonSchema})
+	assert.NoError(t, v.Validate(cred))
+
+	subject := cred.Subject.(map[string]interface{})
+	subject["birthDate"] = "17/07/1958"
+	delete(subject, "familyName")
+	err := v.Validate(cred)
+	var errs credential.ValidationErrors
+	assert.True(t, errors.As(err, &errs), err)
+	paths := make([]string, len(errs))
+	for i, fe := range errs {
+		paths[i] = fe.Path
+	}
+	assert.ElementsMatch(t, []string{"/credentialSubject", "/credentialSubject/birthDate"}, paths)
+
+	// schemas are compiled once
+	assert.Equal(t, 1, loader.count[residentSchemaURL])
+	assert.Equal(t, 1, loader.count[dateSchemaURL])
+
+	// no schema
+	assert.NoError(t, v.Validate(testCredential(t)))
+}
+
+func TestValidateJsonSchemaCredential(t *testing.T) {
+	verified := 0
+	v := NewValidator(WithLoader(testLoader(t)), WithSchemaVerifier(func(cred *credential.Credential) error {
+		verified++
+		return nil
+	}))
+	cred := testCredential(t,
+		map[string]interface{}{"id": residentSchemaURL, "type": JsonSchema},
+		map[string]interface{}{"id": schemaCredentialURL, "type": JsonSchemaCredential},
+	)
+	assert.NoError(t, v.Validate(cred))
+	assert.Equal(t, 1, verified)
+
+	cred.Subject.(map[string]interface{})["gender"] = "Unknown"
+	assert.ErrorContains(t, v.Validate(cred), "/credentialSubject/gender")
+
+	v = NewValidator(WithLoader(testLoader(t)), WithSchemaVerifier(func(cred *credential.Credential) error {
+		return errors.New("bad proof")
+	}))
+	assert.ErrorContains(t, v.Validate(cred), "bad proof")
+	// schema credentials are rejected without verifier
+	assert.ErrorContains(t, NewValidator(WithLoader(testLoader(t))).Validate(cred), "no schema verifier")
+
+	// a JSON schema is not a JsonSchemaCredential
+	cred = testCredential(t, map[string]interface{}{"id": residentSchemaURL, "type": JsonSchemaCredential})
+	assert.ErrorContains(t, v.Validate(cred), "is not a JsonSchemaCredential")
+}
+
+func TestValidateUnsupported(t *testing.T) {
+	loader := testLoader(t)
+	loader["https://example.com/schemas/draft4.json"] = []byte(`{"$schema": "http://json-schema.org/draft-04/schema#"}`)
+	v := NewValidator(WithLoader(loader))
+
+	cred := testCredential(t, map[string]interface{}{"id": "https://example.com/schemas/draft4.json", "type": JsonSchema})
+	assert.ErrorContains(t, v.Validate(cred), "unsupported schema draft")
+
+	cred = testCredential(t, map[string]interface{}{"id": residentSchemaURL, "type": "JsonSchemaValidator2018"})
+	assert.ErrorContains(t, v.Validate(cred), "unsupported credential schema type")
+
+	cred = testCredential(t, map[string]interface{}{"id": "https://example.com/missing.json", "type": JsonSchema})
+	assert.ErrorContains(t, v.Validate(cred), "not found")
+}
+
+func TestValidatorCaches(t *testing.T) {
+	loader := &countingLoader{loader: testLoader(t), count: map[string]int{}}
+	caching := NewCachingLoader(loader, 1)
+	for _, url := range []string{residentSchemaURL, residentSchemaURL, dateSchemaURL, residentSchemaURL} {
+		_, err := caching.Load(url)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, loader.count[residentSchemaURL])
+	assert.Equal(t, 1, caching.cache.len())
+
+	// compiled schemas are bounded too
+	schemas := testLoader(t)
+	schemas["https://example.com/schemas/any.json"] = []byte(`{}`)
+	v := NewValidator(WithLoader(schemas), WithCacheSize(1))
+	for _, url := range []string{residentSchemaURL, "https://example.com/schemas/any.json"} {
+		assert.NoError(t, v.Validate(testCredential(t, map[string]interface{}{"id": url, "type": JsonSchema})))
+	}
+	assert.Equal(t, 1, v.schemas.len())
+}
@@ -0,0 +1,297 @@
+package sdjwt
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	hashAlg      = "sha-256"
+	saltSize     = 16
+	sdKey        = "_sd"
+	sdAlgKey     = "_sd_alg"
+	arrayElemKey = "..."
+)
+
+// Disclosure discloses an object property (Name is set) or an array element
+type Disclosure struct {
+	Salt  string
+	Name  string
+	Value interface{}
+	// Pointer is the JSON pointer of the disclosed value in the credential
+	Pointer string
+	encoded string
+}
+
+func newDisclosure(name string, value interface{}, isArrayElement bool) (*Disclosure, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	ret := &Disclosure{Salt: base64.RawURLEncoding.EncodeToString(salt), Name: name, Value: value}
+	array := []interface{}{ret.Salt, name, value}
+	if isArrayElement {
+		array = []interface{}{ret.Salt, value}
+	}
+	b, err := json.Marshal(array)
+	if err != nil {
+		return nil, err
+	}
+	ret.encoded = base64.RawURLEncoding.EncodeToString(b)
+	return ret, nil
+}
+
+// parseDisclosure decodes a base64url encoded disclosure
+func parseDisclosure(encoded string) (*Disclosure, error) {
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid disclosure: %w", err)
+	}
+	var array []interface{}
+	if err := json.Unmarshal(b, &array); err != nil {
+		return nil, fmt.Errorf("invalid disclosure: %w", err)
+	}
+	ret := &Disclosure{encoded: encoded}
+	var ok bool
+	switch len(array) {
+	case 2:
+		ret.Salt, ok = array[0].(string)
+		ret.Value = array[1]
+	case 3:
+		var saltOK bool
+		ret.Salt, saltOK = array[0].(string)
+		ret.Name, ok = array[1].(string)
+		ok = ok && saltOK && ret.Name != sdKey && ret.Name != arrayElemKey
+		ret.Value = array[2]
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid disclosure %s", encoded)
+	}
+	return ret, nil
+}
+
+// IsArrayElement reports whether the disclosure discloses an array element
+func (d *Disclosure) IsArrayElement() bool {
+	return d.Name == ""
+}
+
+// Encoded returns the base64url encoded disclosure
+func (d *Disclosure) Encoded() string {
+	return d.encoded
+}
+
+// Digest returns the base64url encoded sha-256 digest of the disclosure
+func (d *Disclosure) Digest() string {
+	h := sha256.Sum256([]byte(d.encoded))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+// makeDisclosable replaces the value at pointer of doc with a digest and returns its disclosure
+func makeDisclosable(doc map[string]interface{}, pointer string) (*Disclosure, error) {
+	paths, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	var parent interface{} = doc
+	for _, path := range paths[:len(paths)-1] {
+		if parent, err = childOf(parent, path); err != nil {
+			return nil, fmt.Errorf("%s: %w", pointer, err)
+		}
+	}
+	last := paths[len(paths)-1]
+	value, err := childOf(parent, last)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", pointer, err)
+	}
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		d, err := newDisclosure(last, value, false)
+		if err != nil {
+			return nil, err
+		}
+		delete(p, last)
+		digests, _ := p[sdKey].([]interface{})
+		p[sdKey] = append(digests, d.Digest())
+		d.Pointer = pointer
+		return d, nil
+	case []interface{}:
+		d, err := newDisclosure("", value, true)
+		if err != nil {
+			return nil, err
+		}
+		idx, _ := strconv.Atoi(last)
+		p[idx] = map[string]interface{}{arrayElemKey: d.Digest()}
+		d.Pointer = pointer
+		return d, nil
+	}
+	return nil, fmt.Errorf("%s: JSON pointer does not match the document", pointer)
+}
+
+// sortDigests sorts the _sd arrays of value so digest order does not reveal claim order
+func sortDigests(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if digests, ok := child.([]interface{}); ok && k == sdKey {
+				sort.Slice(digests, func(i, j int) bool {
+					return fmt.Sprint(digests[i]) < fmt.Sprint(digests[j])
+				})
+				continue
+			}
+			sortDigests(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			sortDigests(child)
+		}
+	}
+}
+
+// unfolder replaces digests of a payload with the disclosed values
+type unfolder struct {
+	disclosures map[string]*Disclosure
+	used        map[string]bool
+}
+
+func newUnfolder(disclosures []*Disclosure) (*unfolder, error) {
+	ret := &unfolder{
+		disclosures: make(map[string]*Disclosure, len(disclosures)),
+		used:        make(map[string]bool, len(disclosures)),
+	}
+	for _, d := range disclosures {
+		digest := d.Digest()
+		if _, ok := ret.disclosures[digest]; ok {
+			return nil, fmt.Errorf("duplicated disclosure %s", d.encoded)
+		}
+		ret.disclosures[digest] = d
+	}
+	return ret, nil
+}
+
+// disclosure returns the unused disclosure of digest, nil if the digest is not disclosed
+func (u *unfolder) disclosure(digest interface{}) (*Disclosure, error) {
+	s, ok := digest.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid digest %v", digest)
+	}
+	d, ok := u.disclosures[s]
+	if !ok {
+		return nil, nil
+	}
+	if u.used[s] {
+		return nil, fmt.Errorf("digest %s referenced more than once", s)
+	}
+	u.used[s] = true
+	return d, nil
+}
+
+func (u *unfolder) unfold(value interface{}, pointer string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			if k == sdKey {
+				continue
+			}
+			unfolded, err := u.unfold(child, pointer+"/"+escapePointer(k))
+			if err != nil {
+				return nil, err
+			}
+			ret[k] = unfolded
+		}
+		digests, _ := v[sdKey].([]interface{})
+		for _, digest := range digests {
+			d, err := u.disclosure(digest)
+			if err != nil {
+				return nil, err
+			}
+			if d == nil {
+				continue
+			}
+			if d.IsArrayElement() {
+				return nil, fmt.Errorf("array element disclosure referenced by %s", sdKey)
+			}
+			if _, ok := ret[d.Name]; ok {
+				return nil, fmt.Errorf("disclosed claim %s already exists", d.Name)
+			}
+			d.Pointer = pointer + "/" + escapePointer(d.Name)
+			if ret[d.Name], err = u.unfold(d.Value, d.Pointer); err != nil {
+				return nil, err
+			}
+		}
+		return ret, nil
+	case []interface{}:
+		ret := make([]interface{}, 0, len(v))
+		for i, child := range v {
+			elemPointer := pointer + "/" + strconv.Itoa(i)
+			if m, ok := child.(map[string]interface{}); ok && len(m) == 1 && m[arrayElemKey] != nil {
+				d, err := u.disclosure(m[arrayElemKey])
+				if err != nil {
+					return nil, err
+				}
+				if d == nil {
+					continue
+				}
+				if !d.IsArrayElement() {
+					return nil, fmt.Errorf("object property disclosure referenced by array element")
+				}
+				d.Pointer = elemPointer
+				child = d.Value
+			}
+			unfolded, err := u.unfold(child, elemPointer)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, unfolded)
+		}
+		return ret, nil
+	}
+	return value, nil
+}
+
+// checkAllUsed returns an error if any disclosure is not referenced by the payload
+func (u *unfolder) checkAllUsed() error {
+	for digest, d := range u.disclosures {
+		if !u.used[digest] {
+			return fmt.Errorf("disclosure %s not referenced", d.encoded)
+		}
+	}
+	return nil
+}
+
+// parsePointer splits a JSON pointer (RFC 6901) into unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	paths := strings.Split(pointer[1:], "/")
+	for i := range paths {
+		paths[i] = strings.ReplaceAll(strings.ReplaceAll(paths[i], "~1", "/"), "~0", "~")
+	}
+	return paths, nil
+}
+
+func escapePointer(path string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(path, "~", "~0"), "/", "~1")
+}
+
+func childOf(value interface{}, path string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if child, ok := v[path]; ok {
+			return child, nil
+		}
+	case []interface{}:
+		idx, err := strconv.Atoi(path)
+		if err == nil && idx >= 0 && idx < len(v) {
+			return v[idx], nil
+		}
+	}
+	return nil, fmt.Errorf("JSON pointer does not match the document")
+}
@@ -0,0 +1,108 @@
+package sdjwt
+
+import (
+	"time"
+
+	"github.com/suutaku/go-vc/pkg/jose"
+)
+
+// issueOpts holds options for SD-JWT issuance.
+type issueOpts struct {
+	holderKey *jose.JWK
+}
+
+// IssueOption are the options for SD-JWT issuance.
+type IssueOption func(opts *issueOpts)
+
+// WithHolderKey option binds the SD-JWT to the holder's public key (cnf claim).
+func WithHolderKey(jwk *jose.JWK) IssueOption {
+	return func(opts *issueOpts) {
+		opts.holderKey = jwk
+	}
+}
+
+func prepareIssueOpts(opts []IssueOption) *issueOpts {
+	ret := &issueOpts{}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
+
+// presentOpts holds options for SD-JWT presentation.
+type presentOpts struct {
+	clock func() time.Time
+}
+
+// PresentOption are the options for SD-JWT presentation.
+type PresentOption func(opts *presentOpts)
+
+// WithIssuedAtClock option sets the clock of the key binding JWT iat claim, time.Now by default.
+func WithIssuedAtClock(clock func() time.Time) PresentOption {
+	return func(opts *presentOpts) {
+		opts.clock = clock
+	}
+}
+
+func preparePresentOpts(opts []PresentOption) *presentOpts {
+	ret := &presentOpts{
+		clock: time.Now,
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
+
+// verifyOpts holds options for SD-JWT verification.
+type verifyOpts struct {
+	clock      func() time.Time
+	leeway     time.Duration
+	keyBinding bool
+	audience   string
+	nonce      string
+	maxAge     time.Duration
+}
+
+// VerifyOption are the options for SD-JWT verification.
+type VerifyOption func(opts *verifyOpts)
+
+// WithClock option sets the clock used to check exp, nbf and key binding iat claims.
+func WithClock(clock func() time.Time) VerifyOption {
+	return func(opts *verifyOpts) {
+		opts.clock = clock
+	}
+}
+
+// WithLeeway option tolerates clock skew when checking time claims.
+func WithLeeway(leeway time.Duration) VerifyOption {
+	return func(opts *verifyOpts) {
+		opts.leeway = leeway
+	}
+}
+
+// WithKeyBinding option requires a key binding JWT for audience and nonce.
+func WithKeyBinding(audience, nonce string) VerifyOption {
+	return func(opts *verifyOpts) {
+		opts.keyBinding = true
+		opts.audience = audience
+		opts.nonce = nonce
+	}
+}
+
+// WithMaxAge option rejects key binding JWTs issued longer than maxAge ago.
+func WithMaxAge(maxAge time.Duration) VerifyOption {
+	return func(opts *verifyOpts) {
+		opts.maxAge = maxAge
+	}
+}
+
+func prepareVerifyOpts(opts []VerifyOption) *verifyOpts {
+	ret := &verifyOpts{
+		clock: time.Now,
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
@@ -0,0 +1,283 @@
+// Package sdjwt issues credentials as SD-JWTs (https://www.rfc-editor.org/rfc/rfc9901) with
+// selectively disclosable claims, creates holder presentations with key binding and verifies them.
+//
+// Disclosable claims are selected with JSON pointers into the credential, e.g. /credentialSubject/givenName.
+// The issuer JWT keeps the VC-JWT layout with the credential in the vc claim, so it is typed as a plain
+// SD-JWT rather than an SD-JWT VC, which has the vct claim and top-level claims instead.
+package sdjwt
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/suutaku/go-vc/pkg/credential"
+	"github.com/suutaku/go-vc/pkg/jose"
+	"github.com/suutaku/go-vc/pkg/jwtvc"
+	"github.com/suutaku/go-vc/pkg/resolver"
+)
+
+const (
+	separator      = "~"
+	issuerJWTType  = "sd-jwt"
+	keyBindingType = "kb+jwt"
+)
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// Confirmation is the cnf claim which binds the SD-JWT to the holder's key
+type Confirmation struct {
+	JWK *jose.JWK `json:"jwk"`
+}
+
+// keyBindingClaims are the claims of a key binding JWT
+type keyBindingClaims struct {
+	IssuedAt int64  `json:"iat"`
+	Audience string `json:"aud"`
+	Nonce    string `json:"nonce"`
+	SDHash   string `json:"sd_hash"`
+}
+
+// SDJWT is a parsed SD-JWT
+type SDJWT struct {
+	IssuerJWT     string
+	Disclosures   []*Disclosure
+	KeyBindingJWT string
+	header        *header
+	payload       map[string]interface{}
+	signingInput  string
+	signature     []byte
+	// claims are the issuer JWT claims with disclosed values
+	claims map[string]interface{}
+}
+
+// Issue issues cred as an SD-JWT signed by signer, kid is the issuer's verification method.
+// disclosable are JSON pointers of the credential values which the holder can selectively disclose.
+func Issue(cred *credential.Credential, signer jose.Signer, kid string, disclosable []string, opts ...IssueOption) (string, error) {
+	options := prepareIssueOpts(opts)
+	claims, err := jwtvc.CredentialToClaims(cred)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	payload := make(map[string]interface{})
+	if err := json.Unmarshal(b, &payload); err != nil {
+		return "", err
+	}
+	vc, _ := payload["vc"].(map[string]interface{})
+
+	// fold nested values first, so their digests end up in the parent disclosure
+	pointers := append([]string{}, disclosable...)
+	sort.SliceStable(pointers, func(i, j int) bool {
+		return strings.Count(pointers[i], "/") > strings.Count(pointers[j], "/")
+	})
+	disclosures := make([]string, 0, len(pointers))
+	for _, pointer := range pointers {
+		d, err := makeDisclosable(vc, pointer)
+		if err != nil {
+			return "", fmt.Errorf("make %s disclosable: %w", pointer, err)
+		}
+		disclosures = append(disclosures, d.Encoded())
+	}
+	sortDigests(vc)
+	payload[sdAlgKey] = hashAlg
+	if options.holderKey != nil {
+		payload["cnf"] = &Confirmation{JWK: options.holderKey}
+	}
+	issuerJWT, err := jose.SignCompact(&header{Alg: signer.Alg(), Typ: issuerJWTType, Kid: kid}, payload, signer)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(append([]string{issuerJWT}, disclosures...), separator) + separator, nil
+}
+
+// Parse parses an SD-JWT without verifying it
+func Parse(sdJWT string) (*SDJWT, error) {
+	parts := strings.Split(sdJWT, separator)
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid sd-jwt")
+	}
+	ret := &SDJWT{
+		IssuerJWT:     parts[0],
+		KeyBindingJWT: parts[len(parts)-1],
+		header:        &header{},
+		payload:       make(map[string]interface{}),
+	}
+	var err error
+	ret.signingInput, ret.signature, err = jose.ParseCompact(ret.IssuerJWT, ret.header, &ret.payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sd-jwt issuer jwt: %w", err)
+	}
+	if alg, _ := ret.payload[sdAlgKey].(string); alg != "" && alg != hashAlg {
+		return nil, fmt.Errorf("unsupported %s %s", sdAlgKey, alg)
+	}
+	for _, encoded := range parts[1 : len(parts)-1] {
+		d, err := parseDisclosure(encoded)
+		if err != nil {
+			return nil, err
+		}
+		ret.Disclosures = append(ret.Disclosures, d)
+	}
+	u, err := newUnfolder(ret.Disclosures)
+	if err != nil {
+		return nil, err
+	}
+	claims := make(map[string]interface{}, len(ret.payload))
+	for k, v := range ret.payload {
+		if k == sdAlgKey {
+			continue
+		}
+		if k != "vc" {
+			claims[k] = v
+			continue
+		}
+		if claims[k], err = u.unfold(v, ""); err != nil {
+			return nil, err
+		}
+	}
+	if err := u.checkAllUsed(); err != nil {
+		return nil, err
+	}
+	ret.claims = claims
+	return ret, nil
+}
+
+// Select returns a copy of the SD-JWT which only discloses values at pointers,
+// disclosures of enclosing values are kept so that selected values can be reached
+func (s *SDJWT) Select(pointers []string) *SDJWT {
+	ret := *s
+	ret.Disclosures = nil
+	ret.KeyBindingJWT = ""
+	for _, d := range s.Disclosures {
+		for _, pointer := range pointers {
+			if d.Pointer == pointer || strings.HasPrefix(pointer, d.Pointer+"/") || strings.HasPrefix(d.Pointer, pointer+"/") {
+				ret.Disclosures = append(ret.Disclosures, d)
+				break
+			}
+		}
+	}
+	return &ret
+}
+
+// Serialize returns the SD-JWT in compact serialization
+func (s *SDJWT) Serialize() string {
+	parts := []string{s.IssuerJWT}
+	for _, d := range s.Disclosures {
+		parts = append(parts, d.Encoded())
+	}
+	return strings.Join(parts, separator) + separator + s.KeyBindingJWT
+}
+
+// Present creates a presentation which only discloses values at pointers. When holder is not nil
+// a key binding JWT for audience and nonce is appended.
+func (s *SDJWT) Present(pointers []string, holder jose.Signer, audience, nonce string, opts ...PresentOption) (string, error) {
+	presentation := s.Select(pointers).Serialize()
+	if holder == nil {
+		return presentation, nil
+	}
+	options := preparePresentOpts(opts)
+	kbJWT, err := jose.SignCompact(&header{Alg: holder.Alg(), Typ: keyBindingType}, &keyBindingClaims{
+		IssuedAt: options.clock().Unix(),
+		Audience: audience,
+		Nonce:    nonce,
+		SDHash:   sdHash(presentation),
+	}, holder)
+	if err != nil {
+		return "", err
+	}
+	return presentation + kbJWT, nil
+}
+
+// Verify verifies an SD-JWT presentation with the issuer key from pubResolver and
+// returns the credential with the disclosed values
+func Verify(sdJWT string, pubResolver resolver.PublicKeyResolver, opts ...VerifyOption) (*credential.Credential, error) {
+	options := prepareVerifyOpts(opts)
+	s, err := Parse(sdJWT)
+	if err != nil {
+		return nil, err
+	}
+	if s.header.Typ != "" && s.header.Typ != issuerJWTType {
+		return nil, fmt.Errorf("unexpected sd-jwt typ %s", s.header.Typ)
+	}
+	claims := &jwtvc.Claims{}
+	if err := remarshal(s.claims, claims); err != nil {
+		return nil, err
+	}
+	jwk, err := jwtvc.ResolveKey(pubResolver, s.header.Kid, claims.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	if err := jose.Verify(s.header.Alg, jwk, []byte(s.signingInput), s.signature); err != nil {
+		return nil, fmt.Errorf("verify sd-jwt issuer signature: %w", err)
+	}
+	if err := claims.Validate(jwtvc.WithClock(options.clock), jwtvc.WithLeeway(options.leeway)); err != nil {
+		return nil, err
+	}
+	if s.KeyBindingJWT != "" || options.keyBinding {
+		if err := s.verifyKeyBinding(sdJWT, options); err != nil {
+			return nil, err
+		}
+	}
+	return claims.ToCredential()
+}
+
+func (s *SDJWT) verifyKeyBinding(sdJWT string, opts *verifyOpts) error {
+	if s.KeyBindingJWT == "" {
+		return fmt.Errorf("sd-jwt has no key binding jwt")
+	}
+	cnf := &Confirmation{}
+	if err := remarshal(s.payload["cnf"], cnf); err != nil || cnf.JWK == nil {
+		return fmt.Errorf("sd-jwt has no cnf jwk to verify key binding")
+	}
+	kbHeader, kbClaims := &header{}, &keyBindingClaims{}
+	signingInput, sig, err := jose.ParseCompact(s.KeyBindingJWT, kbHeader, kbClaims)
+	if err != nil {
+		return fmt.Errorf("invalid key binding jwt: %w", err)
+	}
+	if kbHeader.Typ != keyBindingType {
+		return fmt.Errorf("unexpected key binding jwt typ %s", kbHeader.Typ)
+	}
+	if err := jose.Verify(kbHeader.Alg, cnf.JWK, []byte(signingInput), sig); err != nil {
+		return fmt.Errorf("verify key binding signature: %w", err)
+	}
+	if kbClaims.SDHash != sdHash(strings.TrimSuffix(sdJWT, s.KeyBindingJWT)) {
+		return fmt.Errorf("key binding sd_hash not match")
+	}
+	if opts.audience != "" && kbClaims.Audience != opts.audience {
+		return fmt.Errorf("key binding audience not match %s", opts.audience)
+	}
+	if opts.nonce != "" && kbClaims.Nonce != opts.nonce {
+		return fmt.Errorf("key binding nonce not match")
+	}
+	issuedAt := time.Unix(kbClaims.IssuedAt, 0)
+	now := opts.clock()
+	if issuedAt.After(now.Add(opts.leeway)) || (opts.maxAge > 0 && now.Sub(issuedAt) > opts.maxAge+opts.leeway) {
+		return fmt.Errorf("key binding jwt issued at %s is not acceptable", issuedAt.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// sdHash returns the base64url encoded sha-256 digest of the presented SD-JWT
+func sdHash(presentation string) string {
+	h := sha256.Sum256([]byte(presentation))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+func remarshal(from, to interface{}) error {
+	b, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, to)
+}
@@ -0,0 +1,155 @@
+package sdjwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suutaku/go-vc/pkg/credential"
+	"github.com/suutaku/go-vc/pkg/jose"
+	"github.com/suutaku/go-vc/pkg/resolver"
+	"github.com/suutaku/go-vc/test"
+)
+
+const (
+	credentialDocPath = "vc-json-doc-jws.json"
+	issuerKid         = "did:example:489398593#key-1"
+	audience          = "https://verifier.example"
+	nonce             = "n-0S6_WzA2Mj"
+)
+
+func newTestKey(t *testing.T) (jose.Signer, *jose.JWK) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer, err := jose.NewSigner(priv)
+	require.NoError(t, err)
+	jwk, err := jose.NewJWK(&priv.PublicKey)
+	require.NoError(t, err)
+	return signer, jwk
+}
+
+func TestSDJWT(t *testing.T) {
+	credBytes, err := test.GetTestResource(credentialDocPath)
+	require.NoError(t, err)
+	cred := credential.NewCredential()
+	assert.NoError(t, cred.FromBytes(credBytes))
+	issuer, issuerJWK := newTestKey(t)
+	holder, holderJWK := newTestKey(t)
+	pubResolver := resolver.NewTestPublicKeyResolver(&resolver.PublicKey{Type: "JsonWebKey2020", Jwk: issuerJWK}, nil)
+
+	disclosable := []string{
+		"/credentialSubject/givenName",
+		"/credentialSubject/familyName",
+		"/credentialSubject/birthDate",
+		"/credentialSubject/type/1",
+	}
+	issued, err := Issue(cred, issuer, issuerKid, disclosable, WithHolderKey(holderJWK))
+	require.NoError(t, err)
+	assert.NotContains(t, issued, "JOHN")
+
+	sd, err := Parse(issued)
+	require.NoError(t, err)
+	assert.Equal(t, issuerJWTType, sd.header.Typ)
+	assert.Contains(t, sd.payload, "vc")
+	assert.NotContains(t, sd.payload, "vct")
+	assert.Len(t, sd.Disclosures, len(disclosable))
+	pointers := make([]string, 0, len(sd.Disclosures))
+	for _, d := range sd.Disclosures {
+		pointers = append(pointers, d.Pointer)
+	}
+	assert.ElementsMatch(t, disclosable, pointers)
+
+	// issuer signed SD-JWT discloses everything
+	full, err := Verify(issued, pubResolver)
+	require.NoError(t, err)
+	assert.Equal(t, cred.ToMap(), full.ToMap())
+
+	presentation, err := sd.Present([]string{"/credentialSubject/givenName"}, holder, audience, nonce)
+	require.NoError(t, err)
+	disclosed, err := Verify(presentation, pubResolver, WithKeyBinding(audience, nonce))
+	require.NoError(t, err)
+	subject := disclosed.ToMap()["credentialSubject"].(map[string]interface{})
+	assert.Equal(t, "JOHN", subject["givenName"])
+	assert.Equal(t, "did:example:b34ca6cd37bbf23", subject["id"])
+	assert.NotContains(t, subject, "familyName")
+	assert.NotContains(t, subject, "birthDate")
+	assert.Equal(t, []interface{}{"PermanentResident"}, subject["type"])
+
+	// key binding for another verifier
+	_, err = Verify(presentation, pubResolver, WithKeyBinding("https://other.example", nonce))
+	assert.Error(t, err)
+	_, err = Verify(presentation, pubResolver, WithKeyBinding(audience, "replayed"))
+	assert.Error(t, err)
+	// key binding required
+	withoutKB, err := sd.Present([]string{"/credentialSubject/givenName"}, nil, "", "")
+	require.NoError(t, err)
+	_, err = Verify(withoutKB, pubResolver, WithKeyBinding(audience, nonce))
+	assert.Error(t, err)
+	// key binding moved to a presentation disclosing more
+	more, err := sd.Present([]string{"/credentialSubject/givenName", "/credentialSubject/birthDate"}, nil, "", "")
+	require.NoError(t, err)
+	kbJWT := presentation[strings.LastIndex(presentation, separator)+1:]
+	_, err = Verify(more+kbJWT, pubResolver, WithKeyBinding(audience, nonce))
+	assert.Error(t, err)
+	// key binding signed by another key
+	other, _ := newTestKey(t)
+	forged, err := sd.Present([]string{"/credentialSubject/givenName"}, other, audience, nonce)
+	require.NoError(t, err)
+	_, err = Verify(forged, pubResolver, WithKeyBinding(audience, nonce))
+	assert.Error(t, err)
+	// key binding issued at a fixed time
+	issuedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dated, err := sd.Present([]string{"/credentialSubject/givenName"}, holder, audience, nonce, WithIssuedAtClock(func() time.Time { return issuedAt }))
+	require.NoError(t, err)
+	_, err = Verify(dated, pubResolver, WithKeyBinding(audience, nonce), WithMaxAge(time.Minute),
+		WithClock(func() time.Time { return issuedAt.Add(30 * time.Second) }))
+	assert.NoError(t, err)
+	_, err = Verify(dated, pubResolver, WithKeyBinding(audience, nonce), WithMaxAge(time.Minute),
+		WithClock(func() time.Time { return issuedAt.Add(time.Hour) }))
+	assert.Error(t, err)
+	_, err = Verify(dated, pubResolver, WithKeyBinding(audience, nonce),
+		WithClock(func() time.Time { return issuedAt.Add(-time.Hour) }))
+	assert.Error(t, err)
+	// disclosure not issued
+	d, err := newDisclosure("gender", "Female", false)
+	require.NoError(t, err)
+	_, err = Verify(withoutKB+d.Encoded()+separator, pubResolver)
+	assert.Error(t, err)
+	// wrong issuer key
+	_, otherJWK := newTestKey(t)
+	_, err = Verify(withoutKB, resolver.NewTestPublicKeyResolver(&resolver.PublicKey{Jwk: otherJWK}, nil))
+	assert.Error(t, err)
+}
+
+func TestNestedDisclosure(t *testing.T) {
+	credBytes, err := test.GetTestResource(credentialDocPath)
+	require.NoError(t, err)
+	cred := credential.NewCredential()
+	assert.NoError(t, cred.FromBytes(credBytes))
+	issuer, issuerJWK := newTestKey(t)
+	pubResolver := resolver.NewTestPublicKeyResolver(&resolver.PublicKey{Jwk: issuerJWK}, nil)
+
+	issued, err := Issue(cred, issuer, issuerKid, []string{"/credentialSubject", "/credentialSubject/givenName"})
+	require.NoError(t, err)
+	sd, err := Parse(issued)
+	require.NoError(t, err)
+
+	// the enclosing credentialSubject disclosure is kept
+	presentation, err := sd.Present([]string{"/credentialSubject/givenName"}, nil, "", "")
+	require.NoError(t, err)
+	assert.Len(t, sd.Select([]string{"/credentialSubject/givenName"}).Disclosures, 2)
+	disclosed, err := Verify(presentation, pubResolver)
+	require.NoError(t, err)
+	assert.Equal(t, "JOHN", disclosed.ToMap()["credentialSubject"].(map[string]interface{})["givenName"])
+
+	// nothing disclosed
+	presentation, err = sd.Present(nil, nil, "", "")
+	require.NoError(t, err)
+	_, err = Parse(presentation)
+	assert.NoError(t, err)
+}
@@ -5,26 +5,196 @@ import (
 	"compress/gzip"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"strconv"
+	"strings"
 
+	"github.com/suutaku/go-vc/internal/tools"
 	"github.com/suutaku/go-vc/pkg/credential"
 )
 
 // mininum size of 16 KB
 const miniBytesLen = 1024 << 4
 
+// maxBitStringBytes limits the size of a decompressed bitstring to the maximum list size
+const maxBitStringBytes = maxListBytes
+
+// BitString is a status list bitstring, the left-most (most significant) bit of the first byte is index 0.
+// https://www.w3.org/TR/vc-bitstring-status-list/#bitstring-encoding
 type BitString struct {
-	compressed string
+	bits   []byte
+	length int
+	// err is the decoding error of ParseBitString
+	err error
+}
+
+// NewBitString creates a bitstring of length bits initialized to 0
+func NewBitString(length int) *BitString {
+	return bitStringOf(make([]byte, (length+7)/8), length)
+}
+
+// bitStringOf wraps bits, which is shared with the returned bitstring
+func bitStringOf(bits []byte, length int) *BitString {
+	return &BitString{bits: bits, length: length}
+}
+
+// DecodeBitString decodes a GZIP compressed and base64url encoded bitstring, a multibase
+// base64url value (BitstringStatusList) and legacy base64 values are accepted too.
+// Bitstrings larger than 8 MB once decompressed are rejected.
+func DecodeBitString(encoded string) (*BitString, error) {
+	if strings.HasPrefix(encoded, string(rune(tools.MultibaseBase64URL))) {
+		encoded = encoded[1:]
+	}
+	encoded = strings.TrimRight(encoded, "=")
+	compressed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		if compressed, err = base64.RawStdEncoding.DecodeString(encoded); err != nil {
+			return nil, fmt.Errorf("invalid bitstring encoding: %w", err)
+		}
+	}
+	gr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bitstring compression: %w", err)
+	}
+	res := bytes.Buffer{}
+	if _, err := res.ReadFrom(io.LimitReader(gr, maxBitStringBytes+1)); err != nil {
+		return nil, fmt.Errorf("invalid bitstring compression: %w", err)
+	}
+	if res.Len() > maxBitStringBytes {
+		return nil, fmt.Errorf("bitstring exceeds %d bytes", maxBitStringBytes)
+	}
+	return bitStringOf(res.Bytes(), res.Len()*8), nil
+}
+
+// Len returns the number of bits
+func (bs *BitString) Len() int {
+	return bs.length
+}
+
+// Get returns the bit at idx
+func (bs *BitString) Get(idx int) (bool, error) {
+	if err := bs.checkIndex(idx); err != nil {
+		return false, err
+	}
+	return bs.bits[idx/8]&(1<<(7-idx%8)) != 0, nil
+}
+
+// Set sets the bit at idx to 1
+func (bs *BitString) Set(idx int) error {
+	if err := bs.checkIndex(idx); err != nil {
+		return err
+	}
+	bs.bits[idx/8] |= 1 << (7 - idx%8)
+	return nil
+}
+
+// Clear sets the bit at idx to 0
+func (bs *BitString) Clear(idx int) error {
+	if err := bs.checkIndex(idx); err != nil {
+		return err
+	}
+	bs.bits[idx/8] &^= 1 << (7 - idx%8)
+	return nil
+}
+
+// GetValue returns the size bits value of entry idx, the first bit is the most significant one
+func (bs *BitString) GetValue(idx, size int) (uint64, error) {
+	if err := bs.checkValue(idx, size); err != nil {
+		return 0, err
+	}
+	var ret uint64
+	for i := idx * size; i < (idx+1)*size; i++ {
+		ret <<= 1
+		if bs.bits[i/8]&(1<<(7-i%8)) != 0 {
+			ret |= 1
+		}
+	}
+	return ret, nil
+}
+
+// SetValue sets the size bits of entry idx to value
+func (bs *BitString) SetValue(idx, size int, value uint64) error {
+	if err := bs.checkValue(idx, size); err != nil {
+		return err
+	}
+	if size < 64 && value >= 1<<size {
+		return fmt.Errorf("status value %d exceeds %d bits", value, size)
+	}
+	for i := (idx+1)*size - 1; i >= idx*size; i-- {
+		if value&1 != 0 {
+			bs.bits[i/8] |= 1 << (7 - i%8)
+		} else {
+			bs.bits[i/8] &^= 1 << (7 - i%8)
+		}
+		value >>= 1
+	}
+	return nil
+}
+
+// Bytes returns the uncompressed bitstring
+func (bs *BitString) Bytes() []byte {
+	return bs.bits
+}
+
+// Encode GZIP compresses and base64url encodes the bitstring (StatusList2021 encodedList)
+func (bs *BitString) Encode() (string, error) {
+	buf := bytes.Buffer{}
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(bs.bits); err != nil {
+		return "", err
+	}
+	if err := gw.Close(); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
 }
 
+// EncodeMultibase GZIP compresses and multibase base64url encodes the bitstring (BitstringStatusList encodedList)
+func (bs *BitString) EncodeMultibase() (string, error) {
+	encoded, err := bs.Encode()
+	if err != nil {
+		return "", err
+	}
+	return string(rune(tools.MultibaseBase64URL)) + encoded, nil
+}
+
+func (bs *BitString) checkIndex(idx int) error {
+	if bs.err != nil {
+		return bs.err
+	}
+	if idx < 0 || idx >= bs.length {
+		return fmt.Errorf("bitstring index %d out of range [0, %d)", idx, bs.length)
+	}
+	return nil
+}
+
+func (bs *BitString) checkValue(idx, size int) error {
+	if size < 1 || size > 64 {
+		return fmt.Errorf("invalid status size %d", size)
+	}
+	if idx < 0 {
+		return fmt.Errorf("bitstring index %d out of range", idx)
+	}
+	return bs.checkIndex((idx+1)*size - 1)
+}
+
+// ParseBitString decodes a compressed bitstring, decoding errors are returned by the bitstring methods.
+//
+// Deprecated: use DecodeBitString.
 func ParseBitString(compressed string) *BitString {
-	return &BitString{
-		compressed: compressed,
+	ret, err := DecodeBitString(compressed)
+	if err != nil {
+		return &BitString{err: err}
 	}
+	return ret
 }
 
+// Compressed returns the encoded bitstring.
+//
+// Deprecated: use Encode.
 func (bs *BitString) Compressed() string {
-	return bs.compressed
+	ret, _ := bs.Encode()
+	return ret
 }
 
 // GenBitstring
@@ -37,50 +207,46 @@ func (bs *BitString) Compressed() string {
 // 2) For each bit in bitstring, if there is a corresponding statusListIndex value in a revoked credential in issuedCredentials, set the bit to 1 (one), otherwise set the bit to 0 (zero).
 // 3) Generate a compressed bitstring by using the GZIP compression algorithm [RFC1952] on the bitstring and then base64-encoding [RFC4648] the result.
 // 4) Return the compressed bitstring.
+//
+// It returns nil when a statusListIndex is out of the bitstring, see GenPurposeBitstring.
 func GenBitstring(issuedCredentials []credential.Credential) *BitString {
-	bitStr := make([]byte, miniBytesLen)
+	bitStr, err := GenPurposeBitstring(issuedCredentials, StatusPurposeRevocation)
+	if err != nil {
+		return nil
+	}
+	return bitStr
+}
+
+// GenPurposeBitstring generates the bitstring of a status purpose, the bit of every StatusList2021Entry
+// of issuedCredentials with that purpose is set. It fails when a statusListIndex is out of the bitstring.
+func GenPurposeBitstring(issuedCredentials []credential.Credential, purpose string) (*BitString, error) {
+	bitStr := NewBitString(miniBytesLen * 8)
 	for _, v := range issuedCredentials {
-		// don't have status
-		if v.Status == nil {
-			continue
-		}
-		// not a StatusList2021Entry
-		if v.Status["type"] != StatusList2021Entry {
-			continue
-		}
-		//  Revocation purpose
-		if v.Status["statusPurpose"] != StatusPurposeRevocation {
+		entries, err := credential.GetStatusEntries(v.Status)
+		if err != nil {
 			continue
 		}
-		// must have index
-		if indexStr, ok := v.Status["statusListIndex"].(string); ok {
-			idx, err := strconv.ParseUint(indexStr, 10, 64)
-			if err != nil {
+		for _, entry := range entries {
+			// not a StatusList2021Entry
+			if entry["type"] != StatusList2021Entry {
 				continue
 			}
-
-			byteIdx := idx >> 3
-			subBitIdx := idx - (byteIdx << 3)
-
-			bitStr[byteIdx] = bitStr[byteIdx] | (1 << (8 - subBitIdx))
-
+			if entry["statusPurpose"] != purpose {
+				continue
+			}
+			// must have index
+			if indexStr, ok := entry["statusListIndex"].(string); ok {
+				idx, err := strconv.ParseUint(indexStr, 10, 64)
+				if err != nil {
+					continue
+				}
+				if err := bitStr.Set(int(idx)); err != nil {
+					return nil, fmt.Errorf("statusListIndex of credential %s: %w", v.Id, err)
+				}
+			}
 		}
 	}
-	// compressing
-	buf := bytes.Buffer{}
-	gr := gzip.NewWriter(&buf)
-	_, err := gr.Write(bitStr)
-	if err != nil {
-		return nil
-	}
-	if err = gr.Flush(); err != nil {
-		return nil
-	}
-
-	if err = gr.Close(); err != nil {
-		return nil
-	}
-	return ParseBitString(base64.RawStdEncoding.EncodeToString(buf.Bytes()))
+	return bitStr, nil
 }
 
 // ExpanBistring
@@ -94,26 +260,17 @@ func GenBitstring(issuedCredentials []credential.Credential) *BitString {
 // compressed bitstring and then expanding the output using the GZIP decompression algorithm [RFC1952].
 // 3) Return the uncompressed bitstring.
 func (bs *BitString) ExpanBistring() ([]byte, error) {
-	compressRaw, err := base64.RawStdEncoding.DecodeString(bs.compressed)
-	if err != nil {
-		return nil, err
+	if bs.err != nil {
+		return nil, bs.err
 	}
-	buf := bytes.NewBuffer(compressRaw)
-	gr, err := gzip.NewReader(buf)
-	if err != nil {
-		return nil, err
-	}
-	res := bytes.Buffer{}
-	_, err = res.ReadFrom(gr)
-	return res.Bytes(), err
+	return bs.bits, nil
 }
 
+// Check returns the bit at idx
 func (bs *BitString) Check(idx int) (bool, error) {
-	exp, err := bs.ExpanBistring()
+	ret, err := bs.Get(idx)
 	if err != nil {
 		return false, fmt.Errorf("on bit string check: %w", err)
 	}
-	byteIdx := idx >> 3
-	subBitIdx := idx - (byteIdx << 3)
-	return exp[byteIdx] == (1 << (8 - subBitIdx)), nil
+	return ret, nil
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/suutaku/go-vc/pkg/credential"
 	"github.com/suutaku/go-vc/test"
 )
@@ -28,7 +29,7 @@ func TestBitstring(t *testing.T) {
 	bitStr := GenBitstring(credList)
 	t.Logf("%s\n", bitStr.Compressed())
 	assert.NoError(t, err)
-	bitIdx, err := strconv.ParseInt(cred.Status["statusListIndex"].(string), 10, 64)
+	bitIdx, err := strconv.ParseInt(cred.Status.(map[string]interface{})["statusListIndex"].(string), 10, 64)
 	assert.NoError(t, err)
 
 	check, err := bitStr.Check(int(bitIdx))
@@ -38,4 +39,123 @@ func TestBitstring(t *testing.T) {
 	check2, err := bitStr.Check(int(bitIdx - 1))
 	assert.NoError(t, err)
 	assert.False(t, check2)
+	// out of range indexes are not skipped
+	cred.Status.(map[string]interface{})["statusListIndex"] = strconv.Itoa(miniBytesLen * 8)
+	_, err = GenPurposeBitstring(credList, StatusPurposeRevocation)
+	assert.ErrorContains(t, err, "statusListIndex of credential")
+	assert.Nil(t, GenBitstring(credList))
+}
+
+func TestBitStringSpecVector(t *testing.T) {
+	// https://www.w3.org/TR/vc-status-list/#example-example-statuslist2021credential
+	encoded := "H4sIAAAAAAAAA-3BMQEAAADCoPVPbQwfoAAAAAAAAAAAAAAAAAAAAIC3AYbSVKsAQAAA"
+	for _, v := range []string{encoded, "u" + encoded} {
+		bitStr, err := DecodeBitString(v)
+		require.NoError(t, err)
+		assert.Equal(t, 131072, bitStr.Len())
+		for _, idx := range []int{0, 94567, 131071} {
+			value, err := bitStr.Get(idx)
+			require.NoError(t, err)
+			assert.False(t, value)
+		}
+	}
+}
+
+func TestBitStringSetBitsVector(t *testing.T) {
+	// bits 1, 14, 94567 and 131071 of a 16KB list set left-most bit first, gzipped and base64url
+	// encoded by Python's gzip and base64 modules
+	encoded := "H4sIAAAAAAACA-3QAREAAAQEMC-Z_qnk4LYImy4AAAAAAAAAAAAAAJ6JAgAAAIBrsn2hDvcAQAAA"
+	set := map[int]bool{1: true, 14: true, 94567: true, 131071: true}
+	for _, v := range []string{encoded, "u" + encoded} {
+		bitStr, err := DecodeBitString(v)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x40, 0x02}, bitStr.Bytes()[:2])
+		for _, idx := range []int{0, 1, 2, 6, 9, 14, 15, 94566, 94567, 94568, 131070, 131071} {
+			value, err := bitStr.Get(idx)
+			require.NoError(t, err)
+			assert.Equal(t, set[idx], value, "bit %d", idx)
+		}
+	}
+
+	bitStr := NewBitString(131072)
+	for idx := range set {
+		require.NoError(t, bitStr.Set(idx))
+	}
+	expected, err := DecodeBitString(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, expected.Bytes(), bitStr.Bytes())
+}
+
+func TestBitStringOrder(t *testing.T) {
+	bitStr := NewBitString(16)
+	assert.NoError(t, bitStr.Set(0))
+	assert.Equal(t, []byte{0x80, 0x00}, bitStr.Bytes())
+	assert.NoError(t, bitStr.Set(7))
+	assert.NoError(t, bitStr.Set(8))
+	assert.Equal(t, []byte{0x81, 0x80}, bitStr.Bytes())
+
+	// neighbouring bits don't change the result
+	for idx, expected := range map[int]bool{0: true, 1: false, 6: false, 7: true, 8: true, 9: false} {
+		value, err := bitStr.Get(idx)
+		require.NoError(t, err)
+		assert.Equal(t, expected, value, "bit %d", idx)
+	}
+	assert.NoError(t, bitStr.Clear(7))
+	assert.Equal(t, []byte{0x80, 0x80}, bitStr.Bytes())
+
+	assert.Error(t, bitStr.Set(16))
+	assert.Error(t, bitStr.Clear(-1))
+	_, err := bitStr.Get(16)
+	assert.Error(t, err)
+}
+
+func TestBitStringRoundTrip(t *testing.T) {
+	bitStr := NewBitString(miniBytesLen * 8)
+	assert.NoError(t, bitStr.Set(94567))
+	encoded, err := bitStr.Encode()
+	require.NoError(t, err)
+	multibase, err := bitStr.EncodeMultibase()
+	require.NoError(t, err)
+	assert.Equal(t, "u"+encoded, multibase)
+
+	for _, v := range []string{encoded, multibase} {
+		decoded, err := DecodeBitString(v)
+		require.NoError(t, err)
+		assert.Equal(t, bitStr.Bytes(), decoded.Bytes())
+	}
+	_, err = DecodeBitString("not a bitstring")
+	assert.Error(t, err)
+
+	// a small encoding of a bitstring over the limit
+	large, err := NewBitString((maxBitStringBytes + 1) * 8).Encode()
+	require.NoError(t, err)
+	assert.Less(t, len(large), 64<<10)
+	_, err = DecodeBitString(large)
+	assert.ErrorContains(t, err, "exceeds")
+}
+
+func TestBitStringValue(t *testing.T) {
+	bitStr := NewBitString(16)
+	// entry 1 of 2 bits is bits 2 and 3
+	assert.NoError(t, bitStr.SetValue(1, 2, 2))
+	assert.Equal(t, []byte{0x20, 0x00}, bitStr.Bytes())
+	assert.NoError(t, bitStr.SetValue(3, 4, 0xb))
+	assert.Equal(t, []byte{0x20, 0x0b}, bitStr.Bytes())
+
+	value, err := bitStr.GetValue(1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), value)
+	value, err = bitStr.GetValue(3, 4)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0xb), value)
+	value, err = bitStr.GetValue(0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), value)
+
+	assert.NoError(t, bitStr.SetValue(1, 2, 0))
+	assert.Equal(t, []byte{0x00, 0x0b}, bitStr.Bytes())
+	assert.Error(t, bitStr.SetValue(0, 2, 4))
+	assert.Error(t, bitStr.SetValue(4, 4, 1))
+	_, err = bitStr.GetValue(0, 0)
+	assert.Error(t, err)
 }
@@ -0,0 +1,142 @@
+package status
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/suutaku/go-vc/pkg/credential"
+)
+
+// Result is the status of a credentialStatus entry
+type Result struct {
+	Purpose string
+	Entry   *StatusEntry
+	// Set reports whether the status value is not 0, e.g. the credential is revoked
+	Set bool
+	// Value is the statusSize bits value of the entry
+	Value uint64
+	// Message is the statusMessage of Value, if any
+	Message string
+}
+
+// CheckStatus reads the status of a StatusList2021Entry or BitstringStatusListEntry in its status list credential.
+// https://w3c.github.io/vc-status-list-2021/#validate-algorithm
+// https://www.w3.org/TR/vc-bitstring-status-list/#validate-algorithm
+func CheckStatus(entry map[string]interface{}, statusList *credential.Credential) (*Result, error) {
+	se, err := ParseStatusEntry(entry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status entry: %w", err)
+	}
+	credSubject, ok := statusList.Subject.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("status credential don't have credentialSubject item")
+	}
+	if se.Type == BitstringStatusListEntry && credSubject["type"] != BitstringStatusList {
+		return nil, fmt.Errorf("status list of a %s is not a %s", BitstringStatusListEntry, BitstringStatusList)
+	}
+	if !hasPurpose(credSubject["statusPurpose"], se.Purpose) {
+		return nil, fmt.Errorf("status purpose not matched, list %v, entry %s", credSubject["statusPurpose"], se.Purpose)
+	}
+	idx, err := strconv.ParseInt(se.ListIndex, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invlaid status list index %s", se.ListIndex)
+	}
+	size := se.StatusSize
+	if size == 0 {
+		// earlier drafts of BitstringStatusList put statusSize in the list
+		if v, ok := credSubject["statusSize"].(float64); ok {
+			size = int(v)
+		} else {
+			size = 1
+		}
+	}
+	messages := se.StatusMessage
+	if len(messages) == 0 {
+		if v, ok := credSubject["statusMessage"]; ok {
+			if messages, err = parseStatusMessages(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if size > 1 && len(messages) == 0 {
+		return nil, fmt.Errorf("statusMessage is required when statusSize is %d", size)
+	}
+	encodedList, ok := credSubject["encodedList"].(string)
+	if !ok {
+		return nil, fmt.Errorf("status credential don't have encodedList item")
+	}
+	bitStr, err := DecodeBitString(encodedList)
+	if err != nil {
+		return nil, err
+	}
+	value, err := bitStr.GetValue(int(idx), size)
+	if err != nil {
+		return nil, err
+	}
+	ret := &Result{Purpose: se.Purpose, Entry: se, Set: value != 0, Value: value}
+	for _, msg := range messages {
+		if v, err := strconv.ParseUint(msg.Status, 0, 64); err == nil && v == value {
+			ret.Message = msg.Message
+			break
+		}
+	}
+	return ret, nil
+}
+
+// ListTTL returns the ttl of a BitstringStatusList, 0 when it is not set
+func ListTTL(statusList *credential.Credential) time.Duration {
+	credSubject, ok := statusList.Subject.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	switch ttl := credSubject["ttl"].(type) {
+	case float64:
+		return time.Duration(ttl) * time.Millisecond
+	case int:
+		return time.Duration(ttl) * time.Millisecond
+	case int64:
+		return time.Duration(ttl) * time.Millisecond
+	}
+	return 0
+}
+
+// SetPurposes returns the purposes of results whose status is set, e.g. revocation or suspension
+func SetPurposes(results []*Result) []string {
+	ret := make([]string, 0)
+	for _, res := range results {
+		if res.Set {
+			ret = append(ret, res.Purpose)
+		}
+	}
+	return ret
+}
+
+// hasPurpose checks statusPurpose of a list, a BitstringStatusList may have several purposes
+func hasPurpose(listPurpose interface{}, purpose string) bool {
+	switch p := listPurpose.(type) {
+	case string:
+		return p == purpose
+	case []interface{}:
+		for _, v := range p {
+			if v == purpose {
+				return true
+			}
+		}
+	case []string:
+		for _, v := range p {
+			if v == purpose {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parseStatusMessages(raw interface{}) ([]StatusMessage, error) {
+	entry, err := ParseStatusEntry(map[string]interface{}{"statusMessage": raw})
+	if err != nil {
+		return nil, fmt.Errorf("invalid statusMessage: %w", err)
+	}
+	return entry.StatusMessage, nil
+}
@@ -0,0 +1,426 @@
+package status
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/suutaku/go-vc/pkg/common"
+	"github.com/suutaku/go-vc/pkg/credential"
+)
+
+// DefaultListSize is the number of entries of a status list, the 16KB minimum of the spec
+const DefaultListSize = miniBytesLen * 8
+
+// SignFunc adds a proof to the status list credential
+type SignFunc func(cred *credential.Credential) (*credential.Credential, error)
+
+// StatusListManager allocates status list entries for issued credentials, records their status
+// in a Storage and generates the StatusList2021Credential, or BitstringStatusListCredential, of each list.
+// A new list is started when the current list is full. It is safe for concurrent use.
+type StatusListManager struct {
+	mu      sync.Mutex
+	storage Storage
+	baseURL string
+	options *managerOpts
+}
+
+// NewStatusListManager creates a manager whose lists are published at listBaseURL/<sequence>
+func NewStatusListManager(storage Storage, listBaseURL string, opts ...ManagerOption) *StatusListManager {
+	return &StatusListManager{
+		storage: storage,
+		baseURL: strings.TrimSuffix(listBaseURL, "/"),
+		options: prepareManagerOpts(opts),
+	}
+}
+
+// Allocate reserves a random unused index of the current list and returns the status entry to
+// put in the credential's credentialStatus
+func (m *StatusListManager) Allocate() (*StatusEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, err := m.currentList()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := m.randomFreeIndex(rec)
+	if err != nil {
+		return nil, err
+	}
+	bitStringOf(rec.Allocated, rec.Size).Set(idx)
+	rec.Used++
+	if err := m.storage.SaveList(rec); err != nil {
+		return nil, err
+	}
+	entry := &StatusEntry{
+		ID:         fmt.Sprintf("%s#%d", rec.ID, idx),
+		Type:       StatusList2021Entry,
+		Purpose:    rec.Purpose,
+		ListIndex:  strconv.Itoa(idx),
+		Credential: rec.ID,
+	}
+	if rec.Type == BitstringStatusList {
+		entry.Type = BitstringStatusListEntry
+		if rec.StatusSize > 1 {
+			entry.StatusSize = rec.StatusSize
+		}
+		entry.StatusMessage = m.options.messages
+	}
+	return entry, nil
+}
+
+// Release frees an allocated entry whose credential was not issued, its index is unallocated again
+func (m *StatusListManager) Release(entry *StatusEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, idx, err := m.entryList(entry)
+	if err != nil {
+		return err
+	}
+	statusBits, size := rec.statusBits()
+	if err := statusBits.SetValue(idx, size, 0); err != nil {
+		return err
+	}
+	if err := bitStringOf(rec.Allocated, rec.Size).Clear(idx); err != nil {
+		return err
+	}
+	rec.Used--
+	return m.storage.SaveList(rec)
+}
+
+// SetStatus sets (true) or clears (false) the status bit of an allocated entry,
+// e.g. revokes the credential of an entry of a revocation list
+func (m *StatusListManager) SetStatus(entry *StatusEntry, value bool) error {
+	if value {
+		return m.SetStatusValue(entry, 1)
+	}
+	return m.SetStatusValue(entry, 0)
+}
+
+// SetStatusValue sets the statusSize bits status value of an allocated entry
+func (m *StatusListManager) SetStatusValue(entry *StatusEntry, value uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, idx, err := m.entryList(entry)
+	if err != nil {
+		return err
+	}
+	statusBits, size := rec.statusBits()
+	if err := statusBits.SetValue(idx, size, value); err != nil {
+		return err
+	}
+	return m.storage.SaveList(rec)
+}
+
+// Revoke sets the status bit of an entry of a revocation list
+func (m *StatusListManager) Revoke(entry *StatusEntry) error {
+	return m.setPurposeStatus(entry, StatusPurposeRevocation, true)
+}
+
+// Suspend sets the status bit of an entry of a suspension list
+func (m *StatusListManager) Suspend(entry *StatusEntry) error {
+	return m.setPurposeStatus(entry, StatusPurposeSuspension, true)
+}
+
+// Unsuspend clears the status bit of an entry of a suspension list
+func (m *StatusListManager) Unsuspend(entry *StatusEntry) error {
+	return m.setPurposeStatus(entry, StatusPurposeSuspension, false)
+}
+
+func (m *StatusListManager) setPurposeStatus(entry *StatusEntry, purpose string, value bool) error {
+	if entry.Purpose != purpose {
+		return fmt.Errorf("status entry purpose is %s, not %s", entry.Purpose, purpose)
+	}
+	return m.SetStatus(entry, value)
+}
+
+// Status returns the status bit of an allocated entry
+func (m *StatusListManager) Status(entry *StatusEntry) (bool, error) {
+	value, err := m.StatusValue(entry)
+	return value != 0, err
+}
+
+// StatusValue returns the statusSize bits status value of an allocated entry
+func (m *StatusListManager) StatusValue(entry *StatusEntry) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, idx, err := m.entryList(entry)
+	if err != nil {
+		return 0, err
+	}
+	statusBits, size := rec.statusBits()
+	return statusBits.GetValue(idx, size)
+}
+
+// Purpose returns the status purpose of the lists of the manager
+func (m *StatusListManager) Purpose() string {
+	return m.options.purpose
+}
+
+// Lists returns the ids of the status list credentials of the manager's purpose
+func (m *StatusListManager) Lists() ([]string, error) {
+	recs, err := m.storage.Lists(m.options.purpose)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]string, len(recs))
+	for i, rec := range recs {
+		ret[i] = rec.ID
+	}
+	return ret, nil
+}
+
+// StatusCredential generates the status list credential of list id from the stored status,
+// signed when the manager has a SignFunc
+func (m *StatusListManager) StatusCredential(id string) (*credential.Credential, error) {
+	m.mu.Lock()
+	rec, err := m.storage.LoadList(id)
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	statusBits, _ := rec.statusBits()
+	if rec.Type == BitstringStatusList {
+		return m.bitstringStatusCredential(rec, statusBits)
+	}
+	encodedList, err := statusBits.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate bit string: %w", err)
+	}
+	cred := &credential.Credential{
+		Context: credential.Contexts{
+			common.DefaultVCJsonLDContext,
+			common.DefaultBbsJsonLDContext,
+			common.DefaultStatusVCJsonLDContext,
+		},
+		Id: rec.ID,
+		Type: []string{
+			common.DefaultVCJsonLDContextTypeVC,
+			common.DefaultVCJsonLDContextTypeSC,
+		},
+		Issuer:       credential.NewIssuer(m.options.issuer),
+		IssuanceDate: m.issuanceDate(),
+		Subject: map[string]interface{}{
+			"id":            rec.ID + "#list",
+			"type":          StatusList2021,
+			"statusPurpose": rec.Purpose,
+			"encodedList":   encodedList,
+		},
+	}
+	if m.options.sign == nil {
+		return cred, nil
+	}
+	return m.options.sign(cred)
+}
+
+// bitstringStatusCredential generates the BitstringStatusListCredential of a list
+// https://www.w3.org/TR/vc-bitstring-status-list/#bitstringstatuslistcredential
+func (m *StatusListManager) bitstringStatusCredential(rec *ListRecord, statusBits *BitString) (*credential.Credential, error) {
+	encodedList, err := statusBits.EncodeMultibase()
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate bit string: %w", err)
+	}
+	subject := map[string]interface{}{
+		"id":            rec.ID + "#list",
+		"type":          BitstringStatusList,
+		"statusPurpose": rec.Purpose,
+		"encodedList":   encodedList,
+	}
+	if m.options.ttl > 0 {
+		subject["ttl"] = m.options.ttl.Milliseconds()
+	}
+	cred := &credential.Credential{
+		Context: credential.Contexts{common.VC2JsonLDContext},
+		Id:      rec.ID,
+		Type: []string{
+			common.DefaultVCJsonLDContextTypeVC,
+			common.VCJsonLDContextTypeBSC,
+		},
+		Issuer:    credential.NewIssuer(m.options.issuer),
+		ValidFrom: m.issuanceDate(),
+		Subject:   subject,
+	}
+	if m.options.sign == nil {
+		return cred, nil
+	}
+	return m.options.sign(cred)
+}
+
+// issuanceDate returns the current time of the manager clock
+func (m *StatusListManager) issuanceDate() *common.FormatedTime {
+	return &common.FormatedTime{Time: m.options.clock().UTC().Truncate(time.Second)}
+}
+
+// currentList returns the last list which is not full, a new list is created if there is none
+func (m *StatusListManager) currentList() (*ListRecord, error) {
+	recs, err := m.storage.Lists(m.options.purpose)
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) > 0 && !recs[len(recs)-1].Full() {
+		return recs[len(recs)-1], nil
+	}
+	sequence := len(recs)
+	if len(recs) > 0 {
+		sequence = recs[len(recs)-1].Sequence + 1
+	}
+	rec := &ListRecord{
+		ID:        fmt.Sprintf("%s/%d", m.baseURL, sequence),
+		Purpose:   m.options.purpose,
+		Sequence:  sequence,
+		Size:      m.options.size,
+		Allocated: make([]byte, m.options.size/8),
+		Status:    make([]byte, m.options.size/8),
+	}
+	if m.options.bitstring {
+		if m.options.statusSize > 1 && len(m.options.messages) != 1<<m.options.statusSize {
+			return nil, fmt.Errorf("status size %d requires %d status messages", m.options.statusSize, 1<<m.options.statusSize)
+		}
+		rec.Type = BitstringStatusList
+		rec.StatusSize = m.options.statusSize
+		rec.Status = make([]byte, m.options.size*m.options.statusSize/8)
+	}
+	return rec, nil
+}
+
+// randomFreeIndex picks a random index, the next free index is used when it is taken
+func (m *StatusListManager) randomFreeIndex(rec *ListRecord) (int, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, err
+	}
+	start := int(binary.BigEndian.Uint64(b[:]) % uint64(rec.Size))
+	allocated := bitStringOf(rec.Allocated, rec.Size)
+	for i := 0; i < rec.Size; i++ {
+		idx := (start + i) % rec.Size
+		if taken, _ := allocated.Get(idx); !taken {
+			return idx, nil
+		}
+	}
+	return 0, fmt.Errorf("status list %s is full", rec.ID)
+}
+
+// entryList loads the list of an entry and checks the entry was allocated
+func (m *StatusListManager) entryList(entry *StatusEntry) (*ListRecord, int, error) {
+	rec, err := m.storage.LoadList(entry.Credential)
+	if errors.Is(err, ErrListNotFound) {
+		return nil, 0, fmt.Errorf("status list %s is not managed", entry.Credential)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if entry.Purpose != rec.Purpose {
+		return nil, 0, fmt.Errorf("status purpose not matched, list %s, entry %s", rec.Purpose, entry.Purpose)
+	}
+	idx, err := strconv.Atoi(entry.ListIndex)
+	if err != nil || idx < 0 || idx >= rec.Size {
+		return nil, 0, fmt.Errorf("invalid status list index %s", entry.ListIndex)
+	}
+	if allocated, _ := bitStringOf(rec.Allocated, rec.Size).Get(idx); !allocated {
+		return nil, 0, fmt.Errorf("status list index %d is not allocated", idx)
+	}
+	return rec, idx, nil
+}
+
+// managerOpts holds options for StatusListManager.
+type managerOpts struct {
+	purpose    string
+	size       int
+	issuer     string
+	sign       SignFunc
+	bitstring  bool
+	statusSize int
+	messages   []StatusMessage
+	ttl        time.Duration
+	clock      func() time.Time
+}
+
+// ManagerOption are the options for StatusListManager.
+type ManagerOption func(opts *managerOpts)
+
+// WithStatusPurpose option sets the statusPurpose of the managed lists, revocation by default.
+func WithStatusPurpose(purpose string) ManagerOption {
+	return func(opts *managerOpts) {
+		opts.purpose = purpose
+	}
+}
+
+// WithListSize option sets the number of entries of new lists, a multiple of 8.
+func WithListSize(size int) ManagerOption {
+	return func(opts *managerOpts) {
+		opts.size = size
+	}
+}
+
+// WithIssuer option sets the issuer of the status list credentials.
+func WithIssuer(did string) ManagerOption {
+	return func(opts *managerOpts) {
+		opts.issuer = did
+	}
+}
+
+// WithSigner option signs the generated status list credentials.
+func WithSigner(sign SignFunc) ManagerOption {
+	return func(opts *managerOpts) {
+		opts.sign = sign
+	}
+}
+
+// WithBitstringStatusList option creates BitstringStatusList lists instead of StatusList2021 lists.
+func WithBitstringStatusList() ManagerOption {
+	return func(opts *managerOpts) {
+		opts.bitstring = true
+	}
+}
+
+// WithStatusSize option sets the number of bits of a BitstringStatusList status value, 1 by default.
+func WithStatusSize(size int) ManagerOption {
+	return func(opts *managerOpts) {
+		opts.statusSize = size
+	}
+}
+
+// WithStatusMessages option sets the statusMessage of BitstringStatusList entries,
+// one message per status value is required when the status size is greater than 1.
+func WithStatusMessages(messages ...StatusMessage) ManagerOption {
+	return func(opts *managerOpts) {
+		opts.messages = messages
+	}
+}
+
+// WithTTL option sets the ttl of BitstringStatusList credentials.
+func WithTTL(ttl time.Duration) ManagerOption {
+	return func(opts *managerOpts) {
+		opts.ttl = ttl
+	}
+}
+
+// WithIssuanceClock option sets the clock dating the status list credentials, time.Now by default.
+func WithIssuanceClock(clock func() time.Time) ManagerOption {
+	return func(opts *managerOpts) {
+		opts.clock = clock
+	}
+}
+
+func prepareManagerOpts(opts []ManagerOption) *managerOpts {
+	ret := &managerOpts{
+		purpose: StatusPurposeRevocation,
+		size:    DefaultListSize,
+		clock:   time.Now,
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	if ret.size <= 0 || ret.size%8 != 0 {
+		ret.size = DefaultListSize
+	}
+	if ret.statusSize < 1 || ret.statusSize > 64 {
+		ret.statusSize = 1
+	}
+	return ret
+}
@@ -0,0 +1,195 @@
+package status
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suutaku/go-vc/pkg/credential"
+)
+
+func TestStatusListManager(t *testing.T) {
+	fileStorage, err := NewFileStorage(t.TempDir())
+	require.NoError(t, err)
+	for name, storage := range map[string]Storage{"memory": NewMemoryStorage(), "file": fileStorage} {
+		t.Run(name, func(t *testing.T) {
+			m := NewStatusListManager(storage, "https://example.com/status/", WithListSize(16), WithIssuer("did:example:12345"))
+
+			// 16 entries fill the first list, the 17th rolls over to a new list
+			seen := make(map[string]bool)
+			var entries []*StatusEntry
+			for i := 0; i < 17; i++ {
+				entry, err := m.Allocate()
+				require.NoError(t, err)
+				assert.False(t, seen[entry.ID], "duplicated entry %s", entry.ID)
+				seen[entry.ID] = true
+				entries = append(entries, entry)
+			}
+			for _, entry := range entries[:16] {
+				assert.Equal(t, "https://example.com/status/0", entry.Credential)
+			}
+			assert.Equal(t, "https://example.com/status/1", entries[16].Credential)
+			lists, err := m.Lists()
+			require.NoError(t, err)
+			assert.Equal(t, []string{"https://example.com/status/0", "https://example.com/status/1"}, lists)
+
+			revoked := entries[3]
+			assert.NoError(t, m.SetStatus(revoked, true))
+			value, err := m.Status(revoked)
+			require.NoError(t, err)
+			assert.True(t, value)
+			value, err = m.Status(entries[4])
+			require.NoError(t, err)
+			assert.False(t, value)
+
+			cred, err := m.StatusCredential(revoked.Credential)
+			require.NoError(t, err)
+			assert.Equal(t, "did:example:12345", cred.IssuerID())
+			subject := cred.Subject.(map[string]interface{})
+			assert.Equal(t, StatusPurposeRevocation, subject["statusPurpose"])
+			bits, err := DecodeBitString(subject["encodedList"].(string))
+			require.NoError(t, err)
+			idx, err := strconv.Atoi(revoked.ListIndex)
+			require.NoError(t, err)
+			for i := 0; i < 16; i++ {
+				value, err := bits.Get(i)
+				require.NoError(t, err)
+				assert.Equal(t, i == idx, value, "bit %d", i)
+			}
+
+			assert.NoError(t, m.SetStatus(revoked, false))
+			value, err = m.Status(revoked)
+			require.NoError(t, err)
+			assert.False(t, value)
+
+			// entries of other purposes or lists are rejected
+			suspension := *revoked
+			suspension.Purpose = StatusPurposeSuspension
+			assert.Error(t, m.SetStatus(&suspension, true))
+			unknown := *revoked
+			unknown.Credential = "https://example.com/status/9"
+			assert.Error(t, m.SetStatus(&unknown, true))
+
+			// released entries are no longer allocated
+			assert.NoError(t, m.SetStatus(entries[16], true))
+			assert.NoError(t, m.Release(entries[16]))
+			_, err = m.Status(entries[16])
+			assert.ErrorContains(t, err, "is not allocated")
+			assert.Error(t, m.Release(entries[16]))
+		})
+	}
+}
+
+func TestFileStoragePersistence(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := NewFileStorage(dir)
+	require.NoError(t, err)
+	entry, err := NewStatusListManager(storage, "https://example.com/status").Allocate()
+	require.NoError(t, err)
+	assert.NoError(t, NewStatusListManager(storage, "https://example.com/status").SetStatus(entry, true))
+
+	reopened, err := NewFileStorage(dir)
+	require.NoError(t, err)
+	m := NewStatusListManager(reopened, "https://example.com/status")
+	value, err := m.Status(entry)
+	require.NoError(t, err)
+	assert.True(t, value)
+	next, err := m.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, entry.Credential, next.Credential)
+	assert.NotEqual(t, entry.ListIndex, next.ListIndex)
+
+	parsed, err := ParseStatusEntry(entry.ToMap())
+	require.NoError(t, err)
+	assert.Equal(t, entry, parsed)
+}
+
+func TestManagerSuspension(t *testing.T) {
+	m := NewStatusListManager(NewMemoryStorage(), "https://example.com/status/suspension",
+		WithStatusPurpose(StatusPurposeSuspension), WithListSize(64))
+	entry, err := m.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, StatusPurposeSuspension, entry.Purpose)
+	assert.Error(t, m.Revoke(entry))
+
+	assert.NoError(t, m.Suspend(entry))
+	suspended, err := m.Status(entry)
+	require.NoError(t, err)
+	assert.True(t, suspended)
+
+	list, err := m.StatusCredential(entry.Credential)
+	require.NoError(t, err)
+	res, err := CheckStatus(entry.ToMap(), list)
+	require.NoError(t, err)
+	assert.True(t, res.Set)
+	assert.Equal(t, []string{StatusPurposeSuspension}, SetPurposes([]*Result{res}))
+
+	assert.NoError(t, m.Unsuspend(entry))
+	list, err = m.StatusCredential(entry.Credential)
+	require.NoError(t, err)
+	res, err = CheckStatus(entry.ToMap(), list)
+	require.NoError(t, err)
+	assert.False(t, res.Set)
+}
+
+func TestBitstringStatusList(t *testing.T) {
+	messages := []StatusMessage{
+		{Status: "0x0", Message: "pending_review"},
+		{Status: "0x1", Message: "accepted"},
+		{Status: "0x2", Message: "rejected"},
+		{Status: "0x3", Message: "undefined"},
+	}
+	_, err := NewStatusListManager(NewMemoryStorage(), "https://example.com/status/message",
+		WithBitstringStatusList(), WithStatusSize(2)).Allocate()
+	assert.Error(t, err, "status messages are required")
+
+	m := NewStatusListManager(NewMemoryStorage(), "https://example.com/status/message", WithBitstringStatusList(),
+		WithStatusPurpose(StatusPurposeMessage), WithStatusSize(2), WithStatusMessages(messages...),
+		WithListSize(64), WithTTL(5*time.Minute))
+	entry, err := m.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, BitstringStatusListEntry, entry.Type)
+	assert.Equal(t, 2, entry.StatusSize)
+	assert.Equal(t, messages, entry.StatusMessage)
+	assert.NoError(t, m.SetStatusValue(entry, 2))
+	assert.Error(t, m.SetStatusValue(entry, 4))
+
+	list, err := m.StatusCredential(entry.Credential)
+	require.NoError(t, err)
+	assert.Equal(t, credential.Contexts{"https://www.w3.org/ns/credentials/v2"}, list.Context)
+	subject := list.Subject.(map[string]interface{})
+	assert.Equal(t, BitstringStatusList, subject["type"])
+	assert.True(t, strings.HasPrefix(subject["encodedList"].(string), "u"))
+	assert.Equal(t, 5*time.Minute, ListTTL(list))
+
+	// round trip through JSON as a verifier would read it
+	parsed := credential.NewCredential()
+	assert.NoError(t, parsed.FromBytes(list.ToBytes()))
+	res, err := CheckStatus(entry.ToMap(), parsed)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), res.Value)
+	assert.Equal(t, "rejected", res.Message)
+	assert.True(t, res.Set)
+	assert.Equal(t, 5*time.Minute, ListTTL(parsed))
+
+	// a BitstringStatusListEntry can't be checked in a StatusList2021 list
+	legacy := NewStatusListManager(NewMemoryStorage(), "https://example.com/status/legacy",
+		WithStatusPurpose(StatusPurposeMessage), WithListSize(64))
+	legacyEntry, err := legacy.Allocate()
+	require.NoError(t, err)
+	legacyList, err := legacy.StatusCredential(legacyEntry.Credential)
+	require.NoError(t, err)
+	_, err = CheckStatus(entry.ToMap(), legacyList)
+	assert.Error(t, err)
+	res, err = CheckStatus(legacyEntry.ToMap(), legacyList)
+	require.NoError(t, err)
+	assert.False(t, res.Set)
+
+	noMessages := entry.ToMap()
+	delete(noMessages, "statusMessage")
+	_, err = CheckStatus(noMessages, parsed)
+	assert.Error(t, err, "statusMessage is required")
+}
@@ -0,0 +1,225 @@
+package status
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/suutaku/go-vc/pkg/credential"
+)
+
+const (
+	// maxListBytes limits the size of a fetched status list credential
+	maxListBytes = 8 << 20
+	// defaultCacheSize is the default number of cached status lists
+	defaultCacheSize = 256
+)
+
+// StatusListResolver dereferences the statusListCredential of a status entry
+type StatusListResolver interface {
+	Resolve(url string) (*credential.Credential, error)
+}
+
+// VerifyFunc verifies the proof of a status list credential
+type VerifyFunc func(cred *credential.Credential) error
+
+// HTTPStatusListResolver fetches status list credentials over HTTP(S), their proof must be verified by the
+// verifier of WithListVerifier. Lists are cached for their ttl, or as long as the HTTP cache headers allow
+// when they have none, but not after their validity period. It is safe for concurrent use.
+type HTTPStatusListResolver struct {
+	mu      sync.Mutex
+	cache   map[string]*list.Element
+	lru     *list.List
+	options *resolverOpts
+}
+
+type cachedList struct {
+	url     string
+	cred    *credential.Credential
+	expires time.Time
+}
+
+func NewHTTPStatusListResolver(opts ...ResolverOption) *HTTPStatusListResolver {
+	return &HTTPStatusListResolver{
+		cache:   make(map[string]*list.Element),
+		lru:     list.New(),
+		options: prepareResolverOpts(opts),
+	}
+}
+
+// Resolve returns the status list credential of url, its proof and validity period are checked
+func (r *HTTPStatusListResolver) Resolve(url string) (*credential.Credential, error) {
+	now := r.options.clock()
+	if cred, ok := r.cached(url, now); ok {
+		return cred, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status list url %s: %w", url, err)
+	}
+	req.Header.Set("Accept", "application/vc+ld+json, application/ld+json, application/json")
+	resp, err := r.options.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch status list %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot fetch status list %s: %s", url, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxListBytes))
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch status list %s: %w", url, err)
+	}
+	cred := credential.NewCredential()
+	if err := json.Unmarshal(b, cred); err != nil {
+		return nil, fmt.Errorf("invalid status list %s: %w", url, err)
+	}
+	if err := r.checkList(url, cred, now); err != nil {
+		return nil, err
+	}
+
+	expires := cacheExpiry(resp.Header, ListTTL(cred), now)
+	if _, until := cred.ValidityPeriod(); until != nil && until.Before(expires) {
+		expires = *until
+	}
+	r.store(url, cred, now, expires)
+	return cred, nil
+}
+
+// cached returns the cached list of url if it is neither expired nor out of its validity period
+func (r *HTTPStatusListResolver) cached(url string, now time.Time) (*credential.Credential, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	elem, ok := r.cache[url]
+	if !ok {
+		return nil, false
+	}
+	cached := elem.Value.(*cachedList)
+	if !now.Before(cached.expires) || cached.cred.CheckValidity(now, 0) != nil {
+		r.lru.Remove(elem)
+		delete(r.cache, url)
+		return nil, false
+	}
+	r.lru.MoveToFront(elem)
+	return cached.cred, true
+}
+
+// store caches the list of url until expires, evicting the least recently used lists
+func (r *HTTPStatusListResolver) store(url string, cred *credential.Credential, now, expires time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if elem, ok := r.cache[url]; ok {
+		r.lru.Remove(elem)
+		delete(r.cache, url)
+	}
+	if !expires.After(now) || r.options.cacheSize <= 0 {
+		return
+	}
+	r.cache[url] = r.lru.PushFront(&cachedList{url: url, cred: cred, expires: expires})
+	for r.lru.Len() > r.options.cacheSize {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.cache, oldest.Value.(*cachedList).url)
+	}
+}
+
+// checkList checks the id, validity period and proof of a status list credential
+func (r *HTTPStatusListResolver) checkList(url string, cred *credential.Credential, now time.Time) error {
+	if cred.Id != "" && cred.Id != url {
+		return fmt.Errorf("status list id %s not matched %s", cred.Id, url)
+	}
+	if err := cred.CheckValidity(now, 0); err != nil {
+		return fmt.Errorf("status list %s: %w", url, err)
+	}
+	if r.options.verify == nil {
+		return fmt.Errorf("cannot verify status list %s proof: no list verifier", url)
+	}
+	if err := r.options.verify(cred); err != nil {
+		return fmt.Errorf("invalid status list %s proof: %w", url, err)
+	}
+	return nil
+}
+
+// cacheExpiry returns until when a list may be cached, the list ttl is preferred to Cache-Control and Expires
+func cacheExpiry(header http.Header, ttl time.Duration, now time.Time) time.Time {
+	var maxAge time.Duration = -1
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store" || directive == "no-cache":
+			return now
+		case strings.HasPrefix(directive, "max-age="):
+			if seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+				maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	if ttl > 0 {
+		return now.Add(ttl)
+	}
+	if maxAge >= 0 {
+		return now.Add(maxAge)
+	}
+	if expires, err := http.ParseTime(header.Get("Expires")); err == nil {
+		return expires
+	}
+	return now
+}
+
+// resolverOpts holds options for HTTPStatusListResolver.
+type resolverOpts struct {
+	client    *http.Client
+	verify    VerifyFunc
+	clock     func() time.Time
+	cacheSize int
+}
+
+// ResolverOption are the options for HTTPStatusListResolver.
+type ResolverOption func(opts *resolverOpts)
+
+// WithHTTPClient option sets the client fetching status lists, http.DefaultClient by default.
+func WithHTTPClient(client *http.Client) ResolverOption {
+	return func(opts *resolverOpts) {
+		opts.client = client
+	}
+}
+
+// WithListVerifier option verifies the proof of fetched status lists, lists are rejected without verifier.
+func WithListVerifier(verify VerifyFunc) ResolverOption {
+	return func(opts *resolverOpts) {
+		opts.verify = verify
+	}
+}
+
+// WithClock option sets the current time of validity and cache checks.
+func WithClock(clock func() time.Time) ResolverOption {
+	return func(opts *resolverOpts) {
+		opts.clock = clock
+	}
+}
+
+// WithCacheSize option sets the maximum number of cached lists, 256 by default, 0 disables the cache.
+func WithCacheSize(size int) ResolverOption {
+	return func(opts *resolverOpts) {
+		opts.cacheSize = size
+	}
+}
+
+func prepareResolverOpts(opts []ResolverOption) *resolverOpts {
+	ret := &resolverOpts{
+		client:    http.DefaultClient,
+		clock:     time.Now,
+		cacheSize: defaultCacheSize,
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
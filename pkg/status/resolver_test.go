@@ -0,0 +1,159 @@
+package status
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suutaku/go-vc/pkg/common"
+	"github.com/suutaku/go-vc/pkg/credential"
+)
+
+func TestHTTPStatusListResolver(t *testing.T) {
+	var (
+		hits         int
+		cacheControl string
+		list         *credential.Credential
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+		w.Write(list.ToBytes())
+	}))
+	defer ts.Close()
+
+	now := time.Now()
+	clock := func() time.Time { return now }
+	// proofs of the test lists are not checked
+	anyProof := WithListVerifier(func(cred *credential.Credential) error { return nil })
+	m := NewStatusListManager(NewMemoryStorage(), ts.URL+"/status", WithListSize(64), WithIssuer("did:example:12345"),
+		WithBitstringStatusList(), WithTTL(time.Minute))
+	entry, err := m.Allocate()
+	require.NoError(t, err)
+	assert.NoError(t, m.SetStatus(entry, true))
+	list, err = m.StatusCredential(entry.Credential)
+	require.NoError(t, err)
+
+	res := NewHTTPStatusListResolver(WithClock(clock), anyProof)
+	for i := 0; i < 2; i++ {
+		cred, err := res.Resolve(entry.Credential)
+		require.NoError(t, err)
+		result, err := CheckStatus(entry.ToMap(), cred)
+		require.NoError(t, err)
+		assert.True(t, result.Set)
+	}
+	assert.Equal(t, 1, hits, "list is cached for its ttl")
+	now = now.Add(2 * time.Minute)
+	_, err = res.Resolve(entry.Credential)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, hits)
+
+	// HTTP cache headers without ttl
+	m = NewStatusListManager(NewMemoryStorage(), ts.URL+"/status", WithListSize(64))
+	entry, err = m.Allocate()
+	require.NoError(t, err)
+	list, err = m.StatusCredential(entry.Credential)
+	require.NoError(t, err)
+	res = NewHTTPStatusListResolver(WithClock(clock), anyProof)
+	cacheControl = "public, max-age=30"
+	hits = 0
+	for i := 0; i < 2; i++ {
+		_, err = res.Resolve(entry.Credential)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, hits)
+	cacheControl = "no-store"
+	now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		_, err = res.Resolve(entry.Credential)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 3, hits)
+
+	// expired list
+	list.ValidUntil = &common.FormatedTime{Time: now.Add(-time.Second)}
+	_, err = res.Resolve(entry.Credential)
+	assert.Error(t, err)
+	list.ValidUntil = nil
+
+	// proof verification, lists are rejected without verifier
+	res = NewHTTPStatusListResolver(WithListVerifier(func(cred *credential.Credential) error {
+		return fmt.Errorf("no proof")
+	}))
+	_, err = res.Resolve(entry.Credential)
+	assert.Error(t, err)
+	_, err = NewHTTPStatusListResolver().Resolve(entry.Credential)
+	assert.ErrorContains(t, err, "no list verifier")
+
+	// id of the list must be the url
+	_, err = NewHTTPStatusListResolver(anyProof).Resolve(ts.URL + "/other")
+	assert.Error(t, err)
+}
+
+func TestHTTPStatusListResolverNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+	_, err := NewHTTPStatusListResolver().Resolve(ts.URL + "/status/0")
+	assert.Error(t, err)
+}
+
+func TestHTTPStatusListResolverCache(t *testing.T) {
+	lists := make(map[string]*credential.Credential)
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.Write(lists[r.URL.Path].ToBytes())
+	}))
+	defer ts.Close()
+
+	now := time.Now()
+	clock := func() time.Time { return now }
+	// two lists of 8 entries
+	m := NewStatusListManager(NewMemoryStorage(), ts.URL+"/status", WithListSize(8))
+	var urls []string
+	for i := 0; i < 9; i++ {
+		entry, err := m.Allocate()
+		require.NoError(t, err)
+		if len(urls) == 0 || urls[len(urls)-1] != entry.Credential {
+			urls = append(urls, entry.Credential)
+		}
+	}
+	require.Len(t, urls, 2)
+	for _, u := range urls {
+		list, err := m.StatusCredential(u)
+		require.NoError(t, err)
+		list.ValidUntil = &common.FormatedTime{Time: now.Add(30 * time.Second)}
+		lists[strings.TrimPrefix(u, ts.URL)] = list
+	}
+	res := NewHTTPStatusListResolver(WithClock(clock), WithCacheSize(1),
+		WithListVerifier(func(cred *credential.Credential) error { return nil }))
+
+	// lists are not cached after their validUntil
+	_, err := res.Resolve(urls[0])
+	assert.NoError(t, err)
+	_, err = res.Resolve(urls[0])
+	assert.NoError(t, err)
+	assert.Equal(t, 1, hits)
+	now = now.Add(time.Minute)
+	lists[strings.TrimPrefix(urls[0], ts.URL)].ValidUntil = &common.FormatedTime{Time: now.Add(30 * time.Second)}
+	cred, err := res.Resolve(urls[0])
+	require.NoError(t, err)
+	assert.NoError(t, cred.CheckValidity(now, 0))
+	assert.Equal(t, 2, hits)
+
+	// the least recently used list is evicted
+	lists[strings.TrimPrefix(urls[1], ts.URL)].ValidUntil = lists[strings.TrimPrefix(urls[0], ts.URL)].ValidUntil
+	_, err = res.Resolve(urls[1])
+	assert.NoError(t, err)
+	_, err = res.Resolve(urls[0])
+	assert.NoError(t, err)
+	assert.Equal(t, 4, hits)
+}
@@ -8,17 +8,32 @@ import (
 
 const (
 	StatusList2021          = "StatusList2021"
+	BitstringStatusList     = "BitstringStatusList"
 	StatusPurposeRevocation = "revocation"
 	StatusPurposeSuspension = "suspension"
+	// StatusPurposeMessage indicates a status message of a multi-bit BitstringStatusList entry
+	StatusPurposeMessage = "message"
 )
 
-// GenStatusCredential
+// GenStatusCredential sets the encodedList of preBuildCred, the list is generated for the statusPurpose
+// of its credentialSubject, revocation by default
 func GenStatusCredential(issuedCreds []credential.Credential, preBuildCred *credential.Credential) (*credential.Credential, error) {
-
-	bitStr := GenBitstring(issuedCreds)
-	if bitStr == nil {
-		return nil, fmt.Errorf("cannot generate bit string")
+	subject, ok := preBuildCred.Subject.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("status credential don't have credentialSubject item")
+	}
+	purpose, _ := subject["statusPurpose"].(string)
+	if purpose == "" {
+		purpose = StatusPurposeRevocation
+	}
+	bitStr, err := GenPurposeBitstring(issuedCreds, purpose)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate bit string: %w", err)
+	}
+	encodedList, err := bitStr.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode bit string: %w", err)
 	}
-	preBuildCred.Subject.(map[string]interface{})["encodedList"] = bitStr.Compressed()
+	subject["encodedList"] = encodedList
 	return preBuildCred, nil
 }
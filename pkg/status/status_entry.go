@@ -1,15 +1,52 @@
 package status
 
+import "encoding/json"
+
 const (
-	StatusList2021Entry = "StatusList2021Entry"
+	StatusList2021Entry      = "StatusList2021Entry"
+	BitstringStatusListEntry = "BitstringStatusListEntry"
 )
 
 // StatusList2021Entry
 // https://w3c.github.io/vc-status-list-2021/#statuslist2021entry
+//
+// BitstringStatusListEntry
+// https://www.w3.org/TR/vc-bitstring-status-list/#bitstringstatuslistentry
 type StatusEntry struct {
 	ID         string `json:"id,omitempty"`
 	Type       string `json:"type,omitempty"`
 	Purpose    string `json:"statusPurpose"`
 	ListIndex  string `json:"statusListIndex"`
 	Credential string `json:"statusListCredential"`
+	// BitstringStatusListEntry only
+	StatusSize      int             `json:"statusSize,omitempty"`
+	StatusMessage   []StatusMessage `json:"statusMessage,omitempty"`
+	StatusReference interface{}     `json:"statusReference,omitempty"`
+}
+
+// StatusMessage maps a status value, a hexadecimal string like 0x1, to a message
+type StatusMessage struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// ToMap returns the entry as a credentialStatus value
+func (se *StatusEntry) ToMap() map[string]interface{} {
+	b, _ := json.Marshal(se)
+	ret := make(map[string]interface{})
+	json.Unmarshal(b, &ret)
+	return ret
+}
+
+// ParseStatusEntry reads the status entry of a credentialStatus value
+func ParseStatusEntry(status map[string]interface{}) (*StatusEntry, error) {
+	b, err := json.Marshal(status)
+	if err != nil {
+		return nil, err
+	}
+	ret := &StatusEntry{}
+	if err := json.Unmarshal(b, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
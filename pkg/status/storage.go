@@ -0,0 +1,203 @@
+package status
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrListNotFound is returned by Storage when a status list does not exist.
+var ErrListNotFound = errors.New("status list not found")
+
+// ListRecord is the persisted state of a status list.
+// Allocated is a bitstring with one bit per entry and Status has StatusSize bits per entry,
+// the left-most bit is index 0.
+type ListRecord struct {
+	ID       string `json:"id"`
+	Purpose  string `json:"statusPurpose"`
+	Sequence int    `json:"sequence"`
+	Size     int    `json:"size"`
+	Used     int    `json:"used"`
+	// Type is StatusList2021 when empty
+	Type       string `json:"type,omitempty"`
+	StatusSize int    `json:"statusSize,omitempty"`
+	Allocated  []byte `json:"allocated"`
+	Status     []byte `json:"status"`
+}
+
+// Full reports whether every index of the list has been allocated
+func (rec *ListRecord) Full() bool {
+	return rec.Used >= rec.Size
+}
+
+// statusBits returns the status bitstring of the list
+func (rec *ListRecord) statusBits() (*BitString, int) {
+	size := rec.StatusSize
+	if size == 0 {
+		size = 1
+	}
+	return bitStringOf(rec.Status, rec.Size*size), size
+}
+
+// Storage persists status lists of a StatusListManager
+type Storage interface {
+	// LoadList returns the status list of id, or ErrListNotFound
+	LoadList(id string) (*ListRecord, error)
+	// SaveList creates or replaces the status list
+	SaveList(rec *ListRecord) error
+	// Lists returns the status lists of purpose ordered by sequence
+	Lists(purpose string) ([]*ListRecord, error)
+}
+
+// MemoryStorage keeps status lists in memory.
+type MemoryStorage struct {
+	mu    sync.RWMutex
+	lists map[string]*ListRecord
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		lists: make(map[string]*ListRecord),
+	}
+}
+
+func (ms *MemoryStorage) LoadList(id string) (*ListRecord, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	rec, ok := ms.lists[id]
+	if !ok {
+		return nil, ErrListNotFound
+	}
+	return rec.clone(), nil
+}
+
+func (ms *MemoryStorage) SaveList(rec *ListRecord) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.lists[rec.ID] = rec.clone()
+	return nil
+}
+
+func (ms *MemoryStorage) Lists(purpose string) ([]*ListRecord, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	ret := make([]*ListRecord, 0, len(ms.lists))
+	for _, rec := range ms.lists {
+		if rec.Purpose == purpose {
+			ret = append(ret, rec.clone())
+		}
+	}
+	sortBySequence(ret)
+	return ret, nil
+}
+
+// FileStorage keeps each status list as a JSON file in a directory.
+type FileStorage struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStorage creates a FileStorage in dir, the directory is created if missing
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileStorage{dir: dir}, nil
+}
+
+func (fs *FileStorage) LoadList(id string) (*ListRecord, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.read(fs.path(id))
+}
+
+func (fs *FileStorage) SaveList(rec *ListRecord) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	// write to a temporary file first so a crash never leaves a truncated list
+	tmp, err := os.CreateTemp(fs.dir, ".list-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fs.path(rec.ID))
+}
+
+func (fs *FileStorage) Lists(purpose string) ([]*ListRecord, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	entries, err := os.ReadDir(fs.dir)
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ListRecord
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		rec, err := fs.read(filepath.Join(fs.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if rec.Purpose == purpose {
+			ret = append(ret, rec)
+		}
+	}
+	sortBySequence(ret)
+	return ret, nil
+}
+
+// path returns the file of a list, ids are URLs so the file is named by their digest
+func (fs *FileStorage) path(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return filepath.Join(fs.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (fs *FileStorage) read(path string) (*ListRecord, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrListNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	rec := &ListRecord{}
+	if err := json.Unmarshal(b, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func (rec *ListRecord) clone() *ListRecord {
+	ret := *rec
+	ret.Allocated = append([]byte{}, rec.Allocated...)
+	ret.Status = append([]byte{}, rec.Status...)
+	return &ret
+}
+
+func sortBySequence(recs []*ListRecord) {
+	sort.Slice(recs, func(i, j int) bool {
+		return recs[i].Sequence < recs[j].Sequence
+	})
+}
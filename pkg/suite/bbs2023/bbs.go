@@ -0,0 +1,490 @@
+package bbs2023
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"sync"
+
+	bls12381 "github.com/suutaku/bls12381"
+)
+
+// BBS signatures of the BLS12-381-SHA-256 ciphersuite.
+// https://datatracker.ietf.org/doc/draft-irtf-cfrg-bbs-signatures/
+const (
+	ciphersuiteID = "BBS_BLS12381G1_XMD:SHA-256_SSWU_RO_"
+	apiID         = ciphersuiteID + "H2G_HM2S_"
+	expandLen     = 48
+	scalarSize    = 32
+	g1Size        = 48
+	g2Size        = 96
+	signatureSize = g1Size + scalarSize
+	// proofs hold Abar, Bbar, D, e^, r1^, r3^, a commitment per undisclosed message and the challenge
+	minProofSize = 3*g1Size + 4*scalarSize
+)
+
+var (
+	scalarOrder = bls12381.NewG1().Q()
+
+	p1Once sync.Once
+	p1     *bls12381.PointG1
+	p1Err  error
+)
+
+// generatorP1 returns the P1 generator of the ciphersuite
+func generatorP1(g1 *bls12381.G1) (*bls12381.PointG1, error) {
+	p1Once.Do(func() {
+		var gens []*bls12381.PointG1
+		gens, p1Err = createGenerators(bls12381.NewG1(), 1, "BP_MESSAGE_GENERATOR_SEED")
+		if p1Err == nil {
+			p1 = gens[0]
+		}
+	})
+	if p1Err != nil {
+		return nil, p1Err
+	}
+	return g1.New().Set(p1), nil
+}
+
+// createGenerators returns count generators created from seed
+func createGenerators(g1 *bls12381.G1, count int, seed string) ([]*bls12381.PointG1, error) {
+	seedDST := []byte(apiID + "SIG_GENERATOR_SEED_")
+	generatorDST := []byte(apiID + "SIG_GENERATOR_DST_")
+	v := expandMessageXMD([]byte(apiID+seed), seedDST, expandLen)
+	gens := make([]*bls12381.PointG1, count)
+	for i := range gens {
+		v = expandMessageXMD(concat(v, i2osp(uint64(i+1))), seedDST, expandLen)
+		p, err := g1.HashToCurve(v, generatorDST)
+		if err != nil {
+			return nil, err
+		}
+		gens[i] = p
+	}
+	return gens, nil
+}
+
+// expandMessageXMD is expand_message_xmd of RFC 9380 with SHA-256
+func expandMessageXMD(msg, dst []byte, n int) []byte {
+	h := sha256.New()
+	h.Write(make([]byte, h.BlockSize()))
+	h.Write(msg)
+	h.Write([]byte{byte(n >> 8), byte(n), 0})
+	h.Write(dst)
+	h.Write([]byte{byte(len(dst))})
+	b0 := h.Sum(nil)
+
+	out := make([]byte, 0, n+h.Size())
+	bi := make([]byte, h.Size())
+	for i := 1; len(out) < n; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dst)
+		h.Write([]byte{byte(len(dst))})
+		bi = h.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:n]
+}
+
+// hashToScalar hashes msg to a scalar with domain separation tag dst
+func hashToScalar(msg, dst []byte) *big.Int {
+	s := new(big.Int).SetBytes(expandMessageXMD(msg, dst, expandLen))
+	return s.Mod(s, scalarOrder)
+}
+
+// messagesToScalars maps messages to scalars by hashing them
+func messagesToScalars(messages [][]byte) []*big.Int {
+	dst := []byte(apiID + "MAP_MSG_TO_SCALAR_AS_HASH_")
+	ret := make([]*big.Int, len(messages))
+	for i, msg := range messages {
+		ret[i] = hashToScalar(msg, dst)
+	}
+	return ret
+}
+
+// randomScalars returns count random scalars
+func randomScalars(count int) ([]*big.Int, error) {
+	ret := make([]*big.Int, count)
+	b := make([]byte, expandLen)
+	for i := range ret {
+		if _, err := io.ReadFull(rand.Reader, b); err != nil {
+			return nil, err
+		}
+		ret[i] = new(big.Int).SetBytes(b)
+		ret[i].Mod(ret[i], scalarOrder)
+	}
+	return ret, nil
+}
+
+// serializer concatenates octets of points, scalars and integers
+type serializer struct {
+	g1 *bls12381.G1
+	bytes.Buffer
+}
+
+func (s *serializer) point(p *bls12381.PointG1) {
+	s.Write(s.g1.ToCompressed(p))
+}
+
+func (s *serializer) scalar(e *big.Int) {
+	s.Write(scalarBytes(e))
+}
+
+func (s *serializer) integer(n int) {
+	s.Write(i2osp(uint64(n)))
+}
+
+func scalarBytes(e *big.Int) []byte {
+	return e.FillBytes(make([]byte, scalarSize))
+}
+
+func i2osp(n uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, n)
+	return b
+}
+
+func concat(parts ...[]byte) []byte {
+	var ret []byte
+	for _, p := range parts {
+		ret = append(ret, p...)
+	}
+	return ret
+}
+
+// calculateDomain binds the public key, generators and header to signatures and proofs
+func calculateDomain(g1 *bls12381.G1, pub []byte, gens []*bls12381.PointG1, header []byte) *big.Int {
+	s := &serializer{g1: g1}
+	s.Write(pub)
+	s.integer(len(gens) - 1)
+	for _, p := range gens {
+		s.point(p)
+	}
+	s.WriteString(apiID)
+	s.integer(len(header))
+	s.Write(header)
+	return hashToScalar(s.Bytes(), []byte(apiID+"H2S_"))
+}
+
+// commitment returns P1 + Q_1 * domain + H_i * msg_i of the messages at indexes
+func commitment(g1 *bls12381.G1, gens []*bls12381.PointG1, domain *big.Int, indexes []int, scalars []*big.Int) (*bls12381.PointG1, error) {
+	b, err := generatorP1(g1)
+	if err != nil {
+		return nil, err
+	}
+	tmp := g1.New()
+	g1.Add(b, b, g1.MulScalarBig(tmp, gens[0], domain))
+	for i, idx := range indexes {
+		g1.Add(b, b, g1.MulScalarBig(tmp, gens[idx+1], scalars[i]))
+	}
+	return b, nil
+}
+
+// keyPoint parses a public key
+func keyPoint(g2 *bls12381.G2, pub []byte) (*bls12381.PointG2, error) {
+	if len(pub) != g2Size {
+		return nil, fmt.Errorf("invalid BBS public key size %d", len(pub))
+	}
+	w, err := g2.FromCompressed(pub)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BBS public key: %w", err)
+	}
+	if g2.IsZero(w) {
+		return nil, fmt.Errorf("invalid BBS public key")
+	}
+	return w, nil
+}
+
+// parseScalar parses a non-zero scalar smaller than the group order
+func parseScalar(b []byte) (*big.Int, error) {
+	s := new(big.Int).SetBytes(b)
+	if s.Sign() == 0 || s.Cmp(scalarOrder) >= 0 {
+		return nil, fmt.Errorf("invalid scalar")
+	}
+	return s, nil
+}
+
+func allIndexes(n int) []int {
+	ret := make([]int, n)
+	for i := range ret {
+		ret[i] = i
+	}
+	return ret
+}
+
+// bbsSign signs messages and header with secret key sk of public key pub
+func bbsSign(sk *big.Int, pub, header []byte, messages [][]byte) ([]byte, error) {
+	g1 := bls12381.NewG1()
+	gens, err := createGenerators(g1, len(messages)+1, "MESSAGE_GENERATOR_SEED")
+	if err != nil {
+		return nil, err
+	}
+	scalars := messagesToScalars(messages)
+	domain := calculateDomain(g1, pub, gens, header)
+
+	s := &serializer{g1: g1}
+	s.scalar(sk)
+	for _, msg := range scalars {
+		s.scalar(msg)
+	}
+	s.scalar(domain)
+	e := hashToScalar(s.Bytes(), []byte(apiID+"H2S_"))
+
+	b, err := commitment(g1, gens, domain, allIndexes(len(messages)), scalars)
+	if err != nil {
+		return nil, err
+	}
+	inv := new(big.Int).Add(sk, e)
+	if inv.ModInverse(inv.Mod(inv, scalarOrder), scalarOrder) == nil {
+		return nil, fmt.Errorf("invalid BBS secret key")
+	}
+	a := g1.MulScalarBig(g1.New(), b, inv)
+	if g1.IsZero(a) {
+		return nil, fmt.Errorf("invalid BBS signature")
+	}
+	return concat(g1.ToCompressed(a), scalarBytes(e)), nil
+}
+
+// bbsVerify verifies signature of messages and header
+func bbsVerify(pub, signature, header []byte, messages [][]byte) error {
+	g1, g2 := bls12381.NewG1(), bls12381.NewG2()
+	w, err := keyPoint(g2, pub)
+	if err != nil {
+		return err
+	}
+	if len(signature) != signatureSize {
+		return fmt.Errorf("invalid BBS signature size %d", len(signature))
+	}
+	a, err := g1.FromCompressed(signature[:g1Size])
+	if err != nil || g1.IsZero(a) {
+		return fmt.Errorf("invalid BBS signature")
+	}
+	e, err := parseScalar(signature[g1Size:])
+	if err != nil {
+		return fmt.Errorf("invalid BBS signature: %w", err)
+	}
+
+	gens, err := createGenerators(g1, len(messages)+1, "MESSAGE_GENERATOR_SEED")
+	if err != nil {
+		return err
+	}
+	domain := calculateDomain(g1, pub, gens, header)
+	b, err := commitment(g1, gens, domain, allIndexes(len(messages)), messagesToScalars(messages))
+	if err != nil {
+		return err
+	}
+	// e(A, W + BP2 * e) * e(B, -BP2) == 1
+	we := g2.MulScalarBig(g2.New(), g2.One(), e)
+	g2.Add(we, we, w)
+	engine := bls12381.NewEngine()
+	engine.AddPair(a, we)
+	engine.AddPairInv(b, g2.One())
+	if !engine.Check() {
+		return fmt.Errorf("invalid BBS signature")
+	}
+	return nil
+}
+
+// proofChallenge calculates the challenge of a proof disclosing scalars at indexes
+func proofChallenge(g1 *bls12381.G1, points []*bls12381.PointG1, domain *big.Int, indexes []int, scalars []*big.Int, presentationHeader []byte) *big.Int {
+	s := &serializer{g1: g1}
+	s.integer(len(indexes))
+	for i, idx := range indexes {
+		s.integer(idx)
+		s.scalar(scalars[i])
+	}
+	for _, p := range points {
+		s.point(p)
+	}
+	s.scalar(domain)
+	s.integer(len(presentationHeader))
+	s.Write(presentationHeader)
+	return hashToScalar(s.Bytes(), []byte(apiID+"H2S_"))
+}
+
+// checkIndexes sorts indexes and checks they are distinct and smaller than n
+func checkIndexes(indexes []int, n int) ([]int, error) {
+	ret := append([]int{}, indexes...)
+	sort.Ints(ret)
+	for i, idx := range ret {
+		if idx < 0 || idx >= n {
+			return nil, fmt.Errorf("message index %d out of range", idx)
+		}
+		if i > 0 && ret[i-1] == idx {
+			return nil, fmt.Errorf("duplicate message index %d", idx)
+		}
+	}
+	return ret, nil
+}
+
+// bbsProofGen creates a proof of signature disclosing the messages at indexes
+func bbsProofGen(pub, signature, header, presentationHeader []byte, messages [][]byte, indexes []int) ([]byte, error) {
+	return proofGen(pub, signature, header, presentationHeader, messages, indexes, randomScalars)
+}
+
+// proofGen is bbsProofGen with the source of random scalars, so fixtures can mock it
+func proofGen(pub, signature, header, presentationHeader []byte, messages [][]byte, indexes []int, random func(count int) ([]*big.Int, error)) ([]byte, error) {
+	if err := bbsVerify(pub, signature, header, messages); err != nil {
+		return nil, err
+	}
+	disclosed, err := checkIndexes(indexes, len(messages))
+	if err != nil {
+		return nil, err
+	}
+	var undisclosed []int
+	for i, j := 0, 0; i < len(messages); i++ {
+		if j < len(disclosed) && disclosed[j] == i {
+			j++
+			continue
+		}
+		undisclosed = append(undisclosed, i)
+	}
+
+	g1 := bls12381.NewG1()
+	a, _ := g1.FromCompressed(signature[:g1Size])
+	e := new(big.Int).SetBytes(signature[g1Size:])
+	gens, err := createGenerators(g1, len(messages)+1, "MESSAGE_GENERATOR_SEED")
+	if err != nil {
+		return nil, err
+	}
+	scalars := messagesToScalars(messages)
+	domain := calculateDomain(g1, pub, gens, header)
+	b, err := commitment(g1, gens, domain, allIndexes(len(messages)), scalars)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := random(5 + len(undisclosed))
+	if err != nil {
+		return nil, err
+	}
+	r1, r2, eT, r1T, r3T, mT := rs[0], rs[1], rs[2], rs[3], rs[4], rs[5:]
+	mod := func(x *big.Int) *big.Int { return x.Mod(x, scalarOrder) }
+	tmp := g1.New()
+	d := g1.MulScalarBig(g1.New(), b, r2)
+	aBar := g1.MulScalarBig(g1.New(), a, mod(new(big.Int).Mul(r1, r2)))
+	bBar := g1.MulScalarBig(g1.New(), d, r1)
+	g1.Sub(bBar, bBar, g1.MulScalarBig(tmp, aBar, e))
+	t1 := g1.MulScalarBig(g1.New(), aBar, eT)
+	g1.Add(t1, t1, g1.MulScalarBig(tmp, d, r1T))
+	t2 := g1.MulScalarBig(g1.New(), d, r3T)
+	for i, idx := range undisclosed {
+		g1.Add(t2, t2, g1.MulScalarBig(tmp, gens[idx+1], mT[i]))
+	}
+
+	disclosedScalars := make([]*big.Int, len(disclosed))
+	for i, idx := range disclosed {
+		disclosedScalars[i] = scalars[idx]
+	}
+	c := proofChallenge(g1, []*bls12381.PointG1{aBar, bBar, d, t1, t2}, domain, disclosed, disclosedScalars, presentationHeader)
+
+	r3 := new(big.Int).ModInverse(r2, scalarOrder)
+	if r3 == nil {
+		return nil, fmt.Errorf("invalid random scalar")
+	}
+	s := &serializer{g1: g1}
+	s.point(aBar)
+	s.point(bBar)
+	s.point(d)
+	s.scalar(mod(new(big.Int).Add(eT, new(big.Int).Mul(e, c))))
+	s.scalar(mod(new(big.Int).Sub(r1T, new(big.Int).Mul(r1, c))))
+	s.scalar(mod(new(big.Int).Sub(r3T, new(big.Int).Mul(r3, c))))
+	for i, idx := range undisclosed {
+		s.scalar(mod(new(big.Int).Add(mT[i], new(big.Int).Mul(scalars[idx], c))))
+	}
+	s.scalar(c)
+	return s.Bytes(), nil
+}
+
+// bbsProofVerify verifies a proof disclosing messages at indexes
+func bbsProofVerify(pub, proof, header, presentationHeader []byte, messages [][]byte, indexes []int) error {
+	g1, g2 := bls12381.NewG1(), bls12381.NewG2()
+	w, err := keyPoint(g2, pub)
+	if err != nil {
+		return err
+	}
+	if len(proof) < minProofSize || (len(proof)-3*g1Size)%scalarSize != 0 {
+		return fmt.Errorf("invalid BBS proof size %d", len(proof))
+	}
+	if len(indexes) != len(messages) {
+		return fmt.Errorf("%d disclosed messages for %d indexes", len(messages), len(indexes))
+	}
+	undisclosedCount := (len(proof) - minProofSize) / scalarSize
+	total := len(messages) + undisclosedCount
+	disclosed, err := checkIndexes(indexes, total)
+	if err != nil {
+		return err
+	}
+	// messages are given in the order of their indexes
+	for i := range indexes {
+		if indexes[i] != disclosed[i] {
+			return fmt.Errorf("message indexes must be sorted")
+		}
+	}
+
+	points := make([]*bls12381.PointG1, 3)
+	for i := range points {
+		p, err := g1.FromCompressed(proof[i*g1Size : (i+1)*g1Size])
+		if err != nil || g1.IsZero(p) {
+			return fmt.Errorf("invalid BBS proof")
+		}
+		points[i] = p
+	}
+	aBar, bBar, d := points[0], points[1], points[2]
+	scalars := make([]*big.Int, 0, 4+undisclosedCount)
+	for off := 3 * g1Size; off < len(proof); off += scalarSize {
+		s, err := parseScalar(proof[off : off+scalarSize])
+		if err != nil {
+			return fmt.Errorf("invalid BBS proof: %w", err)
+		}
+		scalars = append(scalars, s)
+	}
+	eH, r1H, r3H, commitments, cp := scalars[0], scalars[1], scalars[2], scalars[3:len(scalars)-1], scalars[len(scalars)-1]
+
+	gens, err := createGenerators(g1, total+1, "MESSAGE_GENERATOR_SEED")
+	if err != nil {
+		return err
+	}
+	domain := calculateDomain(g1, pub, gens, header)
+	msgScalars := messagesToScalars(messages)
+	tmp := g1.New()
+	t1 := g1.MulScalarBig(g1.New(), bBar, cp)
+	g1.Add(t1, t1, g1.MulScalarBig(tmp, aBar, eH))
+	g1.Add(t1, t1, g1.MulScalarBig(tmp, d, r1H))
+	bv, err := commitment(g1, gens, domain, disclosed, msgScalars)
+	if err != nil {
+		return err
+	}
+	t2 := g1.MulScalarBig(g1.New(), bv, cp)
+	g1.Add(t2, t2, g1.MulScalarBig(tmp, d, r3H))
+	for i, j := 0, 0; i < total; i++ {
+		if len(disclosed) > 0 && i == disclosed[0] {
+			disclosed = disclosed[1:]
+			continue
+		}
+		g1.Add(t2, t2, g1.MulScalarBig(tmp, gens[i+1], commitments[j]))
+		j++
+	}
+
+	c := proofChallenge(g1, []*bls12381.PointG1{aBar, bBar, d, t1, t2}, domain, indexes, msgScalars, presentationHeader)
+	if c.Cmp(cp) != 0 {
+		return fmt.Errorf("invalid BBS proof")
+	}
+	// e(Abar, W) * e(Bbar, -BP2) == 1
+	engine := bls12381.NewEngine()
+	engine.AddPair(aBar, w)
+	engine.AddPairInv(bBar, g2.One())
+	if !engine.Check() {
+		return fmt.Errorf("invalid BBS proof")
+	}
+	return nil
+}
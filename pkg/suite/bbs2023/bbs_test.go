@@ -0,0 +1,140 @@
+package bbs2023
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bls12381 "github.com/suutaku/bls12381"
+)
+
+func unhex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+// fixtures of the BLS12-381-SHA-256 ciphersuite
+func TestCiphersuiteFixtures(t *testing.T) {
+	g1, g2 := bls12381.NewG1(), bls12381.NewG2()
+	p1, err := generatorP1(g1)
+	require.NoError(t, err)
+	assert.Equal(t, "a8ce256102840821a3e94ea9025e4662b205762f9776b3a766c872b948f1fd225e7c59698588e70d11406d161b4e28c9",
+		hex.EncodeToString(g1.ToCompressed(p1)))
+	gens, err := createGenerators(g1, 1, "MESSAGE_GENERATOR_SEED")
+	require.NoError(t, err)
+	assert.Equal(t, "a9ec65b70a7fbe40c874c9eb041c2cb0a7af36ccec1bea48fa2ba4c2eb67ef7f9ecb17ed27d38d27cdeddff44c8137be",
+		hex.EncodeToString(g1.ToCompressed(gens[0])))
+
+	sk := new(big.Int).SetBytes(unhex(t, "60e55110f76883a13d030b2f6bd11883422d5abde717569fc0731f51237169fc"))
+	assert.Equal(t, "a820f230f6ae38503b86c70dc50b61c58a77e45c39ab25c0652bbaa8fa136f2851bd4781c9dcde39fc9d1d52c9e602"+
+		"68061e7d7632171d91aa8d460acee0e96f1e7c4cfb12d3ff9ab5d5dc91c277db75c845d649ef3c4f63aebc364cd55ded0c",
+		hex.EncodeToString(g2.ToCompressed(g2.MulScalarBig(g2.New(), g2.One(), sk))))
+
+	message := unhex(t, "9872ad089e452c7b6e283dfac2a80d58e8d0ff71cc4d5e310a1debdda4a45f02")
+	scalars := messagesToScalars([][]byte{message, {}})
+	assert.Equal(t, "1cb5bb86114b34dc438a911617655a1db595abafac92f47c5001799cf624b430", hex.EncodeToString(scalarBytes(scalars[0])))
+	assert.Equal(t, "08e3afeb2b4f2b5f907924ef42856616e6f2d5f1fb373736db1cca32707a7d16", hex.EncodeToString(scalarBytes(scalars[1])))
+
+	// single message signature
+	pub := g2.ToCompressed(g2.MulScalarBig(g2.New(), g2.One(), sk))
+	header := unhex(t, "11223344556677889900aabbccddeeff")
+	signature, err := bbsSign(sk, pub, header, [][]byte{message})
+	require.NoError(t, err)
+	assert.Equal(t, "84773160b824e194073a57493dac1a20b667af70cd2352d8af241c77658da5253aa8458317cca0eae615690d55b1f2716"+
+		"4657dcafee1d5c1973947aa70e2cfbb4c892340be5969920d0916067b4565a0", hex.EncodeToString(signature))
+	assert.NoError(t, bbsVerify(pub, signature, header, [][]byte{message}))
+}
+
+func TestSignatureAndProof(t *testing.T) {
+	g2 := bls12381.NewG2()
+	sk := new(big.Int).SetBytes(unhex(t, "60e55110f76883a13d030b2f6bd11883422d5abde717569fc0731f51237169fc"))
+	pub := g2.ToCompressed(g2.MulScalarBig(g2.New(), g2.One(), sk))
+	header := unhex(t, "11223344556677889900aabbccddeeff")
+	messages := [][]byte{[]byte("first"), []byte("second"), []byte("third"), {}}
+
+	signature, err := bbsSign(sk, pub, header, messages)
+	require.NoError(t, err)
+	assert.Len(t, signature, signatureSize)
+	assert.NoError(t, bbsVerify(pub, signature, header, messages))
+	assert.Error(t, bbsVerify(pub, signature, []byte("other"), messages))
+	assert.Error(t, bbsVerify(pub, signature, header, messages[:3]))
+
+	ph := []byte("presentation")
+	proof, err := bbsProofGen(pub, signature, header, ph, messages, []int{2, 0})
+	require.NoError(t, err)
+	assert.Len(t, proof, minProofSize+2*scalarSize)
+	disclosed := [][]byte{messages[0], messages[2]}
+	assert.NoError(t, bbsProofVerify(pub, proof, header, ph, disclosed, []int{0, 2}))
+	// proofs are bound to the presentation header, header and disclosed messages
+	assert.Error(t, bbsProofVerify(pub, proof, header, []byte("other"), disclosed, []int{0, 2}))
+	assert.Error(t, bbsProofVerify(pub, proof, []byte("other"), ph, disclosed, []int{0, 2}))
+	assert.Error(t, bbsProofVerify(pub, proof, header, ph, [][]byte{messages[0], messages[1]}, []int{0, 2}))
+	assert.Error(t, bbsProofVerify(pub, proof, header, ph, [][]byte{messages[0], messages[2]}, []int{0, 1}))
+	assert.Error(t, bbsProofVerify(pub, proof[:len(proof)-1], header, ph, disclosed, []int{0, 2}))
+
+	// nothing and everything disclosed
+	proof, err = bbsProofGen(pub, signature, header, nil, messages, nil)
+	require.NoError(t, err)
+	assert.NoError(t, bbsProofVerify(pub, proof, header, nil, nil, nil))
+	proof, err = bbsProofGen(pub, signature, header, nil, messages, []int{0, 1, 2, 3})
+	require.NoError(t, err)
+	assert.NoError(t, bbsProofVerify(pub, proof, header, nil, messages, []int{0, 1, 2, 3}))
+
+	_, err = bbsProofGen(pub, signature, header, nil, messages, []int{4})
+	assert.Error(t, err)
+	_, err = bbsProofGen(pub, signature, []byte("other"), nil, messages, []int{0})
+	assert.Error(t, err)
+}
+
+// mockedRandomScalars is mocked_calculate_random_scalars of the BBS draft
+func mockedRandomScalars(count int) ([]*big.Int, error) {
+	v := expandMessageXMD([]byte("3.141592653589793238462643383279"), []byte(ciphersuiteID+"MOCK_RANDOM_SCALARS_DST_"), expandLen*count)
+	ret := make([]*big.Int, count)
+	for i := range ret {
+		ret[i] = new(big.Int).SetBytes(v[i*expandLen : (i+1)*expandLen])
+		ret[i].Mod(ret[i], scalarOrder)
+	}
+	return ret, nil
+}
+
+func TestMockedProof(t *testing.T) {
+	g1, g2 := bls12381.NewG1(), bls12381.NewG2()
+	sk := new(big.Int).SetBytes(unhex(t, "60e55110f76883a13d030b2f6bd11883422d5abde717569fc0731f51237169fc"))
+	pub := g2.ToCompressed(g2.MulScalarBig(g2.New(), g2.One(), sk))
+	header := unhex(t, "11223344556677889900aabbccddeeff")
+	ph := unhex(t, "bed231d880675ed101ead304512e043ade9958dd0241ea70b4b3957fba941501")
+	messages := [][]byte{
+		unhex(t, "9872ad089e452c7b6e283dfac2a80d58e8d0ff71cc4d5e310a1debdda4a45f02"),
+		unhex(t, "c344136d9ab02da4dd5908bbba913ae6f58c2cc844b802a6f811f5fb075f9b80"),
+		unhex(t, "7372e9daa5ed31e6cd5c825eac1b855e84476a1d94932aa348e07b73"),
+		{},
+	}
+	signature, err := bbsSign(sk, pub, header, messages)
+	require.NoError(t, err)
+
+	proof, err := proofGen(pub, signature, header, ph, messages, []int{0, 2}, mockedRandomScalars)
+	require.NoError(t, err)
+	again, err := proofGen(pub, signature, header, ph, messages, []int{0, 2}, mockedRandomScalars)
+	require.NoError(t, err)
+	assert.Equal(t, proof, again)
+	disclosed := [][]byte{messages[0], messages[2]}
+	assert.NoError(t, bbsProofVerify(pub, proof, header, ph, disclosed, []int{0, 2}))
+
+	// Abar = A * (r1 * r2) with the mocked r1 and r2
+	rs, err := mockedRandomScalars(7)
+	require.NoError(t, err)
+	a, err := g1.FromCompressed(signature[:g1Size])
+	require.NoError(t, err)
+	r := new(big.Int).Mul(rs[0], rs[1])
+	assert.Equal(t, g1.ToCompressed(g1.MulScalarBig(g1.New(), a, r.Mod(r, scalarOrder))), proof[:g1Size])
+
+	// every field of the proof is checked
+	for i := 0; i < len(proof); i += scalarSize {
+		tampered := append([]byte{}, proof...)
+		tampered[i] ^= 0x01
+		assert.Error(t, bbsProofVerify(pub, tampered, header, ph, disclosed, []int{0, 2}), "offset %d", i)
+	}
+}
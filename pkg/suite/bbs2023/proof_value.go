@@ -0,0 +1,99 @@
+package bbs2023
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/suutaku/go-vc/internal/tools"
+)
+
+var (
+	baseProofHeader    = []byte{0xd9, 0x5d, 0x02}
+	derivedProofHeader = []byte{0xd9, 0x5d, 0x03}
+)
+
+// baseProofValue holds components of a bbs-2023 base proof
+type baseProofValue struct {
+	_                 struct{} `cbor:",toarray"`
+	Signature         []byte
+	Header            []byte
+	PublicKey         []byte
+	HMACKey           []byte
+	MandatoryPointers []string
+}
+
+// derivedProofValue holds components of a bbs-2023 derived proof.
+// LabelMap is the compressed form of the c14nN -> bM blank node label map.
+type derivedProofValue struct {
+	_                  struct{} `cbor:",toarray"`
+	Proof              []byte
+	LabelMap           map[int]int
+	MandatoryIndexes   []int
+	SelectiveIndexes   []int
+	PresentationHeader []byte
+}
+
+// isDerivedProofValue reports whether proofValue holds a derived (disclosed) proof
+func isDerivedProofValue(proofValue string) bool {
+	b, err := tools.DecodeMultibase(proofValue)
+	return err == nil && bytes.HasPrefix(b, derivedProofHeader)
+}
+
+func serializeBaseProofValue(v *baseProofValue) (string, error) {
+	if v.MandatoryPointers == nil {
+		v.MandatoryPointers = []string{}
+	}
+	return serializeProofValue(baseProofHeader, v)
+}
+
+func parseBaseProofValue(proofValue string) (*baseProofValue, error) {
+	ret := &baseProofValue{}
+	if err := parseProofValue(proofValue, baseProofHeader, ret); err != nil {
+		return nil, fmt.Errorf("invalid bbs-2023 base proof: %w", err)
+	}
+	return ret, nil
+}
+
+func serializeDerivedProofValue(v *derivedProofValue) (string, error) {
+	if v.MandatoryIndexes == nil {
+		v.MandatoryIndexes = []int{}
+	}
+	if v.SelectiveIndexes == nil {
+		v.SelectiveIndexes = []int{}
+	}
+	if v.PresentationHeader == nil {
+		v.PresentationHeader = []byte{}
+	}
+	return serializeProofValue(derivedProofHeader, v)
+}
+
+func parseDerivedProofValue(proofValue string) (*derivedProofValue, error) {
+	ret := &derivedProofValue{}
+	if err := parseProofValue(proofValue, derivedProofHeader, ret); err != nil {
+		return nil, fmt.Errorf("invalid bbs-2023 derived proof: %w", err)
+	}
+	return ret, nil
+}
+
+func serializeProofValue(header []byte, v interface{}) (string, error) {
+	b, err := cbor.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return tools.EncodeMultibase(tools.MultibaseBase64URL, append(append([]byte{}, header...), b...))
+}
+
+func parseProofValue(proofValue string, header []byte, v interface{}) error {
+	if len(proofValue) == 0 || proofValue[0] != tools.MultibaseBase64URL {
+		return fmt.Errorf("proofValue must be multibase base64url encoded")
+	}
+	b, err := tools.DecodeMultibase(proofValue)
+	if err != nil {
+		return err
+	}
+	if !bytes.HasPrefix(b, header) {
+		return fmt.Errorf("unexpected proofValue header")
+	}
+	return cbor.Unmarshal(b[len(header):], v)
+}
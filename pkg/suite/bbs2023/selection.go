@@ -0,0 +1,176 @@
+package bbs2023
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sparseArray is a selected JSON array which may miss some of its source elements
+type sparseArray map[int]interface{}
+
+// selectJSONLD returns the part of a compact JSON-LD document selected by JSON pointers.
+// https://www.w3.org/TR/vc-di-ecdsa/#selectjsonld
+func selectJSONLD(pointers []string, document map[string]interface{}) (map[string]interface{}, error) {
+	if len(pointers) == 0 {
+		return nil, nil
+	}
+	selection := createInitialSelection(document)
+	if ctx, ok := document["@context"]; ok {
+		selection["@context"] = deepCopy(ctx)
+	}
+	for _, pointer := range pointers {
+		paths, err := parsePointer(pointer)
+		if err != nil {
+			return nil, err
+		}
+		if err := selectPaths(document, paths, selection); err != nil {
+			return nil, fmt.Errorf("select %s: %w", pointer, err)
+		}
+	}
+	return compactSelection(selection).(map[string]interface{}), nil
+}
+
+// parsePointer splits a JSON pointer (RFC 6901) into unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	paths := strings.Split(pointer[1:], "/")
+	for i := range paths {
+		paths[i] = strings.ReplaceAll(strings.ReplaceAll(paths[i], "~1", "/"), "~0", "~")
+	}
+	return paths, nil
+}
+
+func createInitialSelection(source interface{}) map[string]interface{} {
+	selection := make(map[string]interface{})
+	m, ok := source.(map[string]interface{})
+	if !ok {
+		return selection
+	}
+	if id, ok := m["id"].(string); ok && !strings.HasPrefix(id, "_:") {
+		selection["id"] = id
+	}
+	if t, ok := m["type"]; ok {
+		selection["type"] = deepCopy(t)
+	}
+	return selection
+}
+
+func selectPaths(document map[string]interface{}, paths []string, selection map[string]interface{}) error {
+	var value interface{} = document
+	var selectedParent interface{}
+	var selectedValue interface{} = selection
+	for _, path := range paths {
+		selectedParent = selectedValue
+		var err error
+		if value, err = childOf(value, path); err != nil {
+			return err
+		}
+		selectedValue = selectedChildOf(selectedParent, path)
+		if selectedValue == nil {
+			if _, ok := value.([]interface{}); ok {
+				selectedValue = sparseArray{}
+			} else {
+				selectedValue = createInitialSelection(value)
+			}
+			setChild(selectedParent, path, selectedValue)
+		}
+	}
+	// path traversal complete, compute selected value
+	switch v := value.(type) {
+	case map[string]interface{}:
+		merged := deepCopy(v).(map[string]interface{})
+		if current, ok := selectedValue.(map[string]interface{}); ok {
+			for k, cv := range current {
+				if _, ok := merged[k]; !ok {
+					merged[k] = cv
+				}
+			}
+		}
+		selectedValue = merged
+	default:
+		selectedValue = deepCopy(v)
+	}
+	setChild(selectedParent, paths[len(paths)-1], selectedValue)
+	return nil
+}
+
+func childOf(value interface{}, path string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if child, ok := v[path]; ok {
+			return child, nil
+		}
+	case []interface{}:
+		idx, err := strconv.Atoi(path)
+		if err == nil && idx >= 0 && idx < len(v) {
+			return v[idx], nil
+		}
+	}
+	return nil, fmt.Errorf("JSON pointer does not match the document")
+}
+
+func selectedChildOf(parent interface{}, path string) interface{} {
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		return p[path]
+	case sparseArray:
+		idx, _ := strconv.Atoi(path)
+		return p[idx]
+	}
+	return nil
+}
+
+func setChild(parent interface{}, path string, value interface{}) {
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[path] = value
+	case sparseArray:
+		idx, _ := strconv.Atoi(path)
+		p[idx] = value
+	}
+}
+
+// compactSelection turns sparse arrays of a selection into plain arrays
+func compactSelection(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = compactSelection(v[k])
+		}
+		return v
+	case sparseArray:
+		indexes := make([]int, 0, len(v))
+		for idx := range v {
+			indexes = append(indexes, idx)
+		}
+		sort.Ints(indexes)
+		ret := make([]interface{}, len(indexes))
+		for i, idx := range indexes {
+			ret[i] = compactSelection(v[idx])
+		}
+		return ret
+	}
+	return value
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for k := range v {
+			ret[k] = deepCopy(v[k])
+		}
+		return ret
+	case []interface{}:
+		ret := make([]interface{}, len(v))
+		for i := range v {
+			ret[i] = deepCopy(v[i])
+		}
+		return ret
+	}
+	return value
+}
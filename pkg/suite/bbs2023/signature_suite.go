@@ -0,0 +1,267 @@
+// Package bbs2023 implements the bbs-2023 Data Integrity cryptosuite (https://www.w3.org/TR/vc-di-bbs/).
+//
+// Signatures and proofs are IETF BBS signatures of the BLS12-381-SHA-256 ciphersuite
+// (https://datatracker.ietf.org/doc/draft-irtf-cfrg-bbs-signatures/), keys are BLS12-381 G2 keys
+// of github.com/suutaku/go-bbs.
+package bbs2023
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"github.com/suutaku/go-bbs/pkg/bbs"
+	"github.com/suutaku/go-vc/pkg/processor"
+)
+
+const (
+	cryptosuite   = "bbs-2023"
+	rdfDataSetAlg = "URDNA2015"
+	hmacKeySize   = 32
+	// SuiteContext is the JSON-LD context which defines DataIntegrityProof terms
+	SuiteContext = "https://w3id.org/security/data-integrity/v2"
+)
+
+type SignatureSuite struct {
+	*Signer
+	*Verifier
+	CompactedProof bool
+}
+
+// NewSignatureSuite creates bbs-2023 suite, a nil private key creates a suite
+// which can only derive and verify proofs.
+func NewSignatureSuite(priv *bbs.PrivateKey, compacted bool) *SignatureSuite {
+	return &SignatureSuite{
+		Signer:         NewSigner(priv),
+		Verifier:       NewVerifier(),
+		CompactedProof: compacted,
+	}
+}
+
+// GetCanonicalDocument will return normalized/canonical version of the document
+func (suite *SignatureSuite) GetCanonicalDocument(doc map[string]interface{}, opts ...processor.ProcessorOpts) ([]byte, error) {
+	return processor.NewProcessor(rdfDataSetAlg).GetCanonicalDocument(doc, opts...)
+}
+
+// GetDigest returns document digest
+func (suite *SignatureSuite) GetDigest(doc []byte) []byte {
+	digest := sha256.Sum256(doc)
+	return digest[:]
+}
+
+func (suite *SignatureSuite) Alg() string {
+	return cryptosuite
+}
+
+// Cryptosuite returns value of the proof's cryptosuite property
+func (suite *SignatureSuite) Cryptosuite() string {
+	return cryptosuite
+}
+
+// Sign is not supported, bbs-2023 signs the whole document with SignDocument
+func (suite *SignatureSuite) Sign(docByte []byte) ([]byte, error) {
+	return nil, fmt.Errorf("%s signs documents with SignDocument", cryptosuite)
+}
+
+// Verify is not supported, bbs-2023 verifies the whole document with VerifyDocument
+func (suite *SignatureSuite) Verify(pubKeyValue, message, signature, nonce []byte) error {
+	return fmt.Errorf("%s verifies documents with VerifyDocument", cryptosuite)
+}
+
+// Accept registers this signature suite with the given signature type
+func (suite *SignatureSuite) Accept(sType string) bool {
+	return sType == cryptosuite
+}
+
+// CompactProof indicates weather to compact the proof doc before canonization
+func (suite *SignatureSuite) CompactProof() bool {
+	return suite.CompactedProof
+}
+
+// SignDocument creates the base proof value.
+// https://www.w3.org/TR/vc-di-bbs/#base-proof-transformation-bbs-2023
+func (suite *SignatureSuite) SignDocument(doc, proofOptions map[string]interface{}, mandatoryPointers []string,
+	opts ...processor.ProcessorOpts) (string, error) {
+	pub, err := suite.Signer.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	proofHash, err := hashProofConfig(doc, proofOptions, opts...)
+	if err != nil {
+		return "", fmt.Errorf("hash proof configuration: %w", err)
+	}
+	hmacKey := make([]byte, hmacKeySize)
+	if _, err := rand.Read(hmacKey); err != nil {
+		return "", err
+	}
+	groups, _, err := canonicalizeAndGroup(doc, shuffledLabelMap(hmacKey), map[string][]string{
+		"mandatory": mandatoryPointers,
+	}, opts...)
+	if err != nil {
+		return "", err
+	}
+	mandatory := groups["mandatory"]
+	header := append(proofHash, hashMandatory(mandatory.Matching)...)
+	signature, err := suite.Signer.Sign(header, toMessages(mandatory.NonMatching))
+	if err != nil {
+		return "", err
+	}
+	return serializeBaseProofValue(&baseProofValue{
+		Signature:         signature,
+		Header:            header,
+		PublicKey:         pub,
+		HMACKey:           hmacKey,
+		MandatoryPointers: mandatoryPointers,
+	})
+}
+
+// DeriveDocumentProof creates the reveal document and derived proof value from a base proof.
+// https://www.w3.org/TR/vc-di-bbs/#add-derived-proof-bbs-2023
+func (suite *SignatureSuite) DeriveDocumentProof(doc, proof map[string]interface{}, selectivePointers []string, presentationHeader []byte,
+	opts ...processor.ProcessorOpts) (map[string]interface{}, string, error) {
+	proofValue, _ := proof["proofValue"].(string)
+	base, err := parseBaseProofValue(proofValue)
+	if err != nil {
+		return nil, "", err
+	}
+	combinedPointers := append(append([]string{}, base.MandatoryPointers...), selectivePointers...)
+	groups, labelMap, err := canonicalizeAndGroup(doc, shuffledLabelMap(base.HMACKey), map[string][]string{
+		"mandatory": base.MandatoryPointers,
+		"selective": selectivePointers,
+		"combined":  combinedPointers,
+	}, opts...)
+	if err != nil {
+		return nil, "", err
+	}
+	mandatory, selective, combined := groups["mandatory"], groups["selective"], groups["combined"]
+
+	mandatoryIndexes := relativeIndexes(mandatory.MatchingIndexes, combined.MatchingIndexes)
+	selectiveIndexes := relativeIndexes(selective.MatchingIndexes, mandatory.NonMatchingIndexes)
+	bbsProof, err := suite.Signer.DeriveProof(base.PublicKey, base.Signature, base.Header, presentationHeader,
+		toMessages(mandatory.NonMatching), selectiveIndexes)
+	if err != nil {
+		return nil, "", fmt.Errorf("derive BBS proof: %w", err)
+	}
+
+	revealDoc, err := selectJSONLD(combinedPointers, doc)
+	if err != nil {
+		return nil, "", err
+	}
+	if revealDoc == nil {
+		return nil, "", fmt.Errorf("nothing to reveal")
+	}
+	// map canonical labels of the reveal document to labels used by the base proof
+	_, canonicalIDMap, err := processor.Default().CanonicalizeNQuads(combined.DeskolemizedNQuads)
+	if err != nil {
+		return nil, "", err
+	}
+	verifierLabelMap := make(map[int]int, len(canonicalIDMap))
+	for input, c14nLabel := range canonicalIDMap {
+		var c14nIdx, labelIdx int
+		if _, err := fmt.Sscanf(c14nLabel, "c14n%d", &c14nIdx); err != nil {
+			return nil, "", fmt.Errorf("unexpected canonical label %s", c14nLabel)
+		}
+		if _, err := fmt.Sscanf(labelMap[input], "b%d", &labelIdx); err != nil {
+			return nil, "", fmt.Errorf("unexpected blank node label %s", labelMap[input])
+		}
+		verifierLabelMap[c14nIdx] = labelIdx
+	}
+	derived, err := serializeDerivedProofValue(&derivedProofValue{
+		Proof:              bbsProof,
+		LabelMap:           verifierLabelMap,
+		MandatoryIndexes:   mandatoryIndexes,
+		SelectiveIndexes:   selectiveIndexes,
+		PresentationHeader: presentationHeader,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return revealDoc, derived, nil
+}
+
+// VerifyDocument verifies either a base proof or a derived proof of the document.
+// https://www.w3.org/TR/vc-di-bbs/#verify-derived-proof-bbs-2023
+func (suite *SignatureSuite) VerifyDocument(doc, proof map[string]interface{}, pub []byte,
+	opts ...processor.ProcessorOpts) error {
+	proofValue, _ := proof["proofValue"].(string)
+	proofHash, err := hashProofConfig(doc, proof, opts...)
+	if err != nil {
+		return fmt.Errorf("hash proof configuration: %w", err)
+	}
+	if !isDerivedProofValue(proofValue) {
+		return suite.verifyBaseProof(doc, proofValue, proofHash, pub, opts...)
+	}
+
+	derived, err := parseDerivedProofValue(proofValue)
+	if err != nil {
+		return err
+	}
+	labelMap := make(map[string]string, len(derived.LabelMap))
+	for c14nIdx, labelIdx := range derived.LabelMap {
+		labelMap[fmt.Sprintf("c14n%d", c14nIdx)] = fmt.Sprintf("b%d", labelIdx)
+	}
+	proc := processor.Default()
+	nquads, err := proc.ToDeskolemizedNQuads(doc, opts...)
+	if err != nil {
+		return err
+	}
+	canonical, _, err := proc.CanonicalizeNQuads(nquads)
+	if err != nil {
+		return err
+	}
+	statements, err := relabelBlankNodes(canonical, labelMap)
+	if err != nil {
+		return err
+	}
+	isMandatory := make(map[int]bool, len(derived.MandatoryIndexes))
+	for _, idx := range derived.MandatoryIndexes {
+		if idx < 0 || idx >= len(statements) {
+			return fmt.Errorf("mandatory index %d out of range", idx)
+		}
+		isMandatory[idx] = true
+	}
+	var mandatory, nonMandatory []string
+	for i, statement := range statements {
+		if isMandatory[i] {
+			mandatory = append(mandatory, statement)
+		} else {
+			nonMandatory = append(nonMandatory, statement)
+		}
+	}
+	if len(nonMandatory) != len(derived.SelectiveIndexes) {
+		return fmt.Errorf("disclosed statements not match selective indexes")
+	}
+	header := append(proofHash, hashMandatory(mandatory)...)
+	return suite.Verifier.VerifyProof(pub, derived.Proof, header, derived.PresentationHeader, toMessages(nonMandatory),
+		derived.SelectiveIndexes)
+}
+
+func (suite *SignatureSuite) verifyBaseProof(doc map[string]interface{}, proofValue string, proofHash, pub []byte,
+	opts ...processor.ProcessorOpts) error {
+	base, err := parseBaseProofValue(proofValue)
+	if err != nil {
+		return err
+	}
+	groups, _, err := canonicalizeAndGroup(doc, shuffledLabelMap(base.HMACKey), map[string][]string{
+		"mandatory": base.MandatoryPointers,
+	}, opts...)
+	if err != nil {
+		return err
+	}
+	mandatory := groups["mandatory"]
+	header := append(proofHash, hashMandatory(mandatory.Matching)...)
+	return suite.Verifier.VerifySignature(pub, base.Signature, header, toMessages(mandatory.NonMatching))
+}
+
+// relativeIndexes returns positions of indexes within all, indexes not in all are skipped
+func relativeIndexes(indexes, all []int) []int {
+	ret := make([]int, 0, len(indexes))
+	for _, idx := range indexes {
+		pos := sort.SearchInts(all, idx)
+		if pos < len(all) && all[pos] == idx {
+			ret = append(ret, pos)
+		}
+	}
+	return ret
+}
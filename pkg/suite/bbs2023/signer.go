@@ -0,0 +1,50 @@
+package bbs2023
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/suutaku/go-bbs/pkg/bbs"
+)
+
+// Signer creates BBS signatures and proofs, keys of go-bbs are BLS12-381 keys
+// of the BBS ciphersuite as well
+type Signer struct {
+	pk *bbs.PrivateKey
+}
+
+func NewSigner(pk *bbs.PrivateKey) *Signer {
+	return &Signer{
+		pk: pk,
+	}
+}
+
+// Sign signs the bbs-2023 header and messages
+func (sig *Signer) Sign(header []byte, messages [][]byte) ([]byte, error) {
+	if sig.pk == nil {
+		return nil, fmt.Errorf("private key was empty")
+	}
+	pub, err := sig.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	sk, err := sig.pk.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return bbsSign(new(big.Int).SetBytes(sk), pub, header, messages)
+}
+
+// PublicKey returns bytes of the signer's public key
+func (sig *Signer) PublicKey() ([]byte, error) {
+	if sig.pk == nil {
+		return nil, fmt.Errorf("private key was empty")
+	}
+	return sig.pk.PublicKey().Marshal()
+}
+
+// DeriveProof creates a BBS proof of signature disclosing messages at indexes,
+// presentation header is bound to the proof
+func (sig *Signer) DeriveProof(pubkey, signature, header, presentationHeader []byte, messages [][]byte, indexes []int) ([]byte, error) {
+	return bbsProofGen(pubkey, signature, header, presentationHeader, messages, indexes)
+}
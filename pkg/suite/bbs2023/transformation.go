@@ -0,0 +1,170 @@
+package bbs2023
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/suutaku/go-vc/pkg/processor"
+)
+
+var blankNodeLabel = regexp.MustCompile(`(^| )_:([^\s]+)`)
+
+// group is the result of selecting a set of JSON pointers from a document.
+// Indexes are positions of the statements in the canonical document.
+type group struct {
+	Matching           []string
+	MatchingIndexes    []int
+	NonMatching        []string
+	NonMatchingIndexes []int
+	DeskolemizedNQuads []string
+}
+
+// labelMapFunc creates the blank node label map (input label -> final label)
+// from the input to canonical label map
+type labelMapFunc func(canonicalIDMap map[string]string) map[string]string
+
+// shuffledLabelMap returns a labelMapFunc which randomises labels with a HMAC key,
+// https://www.w3.org/TR/vc-di-bbs/#createshuffledidlabelmapfunction
+func shuffledLabelMap(hmacKey []byte) labelMapFunc {
+	return func(canonicalIDMap map[string]string) map[string]string {
+		hmacIDs := make(map[string]string, len(canonicalIDMap))
+		sorted := make([]string, 0, len(canonicalIDMap))
+		for input, c14nLabel := range canonicalIDMap {
+			mac := hmac.New(sha256.New, hmacKey)
+			mac.Write([]byte(c14nLabel))
+			id := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+			hmacIDs[input] = id
+			sorted = append(sorted, id)
+		}
+		sort.Strings(sorted)
+		position := make(map[string]int, len(sorted))
+		for i, id := range sorted {
+			position[id] = i
+		}
+		ret := make(map[string]string, len(hmacIDs))
+		for input, id := range hmacIDs {
+			ret[input] = fmt.Sprintf("b%d", position[id])
+		}
+		return ret
+	}
+}
+
+// relabelBlankNodes replaces blank node labels of nquads using labelMap and sorts the result
+func relabelBlankNodes(nquads []string, labelMap map[string]string) ([]string, error) {
+	var missing string
+	ret := make([]string, len(nquads))
+	for i, nquad := range nquads {
+		ret[i] = blankNodeLabel.ReplaceAllStringFunc(nquad, func(m string) string {
+			sep := m[:len(m)-len(strings.TrimLeft(m, " "))]
+			label := strings.TrimPrefix(m[len(sep):], "_:")
+			newLabel, ok := labelMap[label]
+			if !ok {
+				missing = label
+				return m
+			}
+			return sep + "_:" + newLabel
+		})
+	}
+	if missing != "" {
+		return nil, fmt.Errorf("blank node _:%s has no label", missing)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// canonicalizeAndGroup canonicalizes document with labelMapFactory labels and
+// splits the canonical statements into groups selected by JSON pointers.
+// https://www.w3.org/TR/vc-di-ecdsa/#canonicalizeandgroup
+func canonicalizeAndGroup(document map[string]interface{}, labelMapFactory labelMapFunc, groupDefinitions map[string][]string,
+	opts ...processor.ProcessorOpts) (map[string]*group, map[string]string, error) {
+	proc := processor.Default()
+	expanded, compacted, err := proc.SkolemizeCompact(document, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	deskolemized, err := proc.ToDeskolemizedNQuads(expanded, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, canonicalIDMap, err := proc.CanonicalizeNQuads(deskolemized)
+	if err != nil {
+		return nil, nil, err
+	}
+	labelMap := labelMapFactory(canonicalIDMap)
+	nquads, err := relabelBlankNodes(deskolemized, labelMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groups := make(map[string]*group, len(groupDefinitions))
+	for name, pointers := range groupDefinitions {
+		g := &group{}
+		selection, err := selectJSONLD(pointers, compacted)
+		if err != nil {
+			return nil, nil, err
+		}
+		selected := make(map[string]bool)
+		if selection != nil {
+			g.DeskolemizedNQuads, err = proc.ToDeskolemizedNQuads(selection, opts...)
+			if err != nil {
+				return nil, nil, err
+			}
+			selectedNQuads, err := relabelBlankNodes(g.DeskolemizedNQuads, labelMap)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, nq := range selectedNQuads {
+				selected[nq] = true
+			}
+		}
+		for i, nq := range nquads {
+			if selected[nq] {
+				g.Matching = append(g.Matching, nq)
+				g.MatchingIndexes = append(g.MatchingIndexes, i)
+			} else {
+				g.NonMatching = append(g.NonMatching, nq)
+				g.NonMatchingIndexes = append(g.NonMatchingIndexes, i)
+			}
+		}
+		groups[name] = g
+	}
+	return groups, labelMap, nil
+}
+
+// hashProofConfig returns the hash of canonical proof configuration, the document context is used
+// when proof has no context of its own
+func hashProofConfig(document, proofOptions map[string]interface{}, opts ...processor.ProcessorOpts) ([]byte, error) {
+	config := make(map[string]interface{}, len(proofOptions))
+	for k, v := range proofOptions {
+		if k != "proofValue" {
+			config[k] = v
+		}
+	}
+	if _, ok := config["@context"]; !ok {
+		config["@context"] = document["@context"]
+	}
+	canonical, err := processor.Default().GetCanonicalDocument(config, opts...)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(canonical)
+	return h[:], nil
+}
+
+func hashMandatory(statements []string) []byte {
+	h := sha256.Sum256([]byte(strings.Join(statements, "")))
+	return h[:]
+}
+
+func toMessages(statements []string) [][]byte {
+	messages := make([][]byte, 0, len(statements))
+	for _, s := range statements {
+		messages = append(messages, []byte(s))
+	}
+	return messages
+}
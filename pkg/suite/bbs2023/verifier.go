@@ -0,0 +1,17 @@
+package bbs2023
+
+type Verifier struct{}
+
+func NewVerifier() *Verifier {
+	return &Verifier{}
+}
+
+// VerifySignature verifies a base proof BBS signature over the header and all messages
+func (verifier *Verifier) VerifySignature(pubkeyBytes, signature, header []byte, messages [][]byte) error {
+	return bbsVerify(pubkeyBytes, signature, header, messages)
+}
+
+// VerifyProof verifies a derived BBS proof over the header and the messages disclosed at indexes
+func (verifier *Verifier) VerifyProof(pubkeyBytes, proof, header, presentationHeader []byte, messages [][]byte, indexes []int) error {
+	return bbsProofVerify(pubkeyBytes, proof, header, presentationHeader, messages, indexes)
+}
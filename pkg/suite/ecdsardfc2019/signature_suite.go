@@ -0,0 +1,118 @@
+package ecdsardfc2019
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+
+	"github.com/suutaku/go-vc/internal/tools"
+	"github.com/suutaku/go-vc/pkg/processor"
+	"github.com/suutaku/go-vc/pkg/suite"
+)
+
+const (
+	cryptosuite   = "ecdsa-rdfc-2019"
+	rdfDataSetAlg = "URDNA2015"
+	// SuiteContext is the JSON-LD context which defines DataIntegrityProof terms
+	SuiteContext = "https://w3id.org/security/data-integrity/v2"
+)
+
+// SignatureSuite implements https://www.w3.org/TR/vc-di-ecdsa/#ecdsa-rdfc-2019
+// P-256 keys are used with SHA-256 and P-384 keys with SHA-384.
+type SignatureSuite struct {
+	*Signer
+	*Verifier
+	curve          elliptic.Curve
+	CompactedProof bool
+}
+
+// NewSignatureSuite creates a suite for the private key's curve, a nil private key
+// creates a suite with verify function only.
+func NewSignatureSuite(priv *ecdsa.PrivateKey, compacted bool) *SignatureSuite {
+	curve := elliptic.P256()
+	if priv != nil {
+		curve = priv.Curve
+	}
+	return &SignatureSuite{
+		Signer:         NewSigner(priv),
+		Verifier:       NewVerifier(),
+		curve:          curve,
+		CompactedProof: compacted,
+	}
+}
+
+// GetCanonicalDocument will return normalized/canonical version of the document
+func (suite *SignatureSuite) GetCanonicalDocument(doc map[string]interface{}, opts ...processor.ProcessorOpts) ([]byte, error) {
+	return processor.NewProcessor(rdfDataSetAlg).GetCanonicalDocument(doc, opts...)
+}
+
+// GetDigest returns document digest
+func (suite *SignatureSuite) GetDigest(doc []byte) []byte {
+	if suite.curve == elliptic.P384() {
+		digest := sha512.Sum384(doc)
+		return digest[:]
+	}
+	digest := sha256.Sum256(doc)
+	return digest[:]
+}
+
+func (suite *SignatureSuite) Alg() string {
+	return cryptosuite
+}
+
+// Cryptosuite returns value of the proof's cryptosuite property
+func (suite *SignatureSuite) Cryptosuite() string {
+	return cryptosuite
+}
+
+func (suite *SignatureSuite) Sign(docByte []byte) ([]byte, error) {
+	return suite.Signer.Sign(suite.GetDigest(docByte))
+}
+
+// Verify will verify signature against public key
+func (suite *SignatureSuite) Verify(pubKeyValue, message, signature, nonce []byte) error {
+	pub, err := ParsePublicKey(pubKeyValue)
+	if err != nil {
+		return err
+	}
+	if pub.Curve != suite.curve {
+		return fmt.Errorf("public key curve %s not match suite curve %s", pub.Curve.Params().Name, suite.curve.Params().Name)
+	}
+	return suite.Verifier.Verify(pubKeyValue, suite.GetDigest(message), signature, nonce)
+}
+
+// BindPublicKey returns a suite using the curve of the public key
+func (s *SignatureSuite) BindPublicKey(pub []byte) (suite.SignatureSuite, error) {
+	pubKey, err := ParsePublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return &SignatureSuite{
+		Signer:         s.Signer,
+		Verifier:       s.Verifier,
+		curve:          pubKey.Curve,
+		CompactedProof: s.CompactedProof,
+	}, nil
+}
+
+// Accept registers this signature suite with the given signature type
+func (suite *SignatureSuite) Accept(sType string) bool {
+	return sType == cryptosuite
+}
+
+// CompactProof indicates weather to compact the proof doc before canonization
+func (suite *SignatureSuite) CompactProof() bool {
+	return suite.CompactedProof
+}
+
+// EncodeProofValue encodes signature as multibase base58-btc
+func (suite *SignatureSuite) EncodeProofValue(signature []byte) (string, error) {
+	return tools.EncodeMultibase(tools.MultibaseBase58BTC, signature)
+}
+
+// DecodeProofValue decodes multibase proofValue
+func (suite *SignatureSuite) DecodeProofValue(proofValue string) ([]byte, error) {
+	return tools.DecodeMultibase(proofValue)
+}
@@ -0,0 +1,37 @@
+package ecdsardfc2019
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+)
+
+type Signer struct {
+	pk *ecdsa.PrivateKey
+}
+
+func NewSigner(pk *ecdsa.PrivateKey) *Signer {
+	return &Signer{
+		pk: pk,
+	}
+}
+
+func (sig *Signer) Alg() string {
+	return cryptosuite
+}
+
+// Sign signs the hash of message, the signature is r||s in fixed size (IEEE P1363)
+func (sig *Signer) Sign(hashed []byte) ([]byte, error) {
+	if sig.pk == nil {
+		return nil, fmt.Errorf("private key was empty")
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, sig.pk, hashed)
+	if err != nil {
+		return nil, err
+	}
+	size := (sig.pk.Curve.Params().BitSize + 7) / 8
+	ret := make([]byte, 2*size)
+	r.FillBytes(ret[:size])
+	s.FillBytes(ret[size:])
+	return ret, nil
+}
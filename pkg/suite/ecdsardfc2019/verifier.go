@@ -0,0 +1,44 @@
+package ecdsardfc2019
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/suutaku/go-vc/pkg/jose"
+)
+
+type Verifier struct{}
+
+func NewVerifier() *Verifier {
+	return &Verifier{}
+}
+
+// Verify verifies r||s signature of the hash against public key
+func (verifier *Verifier) Verify(pubKeyBytes, hashed, signature, nonce []byte) error {
+	pub, err := ParsePublicKey(pubKeyBytes)
+	if err != nil {
+		return err
+	}
+	size := (pub.Curve.Params().BitSize + 7) / 8
+	if len(signature) != 2*size {
+		return fmt.Errorf("invalid ecdsa signature size %d", len(signature))
+	}
+	r := new(big.Int).SetBytes(signature[:size])
+	s := new(big.Int).SetBytes(signature[size:])
+	if !ecdsa.Verify(pub, hashed, r, s) {
+		return fmt.Errorf("ecdsa: invalid signature")
+	}
+	return nil
+}
+
+// ParsePublicKey parses a SEC1 compressed or uncompressed P-256/P-384 public key,
+// optionally prefixed by its multicodec header (p256-pub 0x1200, p384-pub 0x1201).
+func ParsePublicKey(pubKeyBytes []byte) (*ecdsa.PublicKey, error) {
+	if len(pubKeyBytes) > 2 && pubKeyBytes[0] == 0x80 && pubKeyBytes[1] == 0x24 {
+		pubKeyBytes = pubKeyBytes[2:]
+	} else if len(pubKeyBytes) > 2 && pubKeyBytes[0] == 0x81 && pubKeyBytes[1] == 0x24 {
+		pubKeyBytes = pubKeyBytes[2:]
+	}
+	return jose.ParseSEC1PublicKey(pubKeyBytes)
+}
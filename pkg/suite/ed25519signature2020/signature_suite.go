@@ -0,0 +1,75 @@
+package ed25519signature2020
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+
+	"github.com/suutaku/go-vc/internal/tools"
+	"github.com/suutaku/go-vc/pkg/processor"
+)
+
+const (
+	signatureType = "Ed25519Signature2020"
+	rdfDataSetAlg = "URDNA2015"
+	// SuiteContext is the JSON-LD context which defines Ed25519Signature2020 terms
+	SuiteContext = "https://w3id.org/security/suites/ed25519-2020/v1"
+)
+
+// SignatureSuite implements https://w3c.github.io/vc-di-eddsa/#ed25519signature2020
+type SignatureSuite struct {
+	*Signer
+	*Verifier
+	CompactedProof bool
+}
+
+func NewSignatureSuite(priv ed25519.PrivateKey, compacted bool) *SignatureSuite {
+	return &SignatureSuite{
+		Signer:         NewSigner(priv),
+		Verifier:       NewVerifier(),
+		CompactedProof: compacted,
+	}
+}
+
+// GetCanonicalDocument will return normalized/canonical version of the document
+func (suite *SignatureSuite) GetCanonicalDocument(doc map[string]interface{}, opts ...processor.ProcessorOpts) ([]byte, error) {
+	return processor.NewProcessor(rdfDataSetAlg).GetCanonicalDocument(doc, opts...)
+}
+
+// GetDigest returns document digest
+func (suite *SignatureSuite) GetDigest(doc []byte) []byte {
+	digest := sha256.Sum256(doc)
+	return digest[:]
+}
+
+func (suite *SignatureSuite) Alg() string {
+	return signatureType
+}
+
+func (suite *SignatureSuite) Sign(docByte []byte) ([]byte, error) {
+	return suite.Signer.Sign(docByte)
+}
+
+// Verify will verify signature against public key
+func (suite *SignatureSuite) Verify(pubKeyValue, message, signature, nonce []byte) error {
+	return suite.Verifier.Verify(pubKeyValue, message, signature, nonce)
+}
+
+// Accept registers this signature suite with the given signature type
+func (suite *SignatureSuite) Accept(sType string) bool {
+	return sType == signatureType
+}
+
+// CompactProof indicates weather to compact the proof doc before canonization
+func (suite *SignatureSuite) CompactProof() bool {
+	return suite.CompactedProof
+}
+
+// EncodeProofValue encodes signature as multibase base58-btc
+func (suite *SignatureSuite) EncodeProofValue(signature []byte) (string, error) {
+	return tools.EncodeMultibase(tools.MultibaseBase58BTC, signature)
+}
+
+// DecodeProofValue decodes multibase proofValue
+func (suite *SignatureSuite) DecodeProofValue(proofValue string) ([]byte, error) {
+	return tools.DecodeMultibase(proofValue)
+}
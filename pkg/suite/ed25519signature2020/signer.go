@@ -0,0 +1,27 @@
+package ed25519signature2020
+
+import (
+	"crypto/ed25519"
+	"fmt"
+)
+
+type Signer struct {
+	pk ed25519.PrivateKey
+}
+
+func NewSigner(pk ed25519.PrivateKey) *Signer {
+	return &Signer{
+		pk: pk,
+	}
+}
+
+func (sig *Signer) Alg() string {
+	return signatureType
+}
+
+func (sig *Signer) Sign(msg []byte) ([]byte, error) {
+	if len(sig.pk) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("private key was empty")
+	}
+	return ed25519.Sign(sig.pk, msg), nil
+}
@@ -0,0 +1,22 @@
+package ed25519signature2020
+
+import (
+	"crypto/ed25519"
+	"fmt"
+)
+
+type Verifier struct{}
+
+func NewVerifier() *Verifier {
+	return &Verifier{}
+}
+
+func (verifier *Verifier) Verify(pubKeyBytes, doc, signature, nonce []byte) error {
+	if len(pubKeyBytes) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid ed25519 public key size %d", len(pubKeyBytes))
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pubKeyBytes), doc, signature) {
+		return fmt.Errorf("ed25519: invalid signature")
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package jsonwebsignature2020
+
+import (
+	"crypto"
+	"crypto/sha256"
+
+	"github.com/suutaku/go-vc/pkg/processor"
+)
+
+const (
+	signatureType = "JsonWebSignature2020"
+	rdfDataSetAlg = "URDNA2015"
+	// SuiteContext is the JSON-LD context which defines JsonWebSignature2020 terms
+	SuiteContext = "https://w3id.org/security/suites/jws-2020/v1"
+)
+
+// SignatureSuite implements https://w3c-ccg.github.io/lds-jws2020/ with detached JWS proofs,
+// supported algs are ES256, ES384, ES256K, EdDSA and PS256.
+type SignatureSuite struct {
+	*Signer
+	*Verifier
+	CompactedProof bool
+}
+
+// NewSignatureSuite creates a suite for private key (*ecdsa.PrivateKey, *secp256k1.PrivateKey,
+// ed25519.PrivateKey or *rsa.PrivateKey), a nil private key creates a suite with verify function only.
+func NewSignatureSuite(priv crypto.PrivateKey, compacted bool) *SignatureSuite {
+	return &SignatureSuite{
+		Signer:         NewSigner(priv),
+		Verifier:       NewVerifier(),
+		CompactedProof: compacted,
+	}
+}
+
+// GetCanonicalDocument will return normalized/canonical version of the document
+func (suite *SignatureSuite) GetCanonicalDocument(doc map[string]interface{}, opts ...processor.ProcessorOpts) ([]byte, error) {
+	return processor.NewProcessor(rdfDataSetAlg).GetCanonicalDocument(doc, opts...)
+}
+
+// GetDigest returns document digest
+func (suite *SignatureSuite) GetDigest(doc []byte) []byte {
+	digest := sha256.Sum256(doc)
+	return digest[:]
+}
+
+func (suite *SignatureSuite) Alg() string {
+	return signatureType
+}
+
+func (suite *SignatureSuite) Sign(docByte []byte) ([]byte, error) {
+	return suite.Signer.Sign(docByte)
+}
+
+// Verify will verify signature against public key
+func (suite *SignatureSuite) Verify(pubKeyValue, message, signature, nonce []byte) error {
+	return suite.Verifier.Verify(pubKeyValue, message, signature, nonce)
+}
+
+// Accept registers this signature suite with the given signature type
+func (suite *SignatureSuite) Accept(sType string) bool {
+	return sType == signatureType
+}
+
+// CompactProof indicates weather to compact the proof doc before canonization
+func (suite *SignatureSuite) CompactProof() bool {
+	return suite.CompactedProof
+}
@@ -0,0 +1,42 @@
+package jsonwebsignature2020
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/suutaku/go-vc/pkg/jose"
+)
+
+type Signer struct {
+	signer jose.Signer
+	err    error
+}
+
+// NewSigner creates signer of the private key, signing fails when
+// the private key is empty or of an unsupported type
+func NewSigner(pk crypto.PrivateKey) *Signer {
+	if pk == nil {
+		return &Signer{err: fmt.Errorf("private key was empty")}
+	}
+	signer, err := jose.NewSigner(pk)
+	return &Signer{
+		signer: signer,
+		err:    err,
+	}
+}
+
+// JWSAlg returns the alg header value for the signing key
+func (sig *Signer) JWSAlg() string {
+	if sig.signer == nil {
+		return ""
+	}
+	return sig.signer.Alg()
+}
+
+// Sign signs the JWS signing input
+func (sig *Signer) Sign(msg []byte) ([]byte, error) {
+	if sig.err != nil {
+		return nil, sig.err
+	}
+	return sig.signer.Sign(msg)
+}
@@ -0,0 +1,38 @@
+package jsonwebsignature2020
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/suutaku/go-vc/pkg/jose"
+	"github.com/suutaku/go-vc/pkg/proof"
+)
+
+type Verifier struct {
+}
+
+func NewVerifier() *Verifier {
+	return &Verifier{}
+}
+
+// Verify verifies a detached JWS (RFC 7797), msg is the JWS signing input
+// ("header." followed by the unencoded payload) and pub is a JSON encoded JWK.
+func (verifier *Verifier) Verify(pub, msg, signature, nonce []byte) error {
+	jwk, err := jose.ParseJWK(pub)
+	if err != nil {
+		return err
+	}
+	idx := bytes.IndexByte(msg, '.')
+	if idx < 0 {
+		return fmt.Errorf("invalid JWS signing input")
+	}
+	jwtb := proof.NewJwt()
+	if err := jwtb.Parse(string(msg[:idx]) + ".."); err != nil {
+		return err
+	}
+	alg, err := jwtb.ValidateHeader()
+	if err != nil {
+		return err
+	}
+	return jose.Verify(alg, jwk, msg, signature)
+}
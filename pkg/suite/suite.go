@@ -24,3 +24,55 @@ type SignatureSuite interface {
 	// Alg will return algorithm
 	Alg() string
 }
+
+// ProofValueEncoder is implemented by signature suites which encode proofValue
+// with something else than the default base64 encoding (multibase for example).
+type ProofValueEncoder interface {
+	// EncodeProofValue will encode signature to proofValue
+	EncodeProofValue(signature []byte) (string, error)
+
+	// DecodeProofValue will decode proofValue to signature
+	DecodeProofValue(proofValue string) ([]byte, error)
+}
+
+// DataIntegritySuite is implemented by Data Integrity cryptosuites, which all share
+// the DataIntegrityProof proof type and are told apart by the cryptosuite property.
+type DataIntegritySuite interface {
+	// Cryptosuite returns value of the proof's cryptosuite property
+	Cryptosuite() string
+}
+
+// PublicKeyBinder is implemented by signature suites whose digest depends on
+// the verification key (the curve of an ECDSA key for example).
+type PublicKeyBinder interface {
+	// BindPublicKey returns the suite to use for verifying with the given public key
+	BindPublicKey(pub []byte) (SignatureSuite, error)
+}
+
+// JWSSigner is implemented by signature suites producing detached JWS proofs.
+type JWSSigner interface {
+	// JWSAlg returns the alg header value for the signing key
+	JWSAlg() string
+}
+
+// DocumentSigner is implemented by signature suites which transform and sign the whole
+// document themselves, like selective disclosure cryptosuites (bbs-2023 for example).
+type DocumentSigner interface {
+	// SignDocument returns proofValue of the document (without proof) for the proof options,
+	// statements selected by mandatoryPointers will always be disclosed
+	SignDocument(doc, proofOptions map[string]interface{}, mandatoryPointers []string, opts ...processor.ProcessorOpts) (string, error)
+}
+
+// DocumentVerifier is implemented by signature suites which verify the whole document themselves.
+type DocumentVerifier interface {
+	// VerifyDocument verifies proof of the document (without proof) against public key
+	VerifyDocument(doc, proof map[string]interface{}, pub []byte, opts ...processor.ProcessorOpts) error
+}
+
+// ProofDeriver is implemented by selective disclosure suites which derive a proof from a base proof.
+type ProofDeriver interface {
+	// DeriveDocumentProof returns the document revealing statements selected by selectivePointers
+	// and the mandatory pointers of base proof, together with the derived proofValue
+	DeriveDocumentProof(doc, proof map[string]interface{}, selectivePointers []string, presentationHeader []byte,
+		opts ...processor.ProcessorOpts) (map[string]interface{}, string, error)
+}
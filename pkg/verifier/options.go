@@ -0,0 +1,145 @@
+package verifier
+
+import (
+	"time"
+
+	"github.com/suutaku/go-vc/pkg/credential"
+	"github.com/suutaku/go-vc/pkg/processor"
+	"github.com/suutaku/go-vc/pkg/status"
+	"github.com/suutaku/go-vc/pkg/suite"
+	"github.com/suutaku/go-vc/pkg/suite/bbs2023"
+	"github.com/suutaku/go-vc/pkg/suite/bbsblssignature2020"
+	"github.com/suutaku/go-vc/pkg/suite/bbsblssignatureproof2020"
+	"github.com/suutaku/go-vc/pkg/suite/ecdsardfc2019"
+	"github.com/suutaku/go-vc/pkg/suite/ed25519signature2020"
+	"github.com/suutaku/go-vc/pkg/suite/jsonwebsignature2020"
+)
+
+// RelationshipChecker checks that verification method vm is authorised for the proof purpose
+// (verification relationship) by its controller
+type RelationshipChecker func(vm, purpose string) error
+
+// SchemaValidator validates a credential against its credentialSchema
+type SchemaValidator func(cred *credential.Credential) error
+
+// verifierOpts holds options for Verifier.
+type verifierOpts struct {
+	signatureSuites map[string]suite.SignatureSuite
+	processorOpts   []processor.ProcessorOpts
+	clock           func() time.Time
+	skew            time.Duration
+	purpose         string
+	statusResolver  status.StatusListResolver
+	relationship    RelationshipChecker
+	schema          SchemaValidator
+	skipStatus      bool
+	skipSchema      bool
+}
+
+// VerifierOption are the options for Verifier.
+type VerifierOption func(opts *verifierOpts)
+
+// WithSignatureSuites option sets the suites verifying proofs, every supported suite without private key by default.
+func WithSignatureSuites(suites ...suite.SignatureSuite) VerifierOption {
+	return func(opts *verifierOpts) {
+		opts.signatureSuites = make(map[string]suite.SignatureSuite)
+		for _, v := range suites {
+			opts.signatureSuites[v.Alg()] = v
+		}
+	}
+}
+
+// WithProcessorOptions will parse to json-ld processor
+func WithProcessorOptions(processorOpts ...processor.ProcessorOpts) VerifierOption {
+	return func(opts *verifierOpts) {
+		opts.processorOpts = append(opts.processorOpts, processorOpts...)
+	}
+}
+
+// WithClock option sets the current time of validity checks, time.Now by default.
+func WithClock(clock func() time.Time) VerifierOption {
+	return func(opts *verifierOpts) {
+		opts.clock = clock
+	}
+}
+
+// WithClockSkew option sets the tolerated clock difference with the issuer for validity checks.
+func WithClockSkew(skew time.Duration) VerifierOption {
+	return func(opts *verifierOpts) {
+		opts.skew = skew
+	}
+}
+
+// WithProofPurpose option sets the expected proofPurpose, assertionMethod by default.
+func WithProofPurpose(purpose string) VerifierOption {
+	return func(opts *verifierOpts) {
+		opts.purpose = purpose
+	}
+}
+
+// WithStatusListResolver option checks credentialStatus entries in lists dereferenced by r.
+func WithStatusListResolver(r status.StatusListResolver) VerifierOption {
+	return func(opts *verifierOpts) {
+		opts.statusResolver = r
+	}
+}
+
+// WithRelationshipChecker option checks the verification relationship of proof verification methods,
+// see resolver.RelationshipCheckerOf.
+func WithRelationshipChecker(checker RelationshipChecker) VerifierOption {
+	return func(opts *verifierOpts) {
+		opts.relationship = checker
+	}
+}
+
+// WithSchemaValidator option validates credentials against their credentialSchema.
+func WithSchemaValidator(validator SchemaValidator) VerifierOption {
+	return func(opts *verifierOpts) {
+		opts.schema = validator
+	}
+}
+
+// WithoutStatusCheck option skips credentialStatus entries when no status list resolver is set,
+// they fail the verification by default.
+func WithoutStatusCheck() VerifierOption {
+	return func(opts *verifierOpts) {
+		opts.skipStatus = true
+	}
+}
+
+// WithoutSchemaCheck option skips credentialSchema when no schema validator is set,
+// it fails the verification by default.
+func WithoutSchemaCheck() VerifierOption {
+	return func(opts *verifierOpts) {
+		opts.skipSchema = true
+	}
+}
+
+// VerificationSuites returns all supported signature suites without private key, they verify proofs only
+func VerificationSuites() []suite.SignatureSuite {
+	return []suite.SignatureSuite{
+		bbsblssignature2020.NewSignatureSuite(nil, false),
+		bbsblssignatureproof2020.NewSignatureSuite(nil, false),
+		bbs2023.NewSignatureSuite(nil, false),
+		ed25519signature2020.NewSignatureSuite(nil, false),
+		ecdsardfc2019.NewSignatureSuite(nil, false),
+		jsonwebsignature2020.NewSignatureSuite(nil, false),
+	}
+}
+
+func prepareVerifierOpts(opts []VerifierOption) *verifierOpts {
+	ret := &verifierOpts{
+		clock:   time.Now,
+		purpose: "assertionMethod",
+	}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	if ret.signatureSuites == nil {
+		ret.signatureSuites = make(map[string]suite.SignatureSuite)
+		for _, s := range VerificationSuites() {
+			ret.signatureSuites[s.Alg()] = s
+		}
+	}
+	return ret
+}
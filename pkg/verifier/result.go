@@ -0,0 +1,92 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/suutaku/go-vc/pkg/status"
+)
+
+// Names of the checks of a VerificationResult
+const (
+	CheckProof        = "proof"
+	CheckProofPurpose = "proofPurpose"
+	CheckValidity     = "validity"
+	CheckStatus       = "status"
+	CheckSchema       = "schema"
+)
+
+// Outcome is the outcome of a check
+type Outcome string
+
+const (
+	OutcomePassed  Outcome = "passed"
+	OutcomeFailed  Outcome = "failed"
+	OutcomeSkipped Outcome = "skipped"
+)
+
+// CheckResult is the outcome of one check with the reasons of a failure and the warnings
+type CheckResult struct {
+	Check    string   `json:"check"`
+	Outcome  Outcome  `json:"outcome"`
+	Errors   []string `json:"errors,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func (cr *CheckResult) fail(format string, args ...interface{}) {
+	cr.Outcome = OutcomeFailed
+	cr.Errors = append(cr.Errors, fmt.Sprintf(format, args...))
+}
+
+func (cr *CheckResult) warn(format string, args ...interface{}) {
+	cr.Warnings = append(cr.Warnings, fmt.Sprintf(format, args...))
+}
+
+// VerificationResult lists every check run by a Verifier, the credential is verified when no check failed
+type VerificationResult struct {
+	Verified bool           `json:"verified"`
+	Checks   []*CheckResult `json:"checks"`
+	// Status holds the status of every credentialStatus entry
+	Status []*status.Result `json:"-"`
+}
+
+// Check returns the result of the named check, nil if it was not run
+func (vr *VerificationResult) Check(name string) *CheckResult {
+	for _, cr := range vr.Checks {
+		if cr.Check == name {
+			return cr
+		}
+	}
+	return nil
+}
+
+// Errors returns the errors of the failed checks prefixed by the check name
+func (vr *VerificationResult) Errors() []string {
+	ret := make([]string, 0)
+	for _, cr := range vr.Checks {
+		for _, e := range cr.Errors {
+			ret = append(ret, cr.Check+": "+e)
+		}
+	}
+	return ret
+}
+
+// Warnings returns the warnings of all checks prefixed by the check name
+func (vr *VerificationResult) Warnings() []string {
+	ret := make([]string, 0)
+	for _, cr := range vr.Checks {
+		for _, w := range cr.Warnings {
+			ret = append(ret, cr.Check+": "+w)
+		}
+	}
+	return ret
+}
+
+// Err returns an error joining the errors of the failed checks, nil if the credential is verified
+func (vr *VerificationResult) Err() error {
+	if vr.Verified {
+		return nil
+	}
+	return errors.New(strings.Join(vr.Errors(), "; "))
+}
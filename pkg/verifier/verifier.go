@@ -0,0 +1,195 @@
+// Package verifier verifies credentials and reports the outcome of every check in a VerificationResult.
+package verifier
+
+import (
+	"strings"
+
+	"github.com/suutaku/go-vc/pkg/credential"
+	"github.com/suutaku/go-vc/pkg/proof"
+	"github.com/suutaku/go-vc/pkg/resolver"
+	"github.com/suutaku/go-vc/pkg/status"
+)
+
+// Verifier runs proof, proof purpose, validity period, status and schema checks of credentials.
+type Verifier struct {
+	pubResolver resolver.PublicKeyResolver
+	options     *verifierOpts
+}
+
+// NewVerifier creates a verifier resolving proof verification methods with pubResolver. Verification
+// relationships are checked with the DID documents of pubResolver when it is a resolver.DIDResolver.
+func NewVerifier(pubResolver resolver.PublicKeyResolver, opts ...VerifierOption) *Verifier {
+	options := prepareVerifierOpts(opts)
+	if r, ok := pubResolver.(resolver.DIDResolver); ok && options.relationship == nil {
+		options.relationship = resolver.RelationshipCheckerOf(r)
+	}
+	return &Verifier{
+		pubResolver: pubResolver,
+		options:     options,
+	}
+}
+
+// Verify runs every check of the credential, checks which can't be run are skipped with a warning
+func (v *Verifier) Verify(cred *credential.Credential) *VerificationResult {
+	ret := &VerificationResult{}
+	ret.Checks = append(ret.Checks,
+		v.checkProof(cred),
+		v.checkProofPurpose(cred),
+		v.checkValidity(cred),
+		v.checkStatus(cred, ret),
+		v.checkSchema(cred),
+	)
+	ret.Verified = true
+	for _, cr := range ret.Checks {
+		if cr.Outcome == OutcomeFailed {
+			ret.Verified = false
+		}
+	}
+	return ret
+}
+
+// checkProof verifies every proof of the credential
+func (v *Verifier) checkProof(cred *credential.Credential) *CheckResult {
+	ret := &CheckResult{Check: CheckProof, Outcome: OutcomePassed}
+	if cred.Proof == nil {
+		ret.fail("proof was empty")
+		return ret
+	}
+	proofs, err := credential.GetProofs(cred.Proof)
+	if err != nil {
+		ret.fail("%v", err)
+		return ret
+	}
+	for _, p := range proofs {
+		single := *cred
+		single.Proof = p
+		if err := single.VerifyProof(v.options.signatureSuites, v.pubResolver, v.options.processorOpts...); err != nil {
+			ret.fail("%s: %v", proof.NewProofFromMap(p).SuiteName(), err)
+		}
+	}
+	return ret
+}
+
+// checkProofPurpose checks the proofPurpose of every proof and that its verification method belongs to the issuer
+func (v *Verifier) checkProofPurpose(cred *credential.Credential) *CheckResult {
+	ret := &CheckResult{Check: CheckProofPurpose, Outcome: OutcomePassed}
+	proofs, err := credential.GetProofs(cred.Proof)
+	if err != nil {
+		ret.Outcome = OutcomeSkipped
+		ret.warn("no proof to check")
+		return ret
+	}
+	for _, pm := range proofs {
+		p := proof.NewProofFromMap(pm)
+		if p.ProofPurpose != v.options.purpose {
+			ret.fail("proof purpose %s, expected %s", p.ProofPurpose, v.options.purpose)
+		}
+		vm := p.VerificationMethod
+		if vm == "" {
+			vm = p.Creator
+		}
+		if controller := strings.SplitN(vm, "#", 2)[0]; cred.IssuerID() != "" && controller != cred.IssuerID() {
+			ret.fail("verification method %s is not controlled by issuer %s", vm, cred.IssuerID())
+			continue
+		}
+		if v.options.relationship == nil {
+			ret.warn("verification relationship of %s not checked", vm)
+			continue
+		}
+		if err := v.options.relationship(vm, p.ProofPurpose); err != nil {
+			ret.fail("verification method %s: %v", vm, err)
+		}
+	}
+	return ret
+}
+
+// checkValidity checks the validity period, or issuanceDate and expirationDate, with the clock skew tolerance
+func (v *Verifier) checkValidity(cred *credential.Credential) *CheckResult {
+	ret := &CheckResult{Check: CheckValidity, Outcome: OutcomePassed}
+	if from, until := cred.ValidityPeriod(); from == nil && until == nil {
+		ret.Outcome = OutcomeSkipped
+		ret.warn("credential has no validity period")
+		return ret
+	}
+	if err := cred.CheckValidity(v.options.clock(), v.options.skew); err != nil {
+		ret.fail("%v", err)
+	}
+	return ret
+}
+
+// checkStatus checks every credentialStatus entry, revoked and suspended credentials fail. Entries fail
+// without status list resolver unless WithoutStatusCheck is set.
+func (v *Verifier) checkStatus(cred *credential.Credential, result *VerificationResult) *CheckResult {
+	ret := &CheckResult{Check: CheckStatus, Outcome: OutcomePassed}
+	entries, err := credential.GetStatusEntries(cred.Status)
+	if err != nil {
+		ret.fail("%v", err)
+		return ret
+	}
+	if len(entries) == 0 {
+		ret.Outcome = OutcomeSkipped
+		return ret
+	}
+	if v.options.statusResolver == nil {
+		if v.options.skipStatus {
+			ret.Outcome = OutcomeSkipped
+			ret.warn("no status list resolver, status not checked")
+		} else {
+			ret.fail("no status list resolver, status not checked")
+		}
+		return ret
+	}
+	for _, entry := range entries {
+		listID, _ := entry["statusListCredential"].(string)
+		list, err := v.options.statusResolver.Resolve(listID)
+		if err != nil {
+			ret.fail("%v", err)
+			continue
+		}
+		if list.IssuerID() != cred.IssuerID() {
+			ret.fail("status list issuer %s not matched credential issuer %s", list.IssuerID(), cred.IssuerID())
+			continue
+		}
+		res, err := status.CheckStatus(entry, list)
+		if err != nil {
+			ret.fail("%v", err)
+			continue
+		}
+		result.Status = append(result.Status, res)
+		switch {
+		case !res.Set:
+		case res.Purpose == status.StatusPurposeRevocation:
+			ret.fail("credential is revoked")
+		case res.Purpose == status.StatusPurposeSuspension:
+			ret.fail("credential is suspended")
+		case res.Message != "":
+			ret.warn("%s status: %s", res.Purpose, res.Message)
+		default:
+			ret.warn("%s status: %#x", res.Purpose, res.Value)
+		}
+	}
+	return ret
+}
+
+// checkSchema validates the credential against its credentialSchema, which fails without schema validator
+// unless WithoutSchemaCheck is set
+func (v *Verifier) checkSchema(cred *credential.Credential) *CheckResult {
+	ret := &CheckResult{Check: CheckSchema, Outcome: OutcomePassed}
+	if cred.Schema == nil {
+		ret.Outcome = OutcomeSkipped
+		return ret
+	}
+	if v.options.schema == nil {
+		if v.options.skipSchema {
+			ret.Outcome = OutcomeSkipped
+			ret.warn("no schema validator, credentialSchema not checked")
+		} else {
+			ret.fail("no schema validator, credentialSchema not checked")
+		}
+		return ret
+	}
+	if err := v.options.schema(cred); err != nil {
+		ret.fail("%v", err)
+	}
+	return ret
+}
@@ -0,0 +1,159 @@
+package verifier
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suutaku/go-vc/pkg/common"
+	"github.com/suutaku/go-vc/pkg/credential"
+	"github.com/suutaku/go-vc/pkg/ldcontext"
+	"github.com/suutaku/go-vc/pkg/processor"
+	"github.com/suutaku/go-vc/pkg/proof"
+	"github.com/suutaku/go-vc/pkg/resolver"
+	"github.com/suutaku/go-vc/pkg/status"
+	"github.com/suutaku/go-vc/pkg/suite/ed25519signature2020"
+	"github.com/suutaku/go-vc/test"
+)
+
+const (
+	issuerDID = "did:example:489398593"
+	listURL   = "https://example.com/status/0"
+)
+
+// listResolver resolves status lists from memory
+type listResolver map[string]*credential.Credential
+
+func (lr listResolver) Resolve(url string) (*credential.Credential, error) {
+	if list, ok := lr[url]; ok {
+		return list, nil
+	}
+	return nil, fmt.Errorf("status list %s not found", url)
+}
+
+// offlineProcessorOptions returns processor options which load the test contexts without network
+func offlineProcessorOptions(t *testing.T) []processor.ProcessorOpts {
+	ctxBytes, err := test.GetTestResource("citizenship-v1.jsonld")
+	require.NoError(t, err)
+	loader, err := ldcontext.NewDocumentLoader(ldcontext.WithoutNetwork(),
+		ldcontext.WithContext("https://w3id.org/citizenship/v1", ctxBytes))
+	require.NoError(t, err)
+	return []processor.ProcessorOpts{processor.WithValidateRDF(), processor.WithDocumentLoader(loader)}
+}
+
+func signedTestCredential(t *testing.T, entry *status.StatusEntry) (*credential.Credential, resolver.PublicKeyResolver) {
+	priv := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	pubResv := resolver.NewTestPublicKeyResolver(&resolver.PublicKey{
+		Type:  "Ed25519VerificationKey2020",
+		Value: priv.Public().(ed25519.PublicKey),
+	}, nil)
+	b, err := test.GetTestResource("vc-json-doc-ed25519.json")
+	require.NoError(t, err)
+	cred := credential.NewCredential()
+	assert.NoError(t, cred.FromBytes(b))
+	cred.Context = append(cred.Context, "https://w3id.org/vc/status-list/2021/v1")
+	cred.Status = entry.ToMap()
+	cred.ValidFrom = &common.FormatedTime{Time: time.Date(2019, 12, 3, 12, 19, 52, 0, time.UTC)}
+	cred.ValidUntil = &common.FormatedTime{Time: time.Date(2029, 12, 3, 12, 19, 52, 0, time.UTC)}
+	err = cred.AddLinkedDataProof(ed25519signature2020.NewSignatureSuite(priv, false), &proof.LinkedDataProofContext{
+		SignatureType:           "Ed25519Signature2020",
+		SignatureRepresentation: proof.SignatureProofValue,
+		VerificationMethod:      issuerDID + "#owner",
+	}, offlineProcessorOptions(t)...)
+	assert.NoError(t, err)
+	return cred, pubResv
+}
+
+func TestVerifier(t *testing.T) {
+	m := status.NewStatusListManager(status.NewMemoryStorage(), "https://example.com/status",
+		status.WithListSize(64), status.WithIssuer(issuerDID))
+	entry, err := m.Allocate()
+	require.NoError(t, err)
+	list, err := m.StatusCredential(listURL)
+	require.NoError(t, err)
+	lists := listResolver{listURL: list}
+
+	cred, pubResv := signedTestCredential(t, entry)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	opts := []VerifierOption{
+		WithClock(func() time.Time { return now }),
+		WithStatusListResolver(lists),
+		WithProcessorOptions(offlineProcessorOptions(t)...),
+	}
+	result := NewVerifier(pubResv, opts...).Verify(cred)
+	assert.True(t, result.Verified, result.Errors())
+	assert.NoError(t, result.Err())
+	for _, name := range []string{CheckProof, CheckProofPurpose, CheckValidity, CheckStatus} {
+		assert.Equal(t, OutcomePassed, result.Check(name).Outcome, name)
+	}
+	assert.Equal(t, OutcomeSkipped, result.Check(CheckSchema).Outcome)
+	assert.Len(t, result.Status, 1)
+	assert.Contains(t, result.Warnings(), "proofPurpose: verification relationship of "+issuerDID+"#owner not checked")
+
+	// validity period with clock skew
+	now = cred.ValidUntil.Add(time.Minute)
+	result = NewVerifier(pubResv, opts...).Verify(cred)
+	assert.False(t, result.Verified)
+	assert.Equal(t, OutcomeFailed, result.Check(CheckValidity).Outcome)
+	result = NewVerifier(pubResv, append(opts, WithClockSkew(5*time.Minute))...).Verify(cred)
+	assert.True(t, result.Verified, result.Errors())
+	now = cred.ValidFrom.Add(-time.Minute)
+	result = NewVerifier(pubResv, opts...).Verify(cred)
+	assert.Equal(t, OutcomeFailed, result.Check(CheckValidity).Outcome)
+	now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// revoked
+	assert.NoError(t, m.Revoke(entry))
+	lists[listURL], err = m.StatusCredential(listURL)
+	assert.NoError(t, err)
+	result = NewVerifier(pubResv, opts...).Verify(cred)
+	assert.False(t, result.Verified)
+	assert.Equal(t, []string{"status: credential is revoked"}, result.Errors())
+	assert.EqualError(t, result.Err(), "status: credential is revoked")
+	assert.NoError(t, m.SetStatus(entry, false))
+	lists[listURL], err = m.StatusCredential(listURL)
+	assert.NoError(t, err)
+
+	// status entries fail unless explicitly skipped without status list resolver
+	unchecked := []VerifierOption{opts[0], opts[2]}
+	result = NewVerifier(pubResv, unchecked...).Verify(cred)
+	assert.False(t, result.Verified)
+	assert.Equal(t, OutcomeFailed, result.Check(CheckStatus).Outcome)
+	result = NewVerifier(pubResv, append(unchecked, WithoutStatusCheck())...).Verify(cred)
+	assert.True(t, result.Verified, result.Errors())
+	assert.Equal(t, OutcomeSkipped, result.Check(CheckStatus).Outcome)
+
+	// proof purpose and verification relationship
+	result = NewVerifier(pubResv, append(opts, WithProofPurpose("authentication"))...).Verify(cred)
+	assert.Equal(t, OutcomeFailed, result.Check(CheckProofPurpose).Outcome)
+	result = NewVerifier(pubResv, append(opts, WithRelationshipChecker(func(vm, purpose string) error {
+		return fmt.Errorf("%s is not an %s method", vm, purpose)
+	}))...).Verify(cred)
+	assert.Equal(t, OutcomeFailed, result.Check(CheckProofPurpose).Outcome)
+
+	// schema
+	cred.Schema = map[string]interface{}{"id": "https://example.com/schema.json", "type": "JsonSchema"}
+	result = NewVerifier(pubResv, opts...).Verify(cred)
+	assert.False(t, result.Verified)
+	assert.Equal(t, OutcomeFailed, result.Check(CheckSchema).Outcome)
+	result = NewVerifier(pubResv, append(opts, WithoutSchemaCheck())...).Verify(cred)
+	assert.Equal(t, OutcomeSkipped, result.Check(CheckSchema).Outcome)
+	assert.NotEmpty(t, result.Check(CheckSchema).Warnings)
+	result = NewVerifier(pubResv, append(opts, WithSchemaValidator(func(cred *credential.Credential) error {
+		return fmt.Errorf("birthDate is required")
+	}))...).Verify(cred)
+	assert.Equal(t, OutcomeFailed, result.Check(CheckSchema).Outcome)
+	// credentialSchema is signed too
+	assert.Equal(t, OutcomeFailed, result.Check(CheckProof).Outcome)
+	cred.Schema = nil
+
+	// tampered credential
+	cred.Subject.(map[string]interface{})["givenName"] = "JANE"
+	result = NewVerifier(pubResv, opts...).Verify(cred)
+	assert.False(t, result.Verified)
+	assert.Equal(t, OutcomeFailed, result.Check(CheckProof).Outcome)
+	assert.Equal(t, OutcomePassed, result.Check(CheckStatus).Outcome)
+}